@@ -29,6 +29,9 @@ type SessionSpec struct {
 	Route Route `json:"route,omitempty"`
 	// Who should participate in the given session
 	Refs []Ref `json:"ref,omitempty"`
+	// Name of the pod label used to identify the version subsets, e.g. version or app.kubernetes.io/version.
+	// Detected from the existing DestinationRule subsets or configured on the operator level if not provided.
+	SubsetLabel string `json:"subsetLabel,omitempty"`
 }
 
 // Ref defines how to target a single Deployment or DeploymentConfig.
@@ -36,7 +39,7 @@ type SessionSpec struct {
 type Ref struct {
 	// Deployment or DeploymentConfig name, could optionally contain [Kind/]Name to be specific
 	Name string `json:"name,omitempty"`
-	// How this deployment should be handled, e.g. telepresence or prepared-image
+	// How this deployment should be handled, e.g. telepresence or prepared-image. Multiple comma separated strategies are applied in the given order, e.g. prepared-image,extra-env
 	Strategy string `json:"strategy,omitempty"`
 	// Additional arguments to the given strategy
 	Args map[string]string `json:"args,omitempty"`
@@ -82,6 +85,8 @@ type SessionStatus struct {
 // +k8s:openapi-gen=true
 type RefStatus struct {
 	Ref `json:",inline"`
+	// The pod label used to identify the version subsets of the targets
+	SubsetLabel string `json:"subsetLabel,omitempty"`
 	// A lit of the Object used as source
 	Targets []*LabeledRefResource `json:"targets,omitempty"`
 	// +optional
@@ -8,12 +8,24 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"github.com/maistra/istio-workspace/pkg/cmd"
+	"github.com/maistra/istio-workspace/pkg/cmd/agent"
 	"github.com/maistra/istio-workspace/pkg/cmd/completion"
+	"github.com/maistra/istio-workspace/pkg/cmd/config"
 	"github.com/maistra/istio-workspace/pkg/cmd/create"
+	"github.com/maistra/istio-workspace/pkg/cmd/debug"
 	"github.com/maistra/istio-workspace/pkg/cmd/delete"
 	"github.com/maistra/istio-workspace/pkg/cmd/develop"
+	"github.com/maistra/istio-workspace/pkg/cmd/doctor"
 	"github.com/maistra/istio-workspace/pkg/cmd/execute"
+	"github.com/maistra/istio-workspace/pkg/cmd/install"
+	"github.com/maistra/istio-workspace/pkg/cmd/join"
+	"github.com/maistra/istio-workspace/pkg/cmd/leave"
+	"github.com/maistra/istio-workspace/pkg/cmd/list"
 	"github.com/maistra/istio-workspace/pkg/cmd/serve"
+	"github.com/maistra/istio-workspace/pkg/cmd/status"
+	"github.com/maistra/istio-workspace/pkg/cmd/strategy"
+	"github.com/maistra/istio-workspace/pkg/cmd/template"
+	"github.com/maistra/istio-workspace/pkg/cmd/uninstall"
 	"github.com/maistra/istio-workspace/pkg/cmd/version"
 	"github.com/maistra/istio-workspace/pkg/log"
 )
@@ -34,11 +46,23 @@ func main() {
 	rootCmd := cmd.NewCmd()
 	rootCmd.AddCommand(
 		version.NewCmd(),
+		agent.NewCmd(),
 		create.NewCmd(),
+		join.NewCmd(),
+		leave.NewCmd(),
 		delete.NewCmd(),
 		develop.NewCmd(),
+		debug.NewCmd(),
+		list.NewCmd(),
+		status.NewCmd(),
+		doctor.NewCmd(),
+		install.NewCmd(),
+		uninstall.NewCmd(),
 		execute.NewCmd(),
 		serve.NewCmd(),
+		strategy.NewCmd(),
+		template.NewCmd(),
+		config.NewCmd(),
 		completion.NewCmd(),
 	)
 
@@ -10,12 +10,10 @@ import (
 
 	istiov1alpha1 "github.com/maistra/istio-workspace/api/maistra/v1alpha1"
 	"github.com/maistra/istio-workspace/pkg/model"
+	"github.com/maistra/istio-workspace/pkg/routing"
 )
 
 const (
-	// DefaultRouteHeaderName holds the name of the Header used to route traffic if no Route is provided.
-	DefaultRouteHeaderName = "x-workspace-route"
-
 	// RouteStrategyHeader holds the Route Type keyword for a Header based Route strategy.
 	RouteStrategyHeader = "header"
 )
@@ -28,6 +26,7 @@ func ConvertModelRefToAPIStatus(ref model.Ref, session *istiov1alpha1.Session) {
 			Strategy: ref.Strategy,
 			Args:     ref.Args,
 		},
+		SubsetLabel: ref.SubsetLabel,
 	}
 	for _, t := range ref.Targets {
 		target := t
@@ -115,6 +114,9 @@ func ConvertAPIStatusesToModelRefs(session istiov1alpha1.Session) []*model.Ref {
 func ConvertAPIStatusToModelRef(session istiov1alpha1.Session, ref *model.Ref) {
 	for _, statusRef := range session.Status.Refs {
 		if statusRef.Name == ref.KindName.String() {
+			if ref.SubsetLabel == "" {
+				ref.SubsetLabel = statusRef.SubsetLabel
+			}
 			for _, statusTarget := range statusRef.Targets {
 				timeStamp := time.Time{}
 				if statusTarget.LastTransitionTime != nil {
@@ -175,7 +177,7 @@ func ConvertAPIRouteToModelRoute(session *istiov1alpha1.Session) model.Route {
 	if session.Spec.Route.Type == "" {
 		return model.Route{
 			Type: RouteStrategyHeader,
-			Name: DefaultRouteHeaderName,
+			Name: routing.DefaultRouteHeaderName,
 			//Value: uuid.New().String(),
 			Value: session.Name,
 		}
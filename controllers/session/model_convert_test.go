@@ -10,6 +10,7 @@ import (
 	"github.com/maistra/istio-workspace/api/maistra/v1alpha1"
 	"github.com/maistra/istio-workspace/controllers/session"
 	"github.com/maistra/istio-workspace/pkg/model"
+	"github.com/maistra/istio-workspace/pkg/routing"
 )
 
 var _ = Describe("Basic model conversion", func() {
@@ -272,7 +273,7 @@ var _ = Describe("Basic model conversion", func() {
 
 			It("should default if no route defined", func() {
 				Expect(route.Type).To(Equal(session.RouteStrategyHeader))
-				Expect(route.Name).To(Equal(session.DefaultRouteHeaderName))
+				Expect(route.Name).To(Equal(routing.DefaultRouteHeaderName))
 				Expect(route.Value).To(Equal(sess.ObjectMeta.Name))
 			})
 		})
@@ -31,6 +31,9 @@ import (
 const (
 	// Finalizer defines the Finalizer name owned by the Session reconciler.
 	Finalizer = "finalizers.istio.workspace.session"
+
+	// SubsetLabelEnvVar holds the name of the env variable used to configure the operator wide pod label identifying version subsets.
+	SubsetLabelEnvVar = "SUBSET_LABEL"
 )
 
 var (
@@ -39,20 +42,25 @@ var (
 	}
 )
 
+// DefaultEngine returns the template engine based on the built-in strategies, or the ones found in TEMPLATE_PATH if set.
+// Additional strategies can be loaded at runtime, see ReconcileStrategy.
+func DefaultEngine() *template.ReloadableEngine {
+	return template.NewReloadableEngine(template.LoadPatches(template.StrategiesPath()))
+}
+
 // DefaultManipulators contains the default config for the reconciler.
 func DefaultManipulators() Manipulators {
-	var engine template.Engine
-	if path, exists := os.LookupEnv(template.TemplatePath); exists {
-		engine = template.NewDefaultPatchEngine(path)
-	} else {
-		engine = template.NewDefaultEngine()
-	}
+	return NewManipulators(DefaultEngine())
+}
 
+// NewManipulators contains the default config for the reconciler using the given template engine.
+func NewManipulators(engine template.Engine) Manipulators {
 	return Manipulators{
 		Locators: []model.Locator{
 			k8s.DeploymentLocator,
 			openshift.DeploymentConfigLocator,
 			k8s.ServiceLocator,
+			istio.SubsetLabelLocator,
 			istio.VirtualServiceGatewayLocator,
 		},
 		Handlers: []model.Manipulator{
@@ -67,13 +75,25 @@ func DefaultManipulators() Manipulators {
 
 // Add creates a new Session Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
+//
+// The Strategy Controller sharing the template engine with the Session Controller is added as well.
 func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+	engine := DefaultEngine()
+	if err := add(mgr, newReconciler(mgr, engine)); err != nil {
+		return err
+	}
+
+	return addStrategy(mgr, NewStrategyReconciler(mgr.GetClient(), engine))
 }
 
 // newReconciler returns a new reconcile.Reconciler.
-func newReconciler(mgr manager.Manager) *ReconcileSession {
-	return &ReconcileSession{client: mgr.GetClient(), scheme: mgr.GetScheme(), manipulators: DefaultManipulators()}
+func newReconciler(mgr manager.Manager, engine template.Engine) *ReconcileSession {
+	return &ReconcileSession{
+		client:       mgr.GetClient(),
+		scheme:       mgr.GetScheme(),
+		manipulators: NewManipulators(engine),
+		subsetLabel:  os.Getenv(SubsetLabelEnvVar),
+	}
 }
 
 // NewStandaloneReconciler returns a new reconcile.Reconciler. Primarily used for unit testing outside of the Manager.
@@ -131,6 +151,7 @@ type ReconcileSession struct {
 	client       client.Client
 	scheme       *runtime.Scheme
 	manipulators Manipulators
+	subsetLabel  string // operator wide pod label used to identify version subsets, session.Spec.SubsetLabel takes precedence
 }
 
 // WatchTypes returns a list of client.Objects to watch for changes.
@@ -271,6 +292,7 @@ func (r *ReconcileSession) syncAllRefs(ctx model.SessionContext, session *istiov
 	for _, specRef := range session.Spec.Refs {
 		ctx.Log.Info("Add ref", "name", specRef.Name)
 		ref := ConvertAPIRefToModelRef(specRef, session.Namespace)
+		ref.SubsetLabel = r.getSubsetLabel(session)
 		err := r.sync(ctx, session, &ref)
 		if err != nil {
 			return err
@@ -290,6 +312,16 @@ func (r *ReconcileSession) syncAllRefs(ctx model.SessionContext, session *istiov
 	return errors.Wrap(ctx.Client.Status().Update(ctx, session), "failed syncing all refs")
 }
 
+// getSubsetLabel returns the configured pod label used to identify version subsets for the given session.
+// Empty value means it should be detected.
+func (r *ReconcileSession) getSubsetLabel(session *istiov1alpha1.Session) string {
+	if session.Spec.SubsetLabel != "" {
+		return session.Spec.SubsetLabel
+	}
+
+	return r.subsetLabel
+}
+
 func unique(s []string) []string {
 	uniqueSlice := []string{}
 	entries := make(map[string]bool)
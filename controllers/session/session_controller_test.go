@@ -17,6 +17,7 @@ import (
 	"github.com/maistra/istio-workspace/api/maistra/v1alpha1"
 	"github.com/maistra/istio-workspace/controllers/session"
 	"github.com/maistra/istio-workspace/pkg/model"
+	"github.com/maistra/istio-workspace/pkg/routing"
 )
 
 var kind, name, action = "test", "details", "created"
@@ -154,7 +155,7 @@ var _ = Describe("Basic session manipulation", func() {
 				fmt.Println(modified.Status.Route)
 				Expect(modified.Status.Route).ToNot(BeNil())
 				Expect(modified.Status.Route.Type).To(Equal(session.RouteStrategyHeader))
-				Expect(modified.Status.Route.Name).To(Equal(session.DefaultRouteHeaderName))
+				Expect(modified.Status.Route.Name).To(Equal(routing.DefaultRouteHeaderName))
 			})
 		})
 	})
@@ -0,0 +1,178 @@
+package session
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+	errorsK8s "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/maistra/istio-workspace/pkg/template"
+)
+
+const (
+	// WatchNamespaceEnvVar holds the name of the env variable listing the namespaces watched by the operator, empty for all.
+	WatchNamespaceEnvVar = "WATCH_NAMESPACE"
+
+	// OperatorNamespaceEnvVar holds the name of the env variable with the namespace the operator is running in.
+	OperatorNamespaceEnvVar = "POD_NAMESPACE"
+)
+
+var _ reconcile.Reconciler = &ReconcileStrategy{}
+
+// ReconcileStrategy keeps the strategies of the template engine in sync with the labeled ConfigMaps.
+type ReconcileStrategy struct {
+	client            client.Client
+	engine            *template.ReloadableEngine
+	operatorNamespace string
+	operatorReader    client.Reader
+}
+
+// NewStrategyReconciler returns a new reconcile.Reconciler updating the given engine.
+func NewStrategyReconciler(c client.Client, engine *template.ReloadableEngine) *ReconcileStrategy {
+	return &ReconcileStrategy{client: c, engine: engine}
+}
+
+// WithOperatorNamespace makes the reconciler read ConfigMaps of the operator namespace using the given reader,
+// as this namespace is not necessarily among the watched ones.
+func (r *ReconcileStrategy) WithOperatorNamespace(namespace string, reader client.Reader) *ReconcileStrategy {
+	r.operatorNamespace = namespace
+	r.operatorReader = reader
+
+	return r
+}
+
+// addStrategy adds a new Controller watching strategy ConfigMaps to mgr with r as the reconcile.Reconciler.
+// Custom strategies can be defined next to the operator itself, so when its namespace is not watched
+// a separate cache is used to watch only the ConfigMaps there.
+func addStrategy(mgr manager.Manager, r *ReconcileStrategy) error {
+	c, err := controller.New("strategy-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return errors.Wrap(err, "failed creating strategy-controller")
+	}
+
+	sources := []source.Source{&source.Kind{Type: &corev1.ConfigMap{}}}
+	if namespace := unwatchedOperatorNamespace(); namespace != "" {
+		operatorCache, cacheErr := newConfigMapCache(mgr, namespace)
+		if cacheErr != nil {
+			return cacheErr
+		}
+		r.WithOperatorNamespace(namespace, operatorCache)
+		sources = append(sources, source.NewKindWithCache(&corev1.ConfigMap{}, operatorCache))
+	}
+
+	for _, configMaps := range sources {
+		if err := c.Watch(configMaps, &handler.EnqueueRequestForObject{}, strategyConfigMapPredicate); err != nil {
+			return errors.Wrap(err, "failed creating strategy-controller")
+		}
+	}
+
+	return nil
+}
+
+// Label removal has to be seen as well, so the strategies of the ConfigMap are dropped.
+var strategyConfigMapPredicate = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return isStrategyConfigMap(e.Object)
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return isStrategyConfigMap(e.ObjectOld) || isStrategyConfigMap(e.ObjectNew)
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return isStrategyConfigMap(e.Object)
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return isStrategyConfigMap(e.Object)
+	},
+}
+
+// newConfigMapCache creates the cache for the given namespace started together with the manager. As the informers
+// are created on demand, only ConfigMaps are watched and read there.
+func newConfigMapCache(mgr manager.Manager, namespace string) (cache.Cache, error) {
+	configMapCache, err := cache.New(mgr.GetConfig(), cache.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper(), Namespace: namespace})
+	if err != nil {
+		return nil, errors.WrapWithDetails(err, "failed creating cache", "namespace", namespace)
+	}
+	if err = mgr.Add(configMapCache); err != nil {
+		return nil, errors.WrapWithDetails(err, "failed adding cache to manager", "namespace", namespace)
+	}
+
+	return configMapCache, nil
+}
+
+// unwatchedOperatorNamespace returns the namespace the operator is running in unless it is already watched.
+func unwatchedOperatorNamespace() string {
+	operatorNs := os.Getenv(OperatorNamespaceEnvVar)
+	if operatorNs == "" {
+		return ""
+	}
+	for _, ns := range strings.Split(os.Getenv(WatchNamespaceEnvVar), ",") {
+		// empty namespace means all namespaces are watched
+		if ns == "" || ns == operatorNs {
+			return ""
+		}
+	}
+
+	return operatorNs
+}
+
+// Reconcile loads the strategies defined in the ConfigMap into the template engine, or removes them
+// when the ConfigMap is gone or no longer labeled.
+func (r *ReconcileStrategy) Reconcile(c context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := logger().WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	source := request.NamespacedName.String()
+
+	reader := client.Reader(r.client)
+	if r.operatorReader != nil && request.Namespace == r.operatorNamespace {
+		reader = r.operatorReader
+	}
+	configMap := &corev1.ConfigMap{}
+	err := reader.Get(c, request.NamespacedName, configMap)
+	if err != nil {
+		if errorsK8s.IsNotFound(err) {
+			reqLogger.Info("Removing strategies")
+			r.engine.Remove(source)
+
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, errors.WrapWithDetails(err, "failed reconciling strategy", "configmap", source)
+	}
+
+	if !isStrategyConfigMap(configMap) || configMap.DeletionTimestamp != nil {
+		reqLogger.Info("Removing strategies")
+		r.engine.Remove(source)
+
+		return reconcile.Result{}, nil
+	}
+
+	patches := template.PatchesFromData(configMap.Data)
+	names := make([]string, 0, len(patches))
+	for _, p := range patches {
+		names = append(names, p.Name)
+	}
+	// Invalid templates are not retried, the next change of the ConfigMap will trigger reconcile again
+	if err := r.engine.Update(source, patches); err != nil {
+		reqLogger.Error(err, "Failed loading strategies", "strategies", strings.Join(names, ","))
+
+		return reconcile.Result{}, nil
+	}
+	reqLogger.Info("Loaded strategies", "strategies", strings.Join(names, ","))
+
+	return reconcile.Result{}, nil
+}
+
+func isStrategyConfigMap(object client.Object) bool {
+	return object != nil && object.GetLabels()[template.StrategyLabel] == "true"
+}
@@ -0,0 +1,137 @@
+package session_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/maistra/istio-workspace/controllers/session"
+	"github.com/maistra/istio-workspace/pkg/template"
+)
+
+var _ = Describe("Custom strategies from ConfigMaps", func() {
+
+	var (
+		c          client.Client
+		engine     *template.ReloadableEngine
+		controller reconcile.Reconciler
+		req        reconcile.Request
+		configMap  *corev1.ConfigMap
+	)
+
+	patchNames := func() []string {
+		names := []string{}
+		for _, p := range engine.Patches() {
+			names = append(names, p.Name)
+		}
+
+		return names
+	}
+
+	BeforeEach(func() {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "team-strategies",
+				Namespace: "test",
+				Labels:    map[string]string{template.StrategyLabel: "true"},
+			},
+			Data: map[string]string{
+				"with-debug-env.tpl": `[{{ template "_basic-version" . }}{{ template "_basic-remove" . }}]`,
+				"with-debug-env.var": "level=debug",
+			},
+		}
+		req = reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "test", Name: "team-strategies"}}
+	})
+
+	JustBeforeEach(func() {
+		c = fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+		Expect(corev1.AddToScheme(c.Scheme())).To(Succeed())
+		Expect(c.Create(context.Background(), configMap)).To(Succeed())
+		engine = template.NewReloadableEngine(template.LoadPatches("template/strategies"))
+		controller = session.NewStrategyReconciler(c, engine)
+	})
+
+	It("should load strategies from labeled ConfigMap", func() {
+		_, err := controller.Reconcile(context.Background(), req)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(patchNames()).To(ContainElement("with-debug-env"))
+	})
+
+	It("should remove strategies when ConfigMap is deleted", func() {
+		_, err := controller.Reconcile(context.Background(), req)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(c.Delete(context.Background(), configMap)).To(Succeed())
+		_, err = controller.Reconcile(context.Background(), req)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(patchNames()).ToNot(ContainElement("with-debug-env"))
+	})
+
+	It("should remove strategies when ConfigMap is no longer labeled", func() {
+		_, err := controller.Reconcile(context.Background(), req)
+		Expect(err).ToNot(HaveOccurred())
+
+		configMap.Labels = map[string]string{}
+		Expect(c.Update(context.Background(), configMap)).To(Succeed())
+		_, err = controller.Reconcile(context.Background(), req)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(patchNames()).ToNot(ContainElement("with-debug-env"))
+	})
+
+	Context("operator namespace", func() {
+
+		var operatorReader client.Client
+
+		JustBeforeEach(func() {
+			operatorConfigMap := configMap.DeepCopy()
+			operatorConfigMap.Namespace = "operator"
+			operatorConfigMap.Data = map[string]string{
+				"with-trace-env.tpl": `[{{ template "_basic-version" . }}{{ template "_basic-remove" . }}]`,
+			}
+			operatorReader = fake.NewClientBuilder().WithScheme(c.Scheme()).WithObjects(operatorConfigMap).Build()
+			controller = session.NewStrategyReconciler(c, engine).WithOperatorNamespace("operator", operatorReader)
+		})
+
+		It("should load strategies using the operator namespace reader", func() {
+			_, err := controller.Reconcile(context.Background(), reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: "operator", Name: "team-strategies"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(patchNames()).To(ContainElement("with-trace-env"))
+		})
+
+		It("should load strategies of watched namespaces using the manager client", func() {
+			_, err := controller.Reconcile(context.Background(), req)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(patchNames()).To(ContainElement("with-debug-env"))
+			Expect(patchNames()).ToNot(ContainElement("with-trace-env"))
+		})
+	})
+
+	Context("invalid template", func() {
+
+		BeforeEach(func() {
+			configMap.Data["with-debug-env.tpl"] = `[{{ template "_basic-version" . }]`
+		})
+
+		It("should not load strategies", func() {
+			_, err := controller.Reconcile(context.Background(), req)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(patchNames()).ToNot(ContainElement("with-debug-env"))
+		})
+	})
+})
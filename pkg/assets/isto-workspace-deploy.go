@@ -1,12 +1,27 @@
 // Code generated by go-bindata. (@generated) DO NOT EDIT.
 
-//Package assets generated by go-bindata.// sources:
+ //Package assets generated by go-bindata.// sources:
 // template/strategies/_basic-remove.tpl
 // template/strategies/_basic-version.tpl
+// template/strategies/_basic.tpl
+// template/strategies/debug.tpl
+// template/strategies/debug.var
+// template/strategies/extra-env.smp.yaml
+// template/strategies/extra-env.var
+// template/strategies/mirrord.tpl
+// template/strategies/mirrord.var
 // template/strategies/prepared-image.tpl
 // template/strategies/prepared-image.var
+// template/strategies/sync.tpl
+// template/strategies/sync.var
 // template/strategies/telepresence.tpl
 // template/strategies/telepresence.var
+// template/strategies/telepresence2.tpl
+// template/strategies/telepresence2.var
+// template/strategies/tunnel.tpl
+// template/strategies/tunnel.var
+// config/crd/bases/maistra.io_sessions.yaml
+// config/install/bases/operator.yaml
 package assets
 
 import (
@@ -83,7 +98,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _templateStrategies_basicRemoveTpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x8e\xbd\x4e\xc4\x30\x10\x06\xfb\x7b\x0a\xcb\xf5\xe9\x4c\x4d\x4d\x41\x49\x81\xe8\x17\xfb\x03\x2c\xc5\x3f\xda\xdd\xa4\xb1\xfc\xee\x28\xa1\x89\x40\x28\x8e\x74\xa5\xad\xfd\x66\xa6\x35\x13\x3f\xcc\xed\x89\x94\x6e\xcf\x24\xc6\x3a\xa9\xf0\x4e\x91\xea\x44\x8a\x9f\x97\x2f\x59\x29\x66\xb0\xb8\x07\x37\xc5\x05\x19\x22\x2f\x5c\xde\x61\x4d\xef\x97\x66\x4b\xb5\x8f\xc6\x32\x52\x59\x60\xaf\xc6\x56\xd2\xaf\xf5\xe7\x24\xac\x5f\x2f\xad\x19\xe4\xb0\x51\x4f\x97\x31\x28\xc4\xbb\xa5\xfd\xa2\x1d\xb4\x25\x28\x05\x52\x72\x0c\x29\x33\x7b\xbc\x81\x25\x96\x7c\x54\xf1\xef\x6e\xd4\xf7\x89\x0c\x26\x3d\xa3\xda\x4d\x46\x2d\x73\x0c\xc3\xf8\xf5\x76\x94\xeb\x19\x5b\xc8\x6b\x4c\x10\xa5\x54\x87\x2d\x7f\x97\x7d\xa7\xfc\x0e\x00\x00\xff\xff\x77\xe1\xaa\x7c\xd7\x02\x00\x00")
+var _templateStrategies_basicRemoveTpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x8e\x41\x4b\x03\x31\x10\x46\xef\xfe\x8a\x61\xe8\xa1\x42\xd9\xdc\x17\x3c\xe9\x41\x6f\x1e\xa4\xf7\x31\xfb\xa9\x81\x6e\x12\x26\xd3\x22\x84\xfc\x77\xd9\x5a\xa5\x54\xb4\x2b\x7a\x0a\x21\xf9\xde\x7b\xb5\xd2\xc2\x53\x7f\x45\xdd\x8d\x98\x74\xd7\x29\x9a\x84\x08\xbd\x8b\x03\x5e\x69\x19\xf6\x47\xb7\x16\x2d\xc4\xfe\xe3\x91\x2f\xa9\xb5\x8b\x5a\x29\x3c\x1d\x76\xb7\x52\x68\x99\x35\x44\x23\x76\x25\xc3\x3b\xc3\x98\x37\x62\x78\xbf\x7d\x4e\x8b\xe3\x49\xc8\x6e\x13\x76\x88\x28\xe5\x5e\xd3\x23\x0e\x40\x4e\x99\x7b\x62\xc5\x98\x76\xe0\x15\x71\x16\x7b\xe1\xfe\x2c\xb2\xd6\x85\x6f\xed\x04\xd9\x56\x53\x21\xe2\xf0\xf7\x56\x85\x0c\xe1\x9f\x63\x4f\x98\x3f\xd6\xb2\x1b\x61\x32\x88\x89\x53\x94\xb4\x55\x8f\x35\xb4\x84\x14\xf9\x4c\xca\xb7\xbb\xb9\xbe\x67\x44\xa8\xd8\x6f\x54\x47\x93\xb9\x96\x6d\x18\x66\xe3\xa7\xbf\x73\xb9\x5e\xb1\x6f\x7f\x08\x23\x8a\xc9\x98\x67\x5b\xbe\x2e\xdb\x91\xf2\x6d\x00\x24\x1a\x2a\x2f\x36\x03\x00\x00")
 
 func templateStrategies_basicRemoveTplBytes() ([]byte, error) {
 	return bindataRead(
@@ -103,7 +118,7 @@ func templateStrategies_basicRemoveTpl() (*asset, error) {
 	return a, nil
 }
 
-var _templateStrategies_basicVersionTpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\x94\x4d\x6f\xf2\x30\x10\x84\xef\xfc\x8a\xd5\x9e\xde\x57\x02\x7c\xe7\x5a\x2a\xf5\x50\xf5\xc8\x7d\xeb\x2c\x25\xaa\xbf\x1a\x1b\x2a\x64\xf9\xbf\x57\x09\x1f\x05\x0a\xc5\x31\xf4\x98\xc4\x3b\xf3\x64\x76\xe4\x18\xa1\x9e\x83\xb1\x01\xfe\x8d\xa7\x14\x68\xfc\x44\x1e\x50\x78\xc7\x52\x04\xd6\x4e\x51\x60\xa1\x39\x50\x45\x81\xf0\x3f\xa4\x34\x88\x68\x1d\x4e\x00\xa9\xaa\x70\x08\xe8\x28\x2c\xda\xc7\x4b\x33\x43\xc0\x15\xa9\x25\xe3\x04\x62\x4a\xc3\x41\x8c\xc0\xa6\xea\x84\xfa\x78\x0b\x45\xaf\xac\x7c\x09\xc2\x6e\xf4\x1a\x49\x36\x84\x58\x71\xe3\x6b\x6b\xf0\x90\x45\x5a\xb7\x6e\x2d\xe6\x8d\xd5\xd7\x61\xf6\x12\xd9\xf8\xbb\x89\x91\xb7\xcb\x46\x32\xb6\x3f\xb0\xb5\x6e\xd8\x29\x92\xdc\x5f\xeb\x30\x12\x8c\x71\xfc\xc2\x9f\xb3\xcd\x97\x94\xf0\xe6\x5d\xed\x5d\xca\x77\x76\x5f\x50\xcf\x8a\x65\xb0\x4d\x06\xd0\xfe\x68\x5e\x69\x1e\x3f\x96\xa4\x00\xc5\x7b\x6d\x2a\x04\x9c\xb2\x53\x76\xad\xd9\x84\xae\x22\x00\x39\x54\x42\x53\x90\x8b\xe7\x83\x9a\x03\x64\x31\x1e\x0d\x9e\xf2\x76\xde\x5b\xe2\x1d\x47\x16\xc2\x51\xc9\xbf\x49\x2e\x76\xed\x57\x8d\x2b\xeb\x3b\x07\x99\x1f\xd6\x71\xcf\x0a\x42\x2b\xc1\xec\xd9\x83\x07\x6b\xe6\xf5\x1b\xe6\x6d\xa1\x34\xf9\xbf\x4b\xbb\x28\xe1\xbb\xa5\xba\x21\xca\xb9\x84\xcf\x65\x74\xfb\x95\x92\x23\x6e\x48\xf3\xa9\x64\x07\x3f\x6b\x5f\xfc\x38\x99\xd2\xe8\x8c\xe7\x57\x00\x00\x00\xff\xff\x82\xd4\x0f\xab\x8e\x07\x00\x00")
+var _templateStrategies_basicVersionTpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbc\x94\x4f\x8b\xdb\x30\x10\xc5\xef\xfd\x14\xc3\xd0\x43\x16\xbc\xd6\xdd\xd0\x53\xb7\x50\xca\xb2\x2d\x2c\xe4\x3e\x6b\x4f\x1a\x53\x59\x52\x25\x39\x6d\x30\xfa\xee\xc5\x8e\x93\xd8\xa9\x13\xff\x21\xec\x35\xd1\x7b\xf3\xf3\x7b\x23\x55\x15\x7c\x74\xe5\x9b\x63\xff\x4c\x6f\x2c\x21\xf9\x04\xda\xc2\x2a\x57\x19\xff\x85\x78\x4d\xd6\x01\x76\xfe\xc7\x07\xc0\x1d\x5b\x97\x6b\x85\x10\xc2\x87\x5a\x2e\x8f\x42\x76\x29\x19\xfe\xf6\xfa\xfd\xe5\x87\xce\x95\x67\xdb\xb7\xee\x1e\x7f\xd5\xa5\x4d\x79\x58\xb4\x32\x36\x57\xbe\xaf\xc5\x47\xd7\x28\xf0\xa1\xb5\xc9\x37\xa0\xb4\x87\x55\xfc\x44\x9e\xe2\xaf\xe4\x00\x85\x33\x9c\x0a\xcf\x85\x91\xe4\x59\x14\xec\x29\x23\x4f\xad\x04\xb5\xc1\x04\x90\xb2\x0c\x23\x40\x43\x7e\x8b\xc9\x75\x4d\x04\xb8\x23\x59\x32\x26\x50\x85\x10\xd5\xe0\xac\xb2\xd9\xb3\x45\x93\x8d\x5b\x82\x70\x94\x8e\x91\x9c\x21\xda\xdc\x46\x0c\x05\xb6\x15\xf4\x98\x52\x6d\xf6\x75\x2e\x1b\xab\x8b\x71\x28\x51\x55\x07\x8f\x10\xa6\x7f\xc9\x49\x74\xe8\x3e\x04\x0c\xd1\x69\xbe\x65\x23\x29\xe5\x05\x6e\x21\x74\x23\xc2\xaa\x8a\x5f\xf8\xcf\xfa\xb0\xa2\xed\x8c\xdb\xdd\xcd\x8d\x6d\x79\x97\xf7\x63\x6e\x07\x39\x96\x9c\x7a\x6d\x27\xec\xd7\xe9\xe8\xb4\x7d\xfa\xf2\xbb\x24\x09\x28\x7e\xe5\x2a\x43\xc0\x27\x36\x52\xef\x0b\x56\xbe\xb9\xf5\x00\x53\xa8\x44\x41\x3e\xdd\x3e\x77\x6e\x00\xc0\x24\xc6\x9e\xf0\x92\x17\xe0\x4c\x7c\xe4\xb8\x56\xe6\x90\x61\x7f\xff\x01\x46\x37\x70\xd0\x64\x7a\x93\x43\xbc\x37\x37\xf0\x36\xf4\xd2\x1c\x17\x12\xcf\xdc\x8e\xcf\x5a\x6d\xf2\x9f\x38\xab\x9b\xc5\x7d\xbc\x47\x07\xcb\x72\xbf\x67\xd6\x3d\xb8\xab\x8f\x52\xf7\xfe\x0f\x25\x77\x97\x97\x68\x8a\xbf\xa2\x82\x2f\x2d\x9b\x90\xd7\xf5\x0f\xff\x9d\x0c\xe1\x71\x60\xe6\xbf\x01\x00\xd0\xe1\x4d\x8f\x95\x08\x00\x00")
 
 func templateStrategies_basicVersionTplBytes() ([]byte, error) {
 	return bindataRead(
@@ -123,7 +138,147 @@ func templateStrategies_basicVersionTpl() (*asset, error) {
 	return a, nil
 }
 
-var _templateStrategiesPreparedImageTpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x90\x31\x6b\xc3\x30\x10\x85\x77\xff\x8a\xc7\x4d\x2d\xb8\x52\xbb\x7a\xee\xd0\x5f\xd0\xa5\x94\x72\x95\xaf\x8d\xc0\x96\x84\xa4\x78\x11\xfa\xef\x41\xb1\x33\x78\x48\x20\x19\x8f\x7b\xdf\xf7\xe0\x7d\x75\x1d\x50\x0a\xb2\xcc\x61\xe2\x2c\xa0\x9f\x5f\x4e\xd6\xbc\x2c\x12\x93\xf5\x8e\xa0\x50\xeb\x16\xb2\x7f\x70\x3e\xe3\x49\xbd\x73\x66\xf5\xc1\x09\xa4\x53\x10\xa3\x2f\xf4\x7a\x45\x09\x93\x35\x9c\xe8\xb9\xa1\x40\x21\x1f\x68\x00\xf1\x38\x52\x0f\x0a\x9c\x0f\xed\xbc\x89\xf6\xa0\x85\xa7\xa3\xd0\x80\x52\x6b\xbf\xf6\x8b\x1b\xf7\xc6\x16\x67\x23\x8f\x58\xe9\x8d\x56\xed\x7d\x2a\xe3\x5d\x66\xeb\x24\x26\xfd\xaa\xed\xcc\xff\xb2\x93\x96\xa2\x3e\x39\x26\x75\xfe\xd4\xda\x2a\xae\xec\x1b\x65\xf6\x8b\x6c\xf3\x7e\x77\xa7\x00\x00\x00\xff\xff\x0a\x21\x5d\x3a\x88\x01\x00\x00")
+var _templateStrategies_basicTpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x4b\x00\xb4\xff\x5b\x0a\x20\x20\x7b\x7b\x20\x74\x65\x6d\x70\x6c\x61\x74\x65\x20\x22\x5f\x62\x61\x73\x69\x63\x2d\x76\x65\x72\x73\x69\x6f\x6e\x22\x20\x2e\x20\x7d\x7d\x0a\x20\x20\x7b\x7b\x20\x74\x65\x6d\x70\x6c\x61\x74\x65\x20\x22\x5f\x62\x61\x73\x69\x63\x2d\x72\x65\x6d\x6f\x76\x65\x22\x20\x2e\x20\x7d\x7d\x0a\x5d\x0a\x03\x00\xf3\x45\x7d\xd2\x4b\x00\x00\x00")
+
+func templateStrategies_basicTplBytes() ([]byte, error) {
+	return bindataRead(
+		_templateStrategies_basicTpl,
+		"template/strategies/_basic.tpl",
+	)
+}
+
+func templateStrategies_basicTpl() (*asset, error) {
+	bytes, err := templateStrategies_basicTplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "template/strategies/_basic.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templateStrategiesDebugTpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcc\x56\x51\x6f\xdb\x36\x10\x7e\xf7\xaf\x38\x1c\xfc\xd0\x02\xa2\xdc\xf4\x65\x80\x03\x01\x2b\xda\x0d\x4b\x31\xc4\x03\x5a\xe4\xa5\x29\x02\x86\x3a\xcb\xec\x24\x92\x25\x29\x37\x81\xc0\xff\x3e\xd0\x22\x6d\x67\x9a\x91\xcc\x7b\xd9\x9b\x48\xdd\x7d\xdf\xf1\xfb\x8e\x27\x0d\x03\xac\xb9\x6c\xaf\xd6\x37\xdc\x4a\x7e\xdf\xd2\x07\x4d\xee\x5a\xfb\x5f\x1e\xa4\xf3\x50\xde\x70\xeb\x00\x5b\xae\x9a\x9e\x37\x84\xc0\x42\x98\xbd\x28\xc5\x68\xeb\xf7\xe1\x72\x0d\x8a\x46\xb0\x32\x63\x01\x7e\xe3\x5b\x8e\x10\xc2\x8b\xf0\x84\xee\x3a\xae\xea\x14\x4f\xaa\xce\xd8\x73\x01\xcb\x0a\xca\x0f\xdc\xf3\xf2\xbd\x56\x9e\x4b\x45\xf6\x4a\xd5\xf4\x90\x08\x45\xde\x84\x94\xe0\x7a\x67\x22\xc0\xb2\x02\xfa\x9e\x82\xf2\x1e\x7a\xdb\x13\xe6\xc8\x98\x2a\x55\x4f\x91\x01\x81\xb1\xbc\x4e\x55\xcc\x7f\x70\xe9\x7f\xd5\xf6\x7d\x2b\x49\xf9\x5d\x50\x4e\x95\xeb\x03\x4f\x08\x4f\xb0\x52\xd4\x34\x7f\xe4\x88\x7b\x6c\xad\x2d\x13\xbb\xdd\x14\x3a\xe2\xcc\xbe\xcc\x00\x86\x01\x3c\x75\xa6\xe5\x9e\x00\xef\xee\xb9\x93\x82\x6d\xc9\x3a\xa9\x15\x42\x19\xf9\xc7\xa0\x28\xba\xf6\xf0\x6a\x94\xe6\x37\xee\x00\x17\xce\x90\x58\xe4\xec\x71\x65\xc9\xb4\x52\x70\x87\xaf\x63\x2a\xc0\x80\xda\xe0\x12\x90\xd7\x35\x16\x80\x86\xfb\x0d\x2e\x9f\x49\x2d\x00\xb7\xbc\xed\x09\x97\x30\x84\x50\x8c\xfc\xa9\xe4\x03\x62\x0c\xe7\x82\xce\x41\xc5\x0b\xcc\xb0\x72\x9d\x2c\x93\x1d\x6f\xe8\x2c\x86\x7d\x47\xb8\xc5\x30\xcc\x45\x08\x8b\x1d\xd6\x13\xc2\x61\x38\x62\x09\x01\xa7\xa7\xda\x95\x42\xdf\x4f\xb6\xf5\x29\x17\x5e\x19\x2b\x95\x7f\xb6\x32\x84\xb9\x00\x5c\x90\xda\xe2\xeb\x7f\x6f\xcd\xf4\x88\x11\xe8\xe8\x80\x5f\xbe\x4e\x4f\xf4\x1f\xe1\x17\xec\x98\x60\x98\x01\x00\xa0\xe2\x5d\x5c\xe1\xc7\x77\x37\xef\xee\x3e\xaf\x56\xbf\xdf\xad\xfe\xf8\x7c\xb5\xba\xfe\x84\x91\x1e\x0e\xf1\xc8\x78\x43\xca\xb7\xf2\x7e\xf9\xad\xfe\x61\x2a\x6f\xb9\x72\x71\x8a\x54\xb5\xbf\x73\x5a\xfc\x49\xbe\x70\x64\xb7\x64\xab\xc7\x22\x5d\xad\x6a\x72\xd3\x1e\xa3\x45\xad\x8b\x7d\xa1\xf6\x67\x2b\x78\x5d\x5b\x72\xae\xba\x78\xfb\x53\xf9\xa6\x7c\x53\x5e\x2c\xb3\xbd\x91\x20\x04\x9c\x01\x44\x4f\xf7\x92\x8c\x08\x7b\x07\xcf\x35\x8f\xdb\x66\x72\xaf\x2c\x75\x7a\x7b\x46\x8b\xee\xb0\x5e\xde\x85\x8d\xc6\xa7\xbc\x67\xb9\x9a\xa7\xee\x71\xe3\xa0\xdb\xc4\xea\x99\xc0\x22\xd2\x7b\xfd\xf1\xd3\xea\x7a\x2f\x8c\x23\x0f\x8c\x01\xa6\x82\x12\x00\xe0\x25\x18\xab\x1b\xcb\xbb\x6a\x7e\x71\x09\x6e\x23\xd7\xfe\x12\xe8\x81\x04\xd4\xed\x76\x7c\x60\x6c\x43\xbc\x6e\xc9\x39\x60\xac\x95\xce\x93\x3a\x72\x2c\x23\x46\xc7\x76\x83\x92\x1b\x99\xe7\x5e\xf5\x36\xae\x85\x20\xe3\x59\xd7\xb7\x5e\xe6\xd9\x79\x98\xba\x08\xb7\x38\x4f\x25\xdc\xc6\xf4\xb8\xfe\xf9\x16\x77\xf6\x1c\xee\x42\x34\xfe\x9f\x05\x35\x8f\x7e\xa3\xd5\xff\x4d\x54\xa9\x3c\x59\x63\xc9\x93\x9d\x08\x7b\x8b\xf3\xa3\xd7\xf1\xd0\x1d\xd4\x74\xdf\x37\xe6\x71\x2f\x30\x9c\x10\xf8\x6f\x9f\x26\x3c\xad\x56\x6e\xc3\xf8\x17\xb1\x2f\xb8\x57\xae\x37\x11\x88\x6a\x38\x48\x38\xd1\xb4\x00\x7a\x30\x24\x3c\xd5\xa0\x15\x81\x5e\x43\xa3\x0b\x88\xff\x05\x05\x24\xbd\xf3\xed\x79\xda\xf3\xe9\xf9\xc4\xd7\x30\x5d\x31\x28\x21\x84\xd9\xd7\xd9\x5f\x03\x00\xa1\x24\xbb\xaf\xe3\x08\x00\x00")
+
+func templateStrategiesDebugTplBytes() ([]byte, error) {
+	return bindataRead(
+		_templateStrategiesDebugTpl,
+		"template/strategies/debug.tpl",
+	)
+}
+
+func templateStrategiesDebugTpl() (*asset, error) {
+	bytes, err := templateStrategiesDebugTplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "template/strategies/debug.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templateStrategiesDebugVar = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x4d\x00\xb2\xff\x6c\x61\x6e\x67\x75\x61\x67\x65\x3d\x0a\x70\x6f\x72\x74\x3d\x0a\x63\x6f\x6d\x6d\x61\x6e\x64\x3d\x0a\x69\x6d\x61\x67\x65\x3d\x0a\x73\x75\x73\x70\x65\x6e\x64\x3d\x66\x61\x6c\x73\x65\x0a\x63\x6f\x6e\x74\x61\x69\x6e\x65\x72\x3d\x0a\x73\x75\x62\x73\x65\x74\x4c\x61\x62\x65\x6c\x3d\x76\x65\x72\x73\x69\x6f\x6e\x0a\x03\x00\x2d\x7d\xf3\x55\x4d\x00\x00\x00")
+
+func templateStrategiesDebugVarBytes() ([]byte, error) {
+	return bindataRead(
+		_templateStrategiesDebugVar,
+		"template/strategies/debug.var",
+	)
+}
+
+func templateStrategiesDebugVar() (*asset, error) {
+	bytes, err := templateStrategiesDebugVarBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "template/strategies/debug.var", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templateStrategiesExtraEnvSmpYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4c\x8e\xcd\x4a\xc4\x30\x14\x85\xf7\x7d\x8a\x43\x18\x41\xc1\xf6\x01\x0a\xae\x1c\x17\x22\xcc\xb2\xfb\xeb\xcc\xa9\x04\xda\xdb\x9a\xc4\x52\xb9\xe4\xdd\xa5\xd5\x48\xc9\x26\x9c\x9f\xef\x5c\x33\xf4\xe2\x87\xd7\xbe\x93\xe0\xe5\x7d\xe0\x79\x62\xbc\x4c\xe9\x65\xf5\x31\xa1\xe9\x24\x44\x38\xea\xe2\x50\xe7\x5c\xc5\x99\xd7\xb6\x02\x12\xc7\x79\x90\xc4\xed\x0f\x14\x75\x7b\xd7\x49\x93\x78\x65\x88\x45\xa9\xa1\x32\xb2\x85\x19\x9a\xb3\x24\x69\x9e\x4b\xe4\x22\x23\x71\xef\xf5\xc6\xb5\x2c\xfd\xd7\xdd\x03\x72\xfe\x23\x00\xd4\xa5\xe0\x00\xb3\x1a\x41\xf4\x83\x38\x6d\xe4\x47\x9c\x16\x19\xbe\x88\xf6\x09\xb3\x84\xc8\x37\x7e\x77\x9b\x10\x7f\xa1\x0d\x75\x39\xb2\x0e\xf7\xec\xfd\xa3\x07\xec\xa8\xdd\x9c\x83\xd7\xd4\xc3\xdd\x7d\xba\xb2\x70\x48\x9a\xd5\xa0\xde\x90\x73\xf5\x33\x00\x21\xa3\x5e\xd6\x43\x01\x00\x00")
+
+func templateStrategiesExtraEnvSmpYamlBytes() ([]byte, error) {
+	return bindataRead(
+		_templateStrategiesExtraEnvSmpYaml,
+		"template/strategies/extra-env.smp.yaml",
+	)
+}
+
+func templateStrategiesExtraEnvSmpYaml() (*asset, error) {
+	bytes, err := templateStrategiesExtraEnvSmpYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "template/strategies/extra-env.smp.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templateStrategiesExtraEnvVar = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x10\x00\xef\xff\x65\x6e\x76\x3d\x0a\x63\x6f\x6e\x74\x61\x69\x6e\x65\x72\x3d\x0a\x03\x00\x49\x7b\xc8\x18\x10\x00\x00\x00")
+
+func templateStrategiesExtraEnvVarBytes() ([]byte, error) {
+	return bindataRead(
+		_templateStrategiesExtraEnvVar,
+		"template/strategies/extra-env.var",
+	)
+}
+
+func templateStrategiesExtraEnvVar() (*asset, error) {
+	bytes, err := templateStrategiesExtraEnvVarBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "template/strategies/extra-env.var", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templateStrategiesMirrordTpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x8f\xb1\x6a\xc3\x30\x14\x45\x77\x7d\xc5\xe5\x4e\x2d\xa8\x32\x5d\x3d\x77\xe8\x3f\x94\x12\x5e\xe4\x17\x22\xb0\x2d\x61\x29\x5e\x84\xfe\x3d\x18\x27\x43\xc8\x10\xc8\xf8\xe0\x9e\xf3\x38\x7f\x06\xa8\x15\x45\xa7\x34\x4a\x51\xf0\x70\x94\x1c\xfc\xd7\xaa\x4b\x0e\x71\x26\x1c\x5a\x33\xfb\x28\x9c\x30\xc7\x82\x0f\xf7\x23\x45\xdc\xaf\x64\xb0\xcb\x49\x7d\x77\xa7\xf7\x6b\xd1\x34\x06\x2f\x99\x9f\x1b\x0a\x54\xc6\xc4\x1e\x94\x61\xa0\x05\x93\x94\x33\xfb\x17\xa8\x05\x57\x19\x2f\xca\x1e\xb5\x35\xbb\xff\xd7\x79\x78\x34\x6e\x73\xf1\xfa\x8e\x95\xdf\x6c\xf6\xd6\xf5\x14\xbf\xe8\x14\x57\x25\x1c\x5a\x33\xff\xe6\x3a\x00\x2f\xc6\x7b\x26\x24\x01\x00\x00")
+
+func templateStrategiesMirrordTplBytes() ([]byte, error) {
+	return bindataRead(
+		_templateStrategiesMirrordTpl,
+		"template/strategies/mirrord.tpl",
+	)
+}
+
+func templateStrategiesMirrordTpl() (*asset, error) {
+	bytes, err := templateStrategiesMirrordTplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "template/strategies/mirrord.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templateStrategiesMirrordVar = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1f\x00\xe0\xff\x63\x6f\x6e\x74\x61\x69\x6e\x65\x72\x3d\x0a\x73\x75\x62\x73\x65\x74\x4c\x61\x62\x65\x6c\x3d\x76\x65\x72\x73\x69\x6f\x6e\x0a\x03\x00\xc1\x8b\x63\xf3\x1f\x00\x00\x00")
+
+func templateStrategiesMirrordVarBytes() ([]byte, error) {
+	return bindataRead(
+		_templateStrategiesMirrordVar,
+		"template/strategies/mirrord.var",
+	)
+}
+
+func templateStrategiesMirrordVar() (*asset, error) {
+	bytes, err := templateStrategiesMirrordVarBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "template/strategies/mirrord.var", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templateStrategiesPreparedImageTpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x91\x41\x4b\xc4\x30\x10\x85\xef\xfd\x15\x8f\x21\x87\x5d\xa8\x29\x5e\x0b\x9e\xf4\xe0\xfe\x01\x2f\x22\x32\xa6\xe3\x1a\xd8\x26\x21\x89\x45\x08\xf9\xef\x52\x63\x85\x15\x44\xd8\xe3\x30\xef\x7b\x79\xf3\xf2\xd8\x75\x40\x29\xc8\x32\x87\x13\x67\x01\x3d\xbf\x70\xb2\xe6\x6a\x91\x98\xac\x77\x04\x8d\x5a\xbf\x45\xf6\x15\xce\x67\xec\xf4\x1d\x67\xd6\xf7\x9c\x40\x43\x0a\x62\x86\x8d\x6e\x53\x94\x70\xb2\x86\x13\xed\x57\x14\x28\xe4\x03\x8d\x20\x9e\x26\xea\x41\x81\xf3\x1b\x8d\xff\xa0\x3d\x68\xe1\xd3\xbb\xd0\x88\x52\x6b\xdf\xde\x17\x37\x9d\x3b\xae\x72\x36\x72\x89\x2b\x5d\xd3\x66\x1b\xd9\x1d\x05\xca\xf1\x2c\x3d\x94\x9d\xf9\x28\x18\x6f\x10\x38\x26\x39\xac\x53\x82\x7e\xe0\x98\x74\x5b\xb5\x04\x05\xca\xac\x2a\xd5\xca\xb8\xf5\x2e\xb3\x75\x12\x0f\x6e\x92\x0f\xec\x7c\x6c\x86\x50\x0d\x35\xdb\x7e\x7f\xd1\x05\x3f\x78\x1a\x4a\x51\xa6\xd6\xe1\x2b\xcb\xd9\x41\xa5\xb4\xec\xb5\xd2\xaf\xc2\xfe\xf8\xe2\x28\xb3\x5f\x84\xa0\x51\x6b\xf7\xd4\x7d\x0e\x00\x16\xdb\x7f\x7d\x0b\x02\x00\x00")
 
 func templateStrategiesPreparedImageTplBytes() ([]byte, error) {
 	return bindataRead(
@@ -143,7 +298,7 @@ func templateStrategiesPreparedImageTpl() (*asset, error) {
 	return a, nil
 }
 
-var _templateStrategiesPreparedImageVar = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xca\xcc\x4d\x4c\x4f\xb5\xe5\x02\x04\x00\x00\xff\xff\xe0\xf3\xaa\xf1\x07\x00\x00\x00")
+var _templateStrategiesPreparedImageVar = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x26\x00\xd9\xff\x69\x6d\x61\x67\x65\x3d\x0a\x63\x6f\x6e\x74\x61\x69\x6e\x65\x72\x3d\x0a\x73\x75\x62\x73\x65\x74\x4c\x61\x62\x65\x6c\x3d\x76\x65\x72\x73\x69\x6f\x6e\x0a\x03\x00\x81\xf5\x95\xd1\x26\x00\x00\x00")
 
 func templateStrategiesPreparedImageVarBytes() ([]byte, error) {
 	return bindataRead(
@@ -163,7 +318,47 @@ func templateStrategiesPreparedImageVar() (*asset, error) {
 	return a, nil
 }
 
-var _templateStrategiesTelepresenceTpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x94\x5f\x6b\xdb\x30\x14\xc5\xdf\xfd\x29\x2e\xf7\x69\x83\xc4\x5e\xdf\x86\xdf\x42\xaa\xb1\xc2\xe6\x85\xb4\xe4\xa5\x94\x70\x63\x5f\x77\x62\xb2\x64\x24\xcd\x1b\x08\x7f\xf7\xe1\x7f\x69\xdd\xd2\x91\xa4\x0f\x09\x28\x9c\xfb\x3b\x07\x9d\x1b\x85\x00\x25\x49\x75\x53\xee\xc8\x4a\x3a\x28\xbe\x36\xec\x32\xe3\xc5\x5f\xe9\x3c\xc4\x3b\xb2\x0e\xb0\x61\xeb\xa4\xd1\x08\xcb\xb6\x8d\xa2\xfb\x08\x20\x04\xf0\x5c\xd5\x8a\x3c\x03\xee\x0f\xe4\x64\xbe\x3c\xaa\x62\xe8\x64\xbd\x48\x96\xa0\x8d\x87\x0f\xf1\x35\x79\x8a\xbf\x92\x03\x4c\x5c\xcd\x79\x32\x4d\x0f\x27\xcb\xb5\x92\x39\x39\xfc\xd8\x8d\x02\x04\x34\x35\xa6\x80\x54\x14\xb8\x00\xac\xc9\xff\xec\x8e\xff\x1d\x5d\x00\x36\xa4\x7e\x33\xa6\x10\xda\x76\x31\xf8\xb3\x2e\xe6\xc4\x4e\x4e\x39\x5f\x42\xc5\x2b\x1c\xb0\x27\x85\xab\xd8\x53\x41\x9e\x12\x45\x07\x56\x2e\xf1\xac\xb8\xb6\xec\x58\x0f\xee\x47\xaa\x67\xe7\xe7\xe0\x13\x33\xe6\x46\x7b\x92\x9a\xad\x4b\x3e\x25\xb2\xa2\xc7\x39\xb7\x33\xff\x23\x2d\xcf\x9c\x97\xbf\x3e\xbb\x34\x84\xbe\xd7\x78\x2c\xac\x6d\x71\xba\xae\x13\xeb\x9a\x39\xb3\x6e\x2e\xa8\xed\x15\xe2\x59\xf4\xfb\x87\x37\xeb\xbb\x80\x9c\x2c\x67\xab\x11\x01\x00\xa0\xa6\xaa\xbf\xa4\x3b\xf1\x4d\x6c\xb6\xe2\x56\x64\x6b\xb1\x5f\xff\xc8\xee\x56\x37\x99\xd8\xee\xb3\xd5\x77\x71\xbb\x59\xad\x05\x2e\x06\x7d\x3f\xfe\xc5\x9a\xea\x88\x00\xc0\x52\xb2\x2a\xb6\x5c\x3e\xfb\x0d\x00\xa9\x96\xbb\xf1\x9f\x90\x02\x36\x57\x23\xe2\x69\x62\x33\x86\x9f\x36\x24\xee\xc2\xb8\xba\x6b\x7c\x54\xb6\xd1\xf4\xdd\x7f\x9e\xca\x39\xa7\x17\xb2\x8f\x0e\x5f\x2e\x7f\x65\x9a\xf3\xf6\xaa\xa7\xbc\xae\xe3\xec\x34\xb9\xa9\x2a\xd2\xc5\xfb\x03\x4d\xa0\x17\x99\xde\x78\x93\x46\x83\xe1\x49\x7a\x88\xfe\x05\x00\x00\xff\xff\xe0\x41\x6e\xb4\xee\x04\x00\x00")
+var _templateStrategiesSyncTpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x94\x41\x4f\xdb\x4c\x10\x86\xef\xf9\x15\x2f\xa3\xe8\x83\x4f\x4a\xec\x72\x4d\xd4\x5e\x0a\x55\xa9\x54\x5a\xb5\x28\x17\x84\xaa\x89\x3d\x69\x56\xb5\x77\xad\xdd\x49\x88\x64\xed\x7f\xaf\x16\x27\x26\x50\x10\x84\x53\xb4\xf1\xcc\x33\x7e\x1f\x8f\xdd\xb6\x58\xb0\xa9\x2e\x16\x33\xf6\x86\xe7\x95\x9c\x39\x09\x97\x4e\xcf\x37\x26\x28\xb2\x19\xfb\x00\x6a\x4c\xf9\xc9\x54\x42\x18\xc7\x38\x68\x5b\x0c\x0b\x4c\xde\x23\x3b\x63\xe5\xec\xa3\xb3\xca\xc6\x8a\xbf\xb0\xa5\x6c\xba\x8e\xac\xd8\xfd\x89\x6d\x83\xe5\x5a\xee\x7b\x66\x5c\xad\x04\x27\x8d\x37\x56\x41\x79\x68\xa4\xc8\x55\xea\xa6\x62\x95\xee\xd4\x03\x42\x4e\x69\x1c\xe5\x89\x40\xff\x27\xde\xf5\x00\x68\x5b\xec\x1a\x40\xbf\xe6\x1c\x4c\x31\x5e\x8b\x0f\xc6\x59\x42\x96\xaa\xba\x22\xb3\x80\x75\x8a\x93\x6e\xee\x67\x0e\x4f\x8f\xf3\xd2\x54\xa6\xe0\xd0\x0d\x00\x5a\x72\x0d\x4d\x40\x5c\x96\x34\x02\x35\xac\x4b\x9a\xbc\xd0\x3a\x02\xad\x53\x2e\x9a\xa0\x8d\x71\xd4\xcd\x17\x5b\x3e\x24\xa6\x72\x2e\xe4\x2d\x54\x3a\xa5\x38\x7a\x6d\xac\x5a\x94\x4b\x56\xce\xd9\x5a\xa7\xac\xc6\xd9\x03\xd3\x3d\x49\x78\x5d\xc8\xc3\xc1\x79\xc2\x84\x82\x1b\xf9\xf2\xf3\xdb\xe5\x77\x67\xac\x8a\x07\xfd\x59\xcd\xa5\xd0\x2a\x4b\xbf\xde\x8a\x4a\xc8\x8c\xcb\x4b\x59\xf0\xaa\xd2\x71\xbf\x24\x84\x18\x1f\x98\xea\x57\x2e\xc6\x7d\x67\xbb\xe5\xac\x6b\xee\x6f\xf9\x4e\xe6\xbd\xc7\xc3\x96\x92\xfd\xef\x7f\xac\x7a\xa9\xdd\xfa\xc5\x07\xbc\xc7\x6a\xdb\x61\x11\x63\xc7\x7a\xa3\xd2\x67\x90\xdb\xa4\xfb\x6a\xae\x29\x2c\xd3\x79\x5c\xd0\x28\x0d\x52\x97\x84\xf7\xb1\xd5\x73\x83\x63\xd9\x18\xc5\xbb\x63\x5c\x9d\xff\xf8\x8a\x8b\xcb\xab\x29\x6e\x97\xa6\x12\xa8\x5f\xc9\x14\xa5\x03\x3d\x52\x49\xf8\x0f\x52\x2c\x1d\x86\x47\xf8\xd0\x5f\xdd\x7e\x36\x40\x53\xdc\xb2\x51\x0c\x8f\xa6\x08\x95\x48\x83\xd3\x44\xb1\x72\xa7\xee\xe6\x51\xe6\x67\xde\xee\xad\x56\x64\x88\x71\x70\x33\xf8\x3b\x00\xaf\xdc\x21\x67\xb8\x04\x00\x00")
+
+func templateStrategiesSyncTplBytes() ([]byte, error) {
+	return bindataRead(
+		_templateStrategiesSyncTpl,
+		"template/strategies/sync.tpl",
+	)
+}
+
+func templateStrategiesSyncTpl() (*asset, error) {
+	bytes, err := templateStrategiesSyncTplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "template/strategies/sync.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templateStrategiesSyncVar = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x42\x00\xbd\xff\x63\x6f\x6d\x6d\x61\x6e\x64\x3d\x0a\x70\x69\x64\x46\x69\x6c\x65\x3d\x2f\x74\x6d\x70\x2f\x69\x6b\x65\x2d\x73\x79\x6e\x63\x2e\x70\x69\x64\x0a\x63\x6f\x6e\x74\x61\x69\x6e\x65\x72\x3d\x0a\x73\x75\x62\x73\x65\x74\x4c\x61\x62\x65\x6c\x3d\x76\x65\x72\x73\x69\x6f\x6e\x0a\x03\x00\x1e\xf7\x23\x61\x42\x00\x00\x00")
+
+func templateStrategiesSyncVarBytes() ([]byte, error) {
+	return bindataRead(
+		_templateStrategiesSyncVar,
+		"template/strategies/sync.var",
+	)
+}
+
+func templateStrategiesSyncVar() (*asset, error) {
+	bytes, err := templateStrategiesSyncVarBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "template/strategies/sync.var", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templateStrategiesTelepresenceTpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x94\xd1\x6b\xdb\x30\x10\xc6\xdf\xfd\x57\x1c\x47\x1f\x5a\x48\x6c\xfa\x36\x0c\x7b\x08\xa9\xc7\x02\x9b\x17\xd2\x92\x97\x52\xc2\xc5\x3e\x77\x62\xb6\x2c\x24\xcd\x2b\x08\xfd\xef\x43\xb5\x93\xc6\xcb\xba\x6c\x59\x1f\x12\x90\xb8\xef\x77\xdf\xf1\x9d\xec\x1c\x54\x24\xea\x45\xb5\x26\x2d\x68\x5b\xf3\x4d\xcb\x26\x6f\x6d\xf6\x24\x8c\x85\x78\x4d\xda\x00\x76\xac\x8d\x68\x25\xc2\xd4\xfb\xc8\x39\xb8\x28\x20\x7d\x0f\xf1\x0d\x59\x8a\xe7\xad\xb4\x24\x24\xeb\x85\x2c\xf9\xa9\x57\xc4\xc5\xee\x12\xbc\x8f\xee\x23\x00\xe7\xc0\x72\xa3\x6a\xb2\x0c\xb8\xd9\x92\x11\xc5\x74\x4f\x8d\x43\x55\x5f\x24\x2a\x90\xad\x85\xcb\x9e\xfd\x91\x0c\x60\x62\x14\x17\xc9\x4e\xdd\x9f\x34\xab\x5a\x14\x64\xf0\x2a\x48\x01\x1c\xb6\x0a\x53\x40\x2a\x4b\x9c\x00\x2a\xb2\x5f\x31\x3d\x21\x9d\x00\x76\x54\x7f\x67\x4c\xc1\x79\x3f\xe9\xfb\xb3\x2c\xc7\xc4\x50\x4e\x05\x9f\x43\xc5\x6b\xf4\x93\x03\xd4\x9f\xcd\x35\x6c\xa9\x24\x4b\x49\x4d\x5b\xae\x4d\x62\xb9\x66\xa5\xd9\xb0\x2c\x78\x44\xb5\x6c\xec\x18\xfc\x97\x1e\xf7\xa1\x98\xc4\xb9\x8b\xc2\xfb\x44\x34\xf4\x38\x86\x07\x07\x3f\x84\xe6\x51\xfb\xe9\xb7\x77\x26\x75\xae\x8f\x76\x48\xcd\xfb\xc1\xc3\xef\x32\xbb\x54\x5a\x48\x7b\xd2\x05\x86\x45\xc2\x84\x65\x87\x57\xff\x1e\xe4\xf1\x38\x01\x74\x30\xcc\xfd\xc3\xab\xa9\x9e\x8b\x4f\xa6\x87\x0d\x5c\x04\x00\x80\x92\x9a\x70\xc2\xbb\xec\x53\xb6\x5c\x65\xb7\x59\x3e\xcf\x36\xf3\x2f\xf9\xdd\x6c\x91\x67\xab\x4d\x3e\xfb\x9c\xdd\x2e\x67\xf3\x0c\x83\x1b\x18\xe4\x1f\x74\xdb\xec\x11\x00\x58\x09\xae\xcb\x15\x57\x07\x77\x00\x48\x4a\xac\x87\x57\x92\x02\x76\xd7\x03\xe2\x45\xb1\x1c\x26\xd8\x6d\x4f\x1c\xcc\x18\x15\xb6\x61\xa8\x0c\xcb\xdc\xff\x3f\xff\x5e\x32\x3b\x37\x2e\xd2\x8f\x47\xef\x4e\x73\xd3\x76\x67\x2c\xe0\x33\xeb\x38\xa5\xff\xf2\x57\xb4\x4d\x43\xb2\x7c\x2b\x8b\x3b\xdc\x2f\x2e\x5f\xf9\xa6\x0d\x5d\x20\x06\xef\xa3\x87\xe8\xe7\x00\xf0\xd7\x8b\x2b\x5e\x05\x00\x00")
 
 func templateStrategiesTelepresenceTplBytes() ([]byte, error) {
 	return bindataRead(
@@ -183,7 +378,7 @@ func templateStrategiesTelepresenceTpl() (*asset, error) {
 	return a, nil
 }
 
-var _templateStrategiesTelepresenceVar = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2a\x4b\x2d\x2a\xce\xcc\xcf\xb3\xe5\x02\x04\x00\x00\xff\xff\xa0\x0b\xe9\x34\x09\x00\x00\x00")
+var _templateStrategiesTelepresenceVar = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x28\x00\xd7\xff\x76\x65\x72\x73\x69\x6f\x6e\x3d\x0a\x63\x6f\x6e\x74\x61\x69\x6e\x65\x72\x3d\x0a\x73\x75\x62\x73\x65\x74\x4c\x61\x62\x65\x6c\x3d\x76\x65\x72\x73\x69\x6f\x6e\x0a\x03\x00\x8d\x18\xd7\x3d\x28\x00\x00\x00")
 
 func templateStrategiesTelepresenceVarBytes() ([]byte, error) {
 	return bindataRead(
@@ -203,6 +398,126 @@ func templateStrategiesTelepresenceVar() (*asset, error) {
 	return a, nil
 }
 
+var _templateStrategiesTelepresence2Tpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x92\xcd\x6a\xeb\x30\x10\x85\xf7\x7e\x8a\x61\x56\xf7\x82\x23\x93\x6d\xd6\x5d\xf4\x1d\x4a\x29\x63\xe9\x24\x55\x91\x25\x21\x4d\xb3\x31\xea\xb3\x17\xe3\xb6\xd4\xd0\x9f\xa4\x4b\xc1\x39\x9f\x66\x3e\xe6\xae\x23\x9a\x67\x52\x4c\x39\x88\x82\xf8\x61\x94\xea\xed\xee\x8c\x52\x7d\x8a\x4c\x86\x5a\xeb\xd6\x90\x3f\x52\x4c\x4a\xff\xcc\x8d\xa8\x98\x5b\xa9\xc4\x43\xcd\xb0\xc3\x7b\x7b\x7d\x15\xe4\xe0\xad\x54\xfe\xbf\x54\x89\x66\x4e\x99\x0f\xc4\xe2\x1c\xf7\xc4\x59\xf4\x91\x0f\xbf\x54\x7b\xe2\xb3\x84\x67\xf0\x81\xe6\xd6\xfa\xf5\x7f\x44\xb7\x25\x2e\x71\xb1\xf8\x0b\x95\xf7\xdc\xfa\x4f\xa8\x9f\x87\x9b\xa0\xe2\x44\x65\x08\x32\x22\xd4\x41\x11\x90\x0b\x2a\xa2\xc5\x86\xea\xa3\xa2\x58\x64\x7d\xa3\x5f\x22\xed\x03\x2e\x31\x26\x15\xf5\x29\x5e\xe9\xee\x4b\xc2\x65\x0a\xaf\x07\x6f\x96\x37\x27\xa8\x4c\xa3\xd4\x2a\x2e\x15\xe3\xd3\xcb\xde\xc7\x27\x58\xdd\x69\x91\xe3\xd1\xdb\x9d\x9c\x10\x75\xe3\x08\x51\xc6\x00\xb7\x18\xfa\xe6\xf8\x0a\xa6\x74\x06\x93\xa1\xd6\xba\xfb\xee\x75\x00\x51\x1a\x1f\x6d\xa4\x02\x00\x00")
+
+func templateStrategiesTelepresence2TplBytes() ([]byte, error) {
+	return bindataRead(
+		_templateStrategiesTelepresence2Tpl,
+		"template/strategies/telepresence2.tpl",
+	)
+}
+
+func templateStrategiesTelepresence2Tpl() (*asset, error) {
+	bytes, err := templateStrategiesTelepresence2TplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "template/strategies/telepresence2.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templateStrategiesTelepresence2Var = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1f\x00\xe0\xff\x63\x6f\x6e\x74\x61\x69\x6e\x65\x72\x3d\x0a\x73\x75\x62\x73\x65\x74\x4c\x61\x62\x65\x6c\x3d\x76\x65\x72\x73\x69\x6f\x6e\x0a\x03\x00\xc1\x8b\x63\xf3\x1f\x00\x00\x00")
+
+func templateStrategiesTelepresence2VarBytes() ([]byte, error) {
+	return bindataRead(
+		_templateStrategiesTelepresence2Var,
+		"template/strategies/telepresence2.var",
+	)
+}
+
+func templateStrategiesTelepresence2Var() (*asset, error) {
+	bytes, err := templateStrategiesTelepresence2VarBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "template/strategies/telepresence2.var", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templateStrategiesTunnelTpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x53\x4d\x6b\x1b\x31\x10\xbd\xef\xaf\x18\x06\x1f\x1c\xd8\x0f\x7a\x35\xf4\xd4\x18\x6a\x52\xdc\x1e\xd2\x5c\x4c\x30\x13\xed\xd8\x15\xd9\x95\x16\x49\x35\x01\x31\xff\xbd\x48\x6b\xa7\x2e\x9b\x92\x38\x39\xad\x56\x7a\xf3\xde\xe3\x3d\x29\x46\xd8\x91\xee\x56\xbb\x3b\x72\x9a\x1e\x3a\xbe\xb6\xec\xd7\x36\x2c\x9f\xb4\x0f\x50\xdf\x91\xf3\x80\xba\xa7\x3d\x23\x54\x22\xc5\x9b\xf0\xc1\x3e\xb2\x79\xc6\xcf\x14\x2c\x3e\x43\x7d\x4d\x81\xea\x2f\xd6\x04\xd2\x86\xdd\xca\xb4\xfc\x04\x73\x9d\x3f\xc7\x31\x75\x3a\xc4\x2b\x10\x29\x36\x05\x40\x8c\x10\xb8\x1f\x3a\x0a\x0c\xb8\x7d\x20\xaf\x55\x75\x60\xe7\xb5\x35\x08\x75\x42\x8d\x20\xbd\x03\x63\x03\xcc\x47\x95\xaf\xe4\x01\x1b\x3f\xb0\x6a\x4e\xd3\xe3\x9f\xe3\xa1\xd3\x8a\xfc\x28\x00\x10\xd1\x0e\xb8\x00\xa4\xb6\xc5\x12\x70\xa0\xf0\x0b\x17\xaf\x8c\x96\x80\x07\xea\x7e\x33\x2e\x20\x8a\x94\xa3\x3e\x9b\xf6\x5f\xc6\x04\x27\xc5\xef\x61\xc5\x4f\x28\xe5\xe5\x54\xcf\xe9\xf9\x26\xc6\x99\x12\x69\xc6\xda\xce\x99\x63\xcc\x51\xd7\xf9\x44\xe4\xa8\x93\xe3\xfb\x9b\xdc\x7c\x70\xda\x84\x57\x45\x10\x66\x0a\xb0\x21\xb7\x9f\xc4\xe9\xb8\xb7\x87\x77\x18\xce\x5c\xd3\x48\x5f\xaa\xf7\x32\x93\x6c\x0e\x78\x75\x79\xe7\x53\x87\x89\xe8\x2c\xd0\xcd\xfd\xd4\xed\x07\xe9\x9b\xea\x5c\x20\xa2\xa1\x3e\x2d\x70\x75\xb3\xdc\xde\xfe\x5c\xaf\x97\xdf\xb6\xb7\xdf\x6f\x96\xeb\x17\x7b\xcd\x0f\x2f\xf5\x2a\xe5\x47\xad\x28\xdb\xf7\x64\xda\x73\x99\x0d\xea\xc7\xdc\x2a\xed\xd9\x84\xb4\xa8\xaa\x34\xe8\x6c\x57\x0d\xd6\xe5\x9d\x93\x93\xe3\xfe\x0f\xeb\x82\x08\xa6\x98\xfe\xf3\x9a\x8f\x77\x05\x6a\x10\x29\xee\x8b\x3f\x03\x00\x18\xbf\xe1\xd2\x90\x04\x00\x00")
+
+func templateStrategiesTunnelTplBytes() ([]byte, error) {
+	return bindataRead(
+		_templateStrategiesTunnelTpl,
+		"template/strategies/tunnel.tpl",
+	)
+}
+
+func templateStrategiesTunnelTpl() (*asset, error) {
+	bytes, err := templateStrategiesTunnelTplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "template/strategies/tunnel.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templateStrategiesTunnelVar = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x3f\x00\xc0\xff\x69\x6d\x61\x67\x65\x3d\x0a\x63\x6f\x6e\x74\x72\x6f\x6c\x50\x6f\x72\x74\x3d\x31\x30\x31\x30\x31\x0a\x74\x6f\x6b\x65\x6e\x3d\x0a\x63\x6f\x6e\x74\x61\x69\x6e\x65\x72\x3d\x0a\x73\x75\x62\x73\x65\x74\x4c\x61\x62\x65\x6c\x3d\x76\x65\x72\x73\x69\x6f\x6e\x0a\x03\x00\x5e\xfe\xf6\xd5\x3f\x00\x00\x00")
+
+func templateStrategiesTunnelVarBytes() ([]byte, error) {
+	return bindataRead(
+		_templateStrategiesTunnelVar,
+		"template/strategies/tunnel.var",
+	)
+}
+
+func templateStrategiesTunnelVar() (*asset, error) {
+	bytes, err := templateStrategiesTunnelVarBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "template/strategies/tunnel.var", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configCrdBasesMaistraIo_sessionsYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x5a\x5f\x6f\xe3\xb8\x11\x7f\xd7\xa7\x18\x5c\x1f\xee\x65\xad\xdc\xa2\x28\x50\xe8\x2d\xcd\x1e\xba\x87\x6e\xdb\x45\xb2\xb8\x3e\x2c\x16\x87\xb1\x38\x96\x79\xa1\x48\x95\x43\x39\x71\x8b\x7e\xf7\x62\x28\xca\x96\x93\x48\x96\xbd\xd9\x1c\x70\x48\x7c\xc0\xad\x28\x6a\xfe\xfc\x38\x7f\x7e\xa4\x94\x2d\x16\x8b\x0c\x1b\xfd\x33\x79\xd6\xce\x16\x80\x8d\xa6\xfb\x40\x56\xae\x38\xbf\xfd\x33\xe7\xda\x5d\x6c\xde\x66\xb7\xda\xaa\x02\xae\x5a\x0e\xae\xbe\x26\x76\xad\x2f\xe9\x1d\xad\xb4\xd5\x41\x3b\x9b\xd5\x14\x50\x61\xc0\x22\x03\x40\x6b\x5d\x40\x19\x66\xb9\x04\x28\x9d\x0d\xde\x19\x43\x7e\x51\x91\xcd\x6f\xdb\x25\x2d\x5b\x6d\x14\xf9\x28\xbc\x57\xbd\xf9\x21\xff\x53\xfe\x43\x06\x50\x7a\x8a\x8f\x7f\xd2\x35\x71\xc0\xba\x29\xc0\xb6\xc6\x64\x00\x16\x6b\x2a\x80\x89\xe5\x09\xce\x6b\xd4\x1c\x3c\xe6\xda\x65\xdc\x50\x29\xda\x2a\xef\xda\xa6\x80\xc1\x9d\xee\xa9\x64\x4a\xe7\xc6\x4d\x27\x20\x8e\x18\xcd\xe1\x6f\xc3\xd1\x0f\x9a\x43\xbc\xd3\x98\xd6\xa3\xd9\xab\x8b\x83\xac\x6d\xd5\x1a\xf4\xbb\xe1\x0c\x80\x4b\xd7\x50\x01\xff\xc0\x9a\xb8\xc1\x92\x54\x06\x90\xbc\x8a\x6a\x17\x80\x4a\x45\x9c\xd0\x7c\xf4\xda\x06\xf2\x57\xce\xb4\x75\x8f\xcf\x02\x14\x71\xe9\x75\x23\x53\x0a\xf0\xb4\x62\x58\x92\xb6\x15\xd4\x68\x75\xd3\x1a\x0c\xa4\x60\xb9\x85\xb0\xd6\x3c\xd0\x2b\xcf\xfe\xca\xce\x7e\xc4\xb0\x2e\x20\xe7\x80\xa1\xe5\xfc\x17\x4f\xab\x68\x4a\x9a\x21\xde\x17\x70\x4d\x2b\x18\x8e\x86\xad\x98\xcc\xc1\x6b\x5b\x3d\x65\x84\xe0\x1a\xa8\xd2\xc4\xd0\x72\xa7\xfd\xa8\xe2\xfd\x33\x69\x4e\xa7\xfa\xe6\xe1\xf0\x31\xdd\x74\xdf\x38\xd1\xb9\x76\x1c\x18\x56\xce\xcf\x74\x3c\xce\x4f\xb7\x3b\xd5\xef\x07\x23\xc7\xb4\x7a\xd7\x06\x02\xba\x6f\x7c\xe7\x67\xe7\xf7\x7c\xed\xf1\xf9\x1f\xef\x9b\x43\xd8\x65\x70\xdc\x80\x81\x98\x3e\x83\xf2\x47\xd1\x7f\x20\xf0\xb2\x3a\x14\xa7\x30\xc9\xef\x1c\xde\xbc\x45\xd3\xac\xf1\x6d\x1c\xe2\x72\x4d\x75\x4c\x49\xb9\x72\x0d\xd9\xcb\x8f\x3f\xfd\xfc\xc7\x9b\x83\x61\x38\x44\x21\x25\x41\x9f\xb3\x0c\x61\x4d\xbb\x84\x04\xb7\x8a\xd7\x92\x6c\x1a\x8d\xfe\x8f\x2c\x92\x56\x8a\x2c\x44\xef\x39\xdf\x09\x6d\xbc\x6b\xc8\x07\xdd\x27\x5e\xf7\x1b\x54\x9a\xc1\xe8\x03\x13\xbe\x17\x2b\xbb\x59\xa0\xa4\xc4\x50\x67\x45\x4a\x29\x52\xc9\xb1\xce\x1a\xcd\xe0\x49\xd6\x8c\x6c\xd8\x19\x89\x16\xdc\xf2\x57\x2a\x43\x0e\x37\xe4\xe5\x41\xe0\xb5\x6b\x8d\x12\xbf\x36\xe4\x03\x78\x2a\x5d\x65\xa3\x07\x9d\x34\x86\xe0\xa2\x1a\xc9\x36\x0e\x10\xd3\xd4\xa2\x81\x0d\x9a\x96\xde\x00\x5a\x05\x35\x6e\xc1\x93\xc8\x85\xd6\x0e\x24\xc4\x29\x9c\xc3\xdf\x9d\x27\xd0\x76\xe5\x0a\x58\x87\xd0\x70\x71\x71\x51\xe9\xd0\x57\xd1\xd2\xd5\x75\x6b\x75\xd8\x5e\x44\x70\xf5\xb2\x0d\xce\xf3\x85\xa2\x0d\x99\x0b\xd6\xd5\x02\x7d\xb9\xd6\x81\xca\xd0\x7a\xba\xc0\x46\x2f\xa2\xb1\x56\x9c\xe2\xbc\x56\x7f\xf0\xa9\xee\xf2\xf7\x07\xe0\x3d\x8a\xab\xee\xbf\x58\xea\x26\x50\x96\xa2\x07\x9a\x01\xd3\xa3\x9d\xa3\x7b\x30\x65\x48\xf0\xb8\xfe\xf1\xe6\x13\xf4\xaa\xbb\x4c\xec\xb0\xdd\x4f\xe5\x3d\xcc\x02\x91\xb6\x2b\x4a\x59\xb3\xf2\xae\x8e\xa8\x92\x55\x8d\xd3\x36\xc4\x8b\xd2\x68\xb2\x01\xb8\x5d\xd6\x3a\xc8\xfa\xfd\xbb\x25\x49\xf5\xe0\x72\xb8\x8a\xed\x03\x96\x04\x6d\x23\xc1\xad\x72\xf8\xc9\xc2\x15\xd6\x64\xae\x90\xe9\x9b\x83\x2c\x68\xf2\x42\xc0\x9b\x07\xf3\xb0\xf3\xed\xff\x44\x4a\x91\x70\x1a\xdc\xe8\xbb\xd4\xc8\x9a\xdc\x34\x54\x1e\x84\xbc\x22\xd6\x5e\x42\x34\xf4\x69\x3e\x9d\x5f\xf2\xf3\xb4\x7a\x38\xf4\x40\xcd\xbf\xd6\xae\x4f\x87\x06\x7d\xd0\xa5\x6e\x30\x48\xe0\x46\x9d\x95\xde\x90\x7d\x50\xee\xf6\x3f\x1d\xa8\x7e\xa4\xf2\x91\x06\xe9\x36\xbd\x1f\x6b\x77\x17\x53\x0b\x7d\x45\x41\xa2\x4d\xdb\xca\x10\xbc\xa3\xc6\xb8\x6d\x2d\x71\xe0\xfc\xe0\xea\xca\xd9\x95\xae\xf2\xec\x81\xf8\x49\x97\x53\x71\xf1\xd5\xc8\x1d\x38\x68\xc0\xd3\x52\x26\x96\x7a\xd4\xdd\xcb\x9d\x74\x40\x5f\xb5\xe2\xd4\xae\x9c\x24\x38\xbb\x1e\xb8\x1d\x91\x36\x12\x2e\xfb\x5f\xac\xee\x73\x4c\x99\x86\x35\x36\x91\x37\x50\xc6\x52\xe8\xa2\xf5\x68\xcc\x56\xaa\x62\x40\x6d\xe1\xb3\x14\x85\x8b\x2f\x42\x14\xc4\xfe\x65\x2a\xf5\x2b\x5d\x66\x67\x22\x95\x38\xc1\x76\x96\xf1\xef\x25\x54\xa4\xc0\xa8\xbd\x17\x29\x50\x97\x04\x6b\xb4\xca\x90\x7a\x03\x94\x57\x39\x04\x32\xa9\xfc\x94\x24\xae\x36\x9e\x1a\xf4\xa4\x16\xba\xc6\x4a\xca\x44\x6b\x82\x6e\x0c\x81\x54\x04\x04\x96\xbb\x52\x4e\x7a\x8b\x84\xd9\xa0\x27\xc0\xa6\x31\x9a\xd4\x61\xf4\x3b\xaf\xc8\x27\x45\x87\x82\xdf\xd0\x7d\xf0\xb8\x20\xbb\x39\x0f\x91\xc9\x95\xee\x6e\xa2\xf7\xf8\x30\x50\x62\x7b\x2d\xb2\xe3\xe8\xb9\xc4\x63\xf6\xbe\xa4\x8e\x9e\xc3\x25\xac\x09\x15\x79\x58\xa2\x30\x9b\x6e\x5e\x2b\x84\x16\xee\x17\x77\xce\xdf\x46\xf2\xba\xe8\xc6\xef\x74\x58\xc7\xf8\xed\x09\x81\x44\x0e\x20\xa7\x2e\x71\xa7\x8d\x89\x35\x5a\x24\xe9\x15\x48\xc9\x6e\xbc\xdb\x68\x45\x2a\xcf\x4e\xcb\xdc\xf1\xd8\x3e\x70\x2f\x06\x65\x22\x20\xb7\xb4\x4d\xab\x23\x7d\x36\xf9\x95\x9d\xbb\x1c\xc7\x75\x7f\x5a\x53\x94\x24\xdc\x22\xe1\xeb\x84\x1f\xf6\x46\x9c\xad\x3f\xa2\x39\xc3\x79\x31\x20\xce\x4d\x8a\x23\x2b\x16\x4b\x9e\x16\x7b\x44\xef\x44\x10\x72\xbb\x64\x0a\x1f\x70\x49\xa6\xc8\x26\x4d\x1a\xae\x47\xe3\x14\x18\x79\x46\x40\x51\x62\xa3\x56\xd2\x53\x57\xdb\x21\x73\x93\x86\xcf\x14\x38\xa1\xd6\x8f\x3a\x2f\x49\x18\xb7\x85\xde\x52\x20\x1e\xec\x0a\x73\x78\x47\xd2\xac\x49\x0d\xa8\xc4\xbd\xe6\x20\x51\xfb\x8e\xe4\xff\x91\xf4\x5d\xb7\x86\x7a\xf1\x52\x0d\xca\xd8\x45\x5a\xe9\x9d\xae\x4b\x6c\x89\x40\x0c\xce\x83\x11\x2a\x70\x3c\x66\x47\x01\x1c\x01\xaf\xdb\x06\x14\xd9\x28\x5e\x37\x71\xc2\x41\x7b\x2f\x5b\xef\x63\x8d\xeb\x6e\x25\x30\x6f\x4e\x68\xf6\xdd\x9e\xa7\x98\xdf\xa6\x67\xc4\xc5\x53\xf5\x67\xb7\xa9\x7c\x09\x55\x69\x23\x75\x24\xfe\xae\xd3\xb4\x7e\xbb\xb6\x67\xa3\x11\xc5\x78\x3b\x2d\x93\xd4\xad\x44\x3b\x12\xd7\xdd\x6f\xf3\xb2\x13\xcc\xfe\x65\xdf\x3b\xbe\x3d\x0e\xa5\xb3\x1d\xab\xe0\x23\x40\x48\x6d\x28\x5d\xbd\xd4\x96\x14\x18\x57\x49\x52\x96\x6b\xb4\x95\x74\xb8\xd2\x3b\x66\x40\x63\xe2\xb1\xc2\x7c\xa3\x0f\xa1\xa6\x55\x7f\xe6\x13\x77\x0c\x16\xe4\x8c\xc8\x0b\xdf\xd9\x8d\x87\x35\x06\x58\xa3\x9c\x5c\x90\x3d\x38\xb8\xd0\x16\xd8\xd5\x04\x77\xb8\x7d\x9c\x66\xc7\x9a\x03\x00\x96\xe1\xd1\x7e\x71\xc4\x4e\x41\xe2\x32\x4e\xef\xcc\xb9\x43\x86\x86\xfc\xca\xf9\x7a\xc7\x1b\xe2\x5e\x56\xae\x56\xa8\x23\x9b\x18\x98\x3a\xa2\x63\x72\x01\x9f\xde\x6a\x4d\x18\xb8\x43\x4c\xb8\xd6\xb9\x1a\x0d\x72\xf8\xe4\xd1\x72\x8c\x10\x39\x2a\x98\xa5\xff\xc3\xa3\xc7\x64\x3d\x25\x5d\x44\x20\x04\x19\x88\xfb\xb6\x0e\xf4\x08\x60\x22\x48\x23\xe2\x05\x5b\x0c\xdd\x39\xc4\x42\x9e\x3f\xd7\xa3\x9a\x98\xb1\x9a\xe7\xc6\xfb\xb6\x46\x49\x78\x54\xb8\x34\x24\xff\x60\x67\xd3\x51\x0d\xa5\xd8\x3f\xd7\x8e\xd9\x4c\xfb\x60\x2d\xa5\x36\x9e\xab\x51\x12\xe0\x37\xdf\xb7\xec\xb3\x30\xe2\x98\x4e\x78\x76\x0e\xf6\x3d\x7b\x7f\x24\xf7\x57\x0c\x74\x87\x5b\xce\x9e\x50\x32\xde\x25\xf7\x7f\xdd\xa2\x15\x73\xcc\xfc\xe8\x5d\xe5\xb1\xae\x31\xe8\xf2\x79\x17\xfb\xa9\xa6\x3d\x62\xc4\x5f\x9c\x33\x84\x76\xcf\xc2\xb4\x55\xba\x94\x7d\x33\xb7\x65\x49\xcc\xe7\xda\x30\x4e\x41\x27\x48\xe8\xd7\xb8\x3d\xb9\x34\xe3\xfd\x48\xfa\x47\x91\x4d\x5a\x98\x28\x4e\xe2\x31\xd7\x72\x8e\xad\xed\x70\x17\x71\x7e\xff\xd9\x8b\x46\x1b\xa1\xdf\x68\xd5\xc6\xfe\xb3\x7a\xa0\xe3\xf5\xe8\xe0\x77\x72\x74\xb0\x3b\xee\x9c\x65\xfd\x65\x7c\x93\xd3\x73\xe8\x5d\xdd\x02\x6d\x37\xce\x6c\x3a\x1a\x52\xa3\x8e\xc6\xca\xe6\x21\xb6\x39\x3f\x38\x9e\x9f\x1d\x9a\x2f\x4d\x90\xe6\x85\xf0\x1c\xb2\xf4\x72\x94\x69\xe6\x12\x1f\xa7\x4f\x23\x26\xcf\x21\x51\x27\xd9\x30\x9f\x50\xbd\x08\xad\x3a\x8d\x5c\x9d\xe4\xe9\x11\xa2\xf5\xbc\x74\xeb\x24\xcb\x2c\x9e\x84\xfa\x5c\x02\x76\x92\x0d\x53\x64\xec\x9c\x7e\x70\x92\xf2\xf1\xde\xf0\xdc\xf4\x6c\x66\xef\x98\x43\xd5\x9e\x93\xb0\x9d\x84\xd6\x34\x79\x7b\x46\x0a\x77\x92\x55\x53\x74\xee\x6b\x48\xdd\x09\x46\xcc\x58\xd6\x71\x9a\xf7\x7a\x6c\xff\xd4\xb1\xfd\x91\x83\xd1\x91\x85\x3d\xed\x58\xb4\xe7\x2e\xdd\x9b\x32\x3e\xd7\xd0\xf4\xf8\x2c\x23\x85\x34\xed\x38\xd3\x3f\x23\x87\xec\x4e\x70\xe5\xc0\x2c\x56\x96\x67\x20\x47\x11\x33\x52\x0f\x39\xd2\x01\x69\x8a\xaf\x1b\xe2\xc4\xc1\x37\x04\xaf\x14\xe8\x85\x29\x90\xc0\xff\x1b\x36\xbf\x99\xed\xe8\x95\xaa\xbd\x52\xb5\x57\xaa\xf6\x4a\xd5\x7e\x0f\x54\x4d\x3e\x7d\x92\x2f\x7c\xc6\xcd\x58\xa4\xba\x9c\x7d\x45\x64\x4c\xb3\xbd\x49\x01\x5f\xf7\x49\xc2\xa7\xc1\x4b\xce\xc1\x2b\xd9\xa7\x8f\x5d\xa6\xdb\xfb\x78\xc5\x19\x7d\x2b\xfd\xfa\x95\xc0\x37\xfb\x4a\x40\x5e\x51\x17\xd9\x6c\x71\x4f\x8a\x7a\x34\xc8\xe4\x37\xa4\x0a\x08\xbe\xed\xa2\x83\x83\xf3\xd2\xff\x06\x23\xed\xf2\xd1\xb1\x60\x2a\x2e\xf0\xdf\xff\x65\xfb\x3a\x83\x65\x49\x4d\x20\x35\x78\x69\x1d\x8f\x99\xe0\xbb\xef\x0e\x3e\xee\x8e\x97\x83\x57\xad\xf0\xf9\x8b\x7c\xd1\x1d\x9c\x27\x95\x3e\x42\xe5\x02\x3e\x7f\xc9\xfe\x3f\x00\x90\xbe\x60\xd7\x2a\x2f\x00\x00")
+
+func configCrdBasesMaistraIo_sessionsYamlBytes() ([]byte, error) {
+	return bindataRead(
+		_configCrdBasesMaistraIo_sessionsYaml,
+		"config/crd/bases/maistra.io_sessions.yaml",
+	)
+}
+
+func configCrdBasesMaistraIo_sessionsYaml() (*asset, error) {
+	bytes, err := configCrdBasesMaistraIo_sessionsYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/crd/bases/maistra.io_sessions.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configInstallBasesOperatorYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcc\x56\xcb\x6e\xe3\xb8\x12\xdd\xeb\x2b\x88\xde\x04\x68\x40\x8e\x93\x46\x03\x81\x76\xce\xe3\xe6\x2e\xd2\x89\x11\x7b\x66\x96\x8d\x32\x55\xb6\x39\xe1\xab\x8b\x94\x32\xce\xd7\x0f\x28\x4b\x8a\x24\xcb\x8e\xec\xcc\xa2\xe1\x00\xa1\x58\xe4\xa9\x53\x6f\x82\x15\x7f\x22\x39\x61\x74\xc2\xf2\x8b\xe8\x45\xe8\x34\x61\x8f\xa0\xd0\x59\xe0\x18\x29\xf4\x90\x82\x87\x24\x62\x4c\xc2\x02\xa5\x0b\x2b\xc6\xb8\xd1\x9e\x8c\x8c\xad\x04\x8d\x49\xf5\x29\x91\x62\x05\x1a\x56\x48\x11\x63\x1a\x14\x26\x4c\x38\x2f\x4c\xfc\x6a\xe8\xa5\x40\x8c\x8d\x45\x02\x6f\x28\x76\x1b\xe7\x51\x45\x71\x1c\x47\xbd\x24\x66\x48\xb9\xe0\x38\xe1\xdc\x64\xda\xb7\x98\x7c\x80\xdc\x11\x94\x17\x8a\xf5\xf1\x7c\x68\x01\x7c\x04\x99\x5f\x1b\x12\x6f\xe0\x85\xd1\xa3\x97\x2b\x37\x12\xe6\xbc\x66\x7a\x23\x33\xe7\x91\x9e\x8d\x6c\x3b\x8c\x13\x16\x17\xe6\x42\xa1\xf3\xa0\x6c\xc2\x74\x26\xe5\xf1\x06\x50\x26\xd1\x25\x51\xcc\xc0\x8a\x7b\x32\x99\x2d\xa2\x10\xb3\xb3\xb3\x88\x31\x42\x67\x32\xe2\x58\xee\x71\xa3\x97\x62\xa5\xc0\xba\xe2\x08\xea\xd4\x1a\xa1\x7d\xf9\x95\x63\xb5\xb4\xc1\x40\xe7\x51\xfb\xdc\xc8\x4c\x21\x97\x20\x54\x29\x32\xe9\x76\xe1\x90\x13\x96\xe7\xdd\x36\x1c\xe1\x23\x47\x5a\x54\x0c\xbe\x9e\x0d\xa3\x55\x47\xa0\x03\xb0\x42\xbf\x0b\x00\xd6\xba\x5d\x88\x14\x50\x19\xed\x2a\x42\x29\x5a\x69\x36\xaa\x36\x88\xd0\x4a\xc1\xa1\x96\x3b\x0f\x1e\x97\x99\x2c\x37\x3e\x22\xdd\xd1\x59\x94\xc0\x56\xd2\x8d\xc6\x2e\xb1\x77\x26\xe7\x4b\xa1\x41\x8a\x37\xa4\x8e\xce\xcc\xa6\xe0\xb1\x5f\xed\xc8\x58\xd4\x6e\x2d\x96\x7e\x24\xcc\x21\xf8\x6d\x6c\x87\x58\x53\x70\x6e\xe0\x72\xa3\x76\x81\xcf\xbe\x9e\x0d\x80\x52\x20\x9c\x27\xe8\xa5\xf6\x79\x04\x87\x2e\xd4\x59\xc7\xa6\xa2\x72\xb0\xb4\x5e\xa2\xc7\x3a\x57\xc2\x7f\x29\xdc\x76\x61\xc1\xf3\x75\xd3\xbd\x61\xf9\x5a\x6c\x9e\x44\xe2\xe8\xe8\x0d\x02\x0d\x89\x98\x75\x00\x57\xb8\xc7\x82\x5d\x15\x46\x0b\x6f\x48\xe8\xd5\x88\x1b\x42\xe3\xfa\x63\x59\x96\x67\x79\x7a\xbf\x3b\x7b\xeb\x4d\xa3\x0f\xe9\x1d\x74\x6c\x13\x67\x78\xac\x3f\xd5\x2d\xaf\x85\x4e\x85\x5e\x1d\xd3\xdb\xcb\xf1\x12\x93\x91\xb8\x28\xaf\x87\xf5\x33\x2e\x03\xcd\xca\xb0\x03\x4c\x22\xc6\x76\xdb\xf6\xd1\x2d\xd9\x65\x8b\xbf\x91\xfb\xa2\x2b\xf7\xce\xab\xdf\x6e\x4a\xed\x8c\xa7\x0f\xf8\x49\x84\x14\x29\x46\x89\x3c\xe0\x15\x1e\x3f\x8e\xe4\xc1\xb1\x15\x26\x95\xa1\x54\xe8\x4e\x6c\x0e\x8f\x33\x89\xe0\x70\x4f\x2d\xd5\x6d\xe1\xb5\x6e\x0b\x8d\xc4\x2f\xeb\xab\x5d\x74\x65\x73\xe9\x67\xd8\x61\x52\xcf\xce\xfe\xc2\xda\x82\x9e\x18\x97\x13\x0a\xa1\x2f\x3c\x55\x41\x1c\x17\xa5\xd3\xaa\x67\x58\xd9\xf4\x26\xd1\x6f\x56\x3b\x61\x04\xbf\x87\xe3\xb6\x9e\xb6\x7b\x1f\xbf\x60\xed\x8e\x96\xff\xea\x55\xbc\xf7\xce\x40\xe3\x9c\x45\x1e\x42\x59\xbd\x86\x12\x76\x11\x31\xe6\x8a\x2a\x36\x14\x24\x8c\xa9\x90\xaa\x0f\x0d\x83\x86\x31\xf7\xa8\xac\x04\x8f\x25\x48\xc3\x39\x8c\xb5\x1d\x74\xd0\x49\x43\xd5\x31\x56\xd9\x52\xdd\x00\xa1\x91\x6a\x15\x31\x03\x5a\x35\x14\x86\x89\x4b\x79\x53\x87\x52\xa0\xd3\xe6\x01\xf1\xf2\x2e\x46\x9d\x37\x45\x21\x7f\x12\xf6\xd7\x64\x7e\xf3\xff\x9f\x8f\x93\x1f\x77\xb3\xe9\xe4\xe6\xae\x96\x33\x96\x83\xcc\x30\x61\x5f\xbe\xec\xdc\x99\x3e\xdd\x16\x37\xba\x87\xff\x47\x46\xbd\x6b\x08\xbf\xa5\x40\x99\x96\xa5\xd6\xfc\x15\xfb\x53\xf0\xeb\xa4\xf6\xea\x28\xf0\xd9\xab\xaa\x97\xdc\xe7\xf5\xb5\x63\x54\x29\x7d\x9a\xde\x3d\x4f\xe6\x4f\xcf\xbd\x46\xee\x0f\x71\x75\x7d\xf6\xc7\xf5\xec\x6e\xfe\xf3\x61\x72\x7d\xf7\x70\xd0\x9f\x42\xc1\x0a\x13\xf6\x2b\x83\x4d\xe8\x8f\xe5\xd3\xea\xbc\x83\x9f\xe4\xe3\xd1\xb7\xd1\x38\xd6\xf8\x8f\x6f\x5f\x9d\x66\x52\x4e\x8d\x14\x7c\x93\xb0\x89\x7c\x85\x8d\xab\xe5\x52\xe4\xa8\xd1\xb9\x29\x99\x45\x99\xbe\xdb\xbf\xb5\xf7\xf6\x1e\x7d\xdb\x41\xb6\xf0\xcc\xf9\x1a\x41\xfa\xf5\x5b\x5b\x64\xc8\x27\xec\xea\xf2\xea\xb2\xb1\x2d\xb4\xf0\x02\xe4\x2d\x4a\xd8\xcc\x90\x1b\x9d\xba\x84\x5d\x7c\x6f\x9c\xb0\x48\xc2\xa4\xb5\xec\x72\x5c\xcb\x7a\xbb\x41\x2d\x25\x84\x54\xf4\x12\x5f\x82\x90\x19\xe1\x7c\x4d\xe8\xd6\x46\xa6\x09\xbb\x18\x0f\xb4\x2b\x80\x6e\x4e\x35\xeb\x72\x90\x55\xad\xc9\x59\xfd\xa4\x50\xc2\xb7\x76\x18\xe3\x36\x4b\xd8\xe5\x78\xac\x5a\xbb\x0a\x95\xa1\x4d\xc2\xbe\x8f\x7f\x88\x86\x80\xf0\x57\x86\xee\x64\x08\xd7\x9a\x31\x8f\x87\xdb\x70\x7f\x44\x3c\x92\x2a\x1f\x2c\xf7\x04\x1c\xa7\x6d\x0f\x7c\x1b\x47\xff\x0e\x00\x99\x9b\x83\xcd\x4f\x11\x00\x00")
+
+func configInstallBasesOperatorYamlBytes() ([]byte, error) {
+	return bindataRead(
+		_configInstallBasesOperatorYaml,
+		"config/install/bases/operator.yaml",
+	)
+}
+
+func configInstallBasesOperatorYaml() (*asset, error) {
+	bytes, err := configInstallBasesOperatorYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/install/bases/operator.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -255,12 +570,27 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"template/strategies/_basic-remove.tpl":  templateStrategies_basicRemoveTpl,
-	"template/strategies/_basic-version.tpl": templateStrategies_basicVersionTpl,
-	"template/strategies/prepared-image.tpl": templateStrategiesPreparedImageTpl,
-	"template/strategies/prepared-image.var": templateStrategiesPreparedImageVar,
-	"template/strategies/telepresence.tpl":   templateStrategiesTelepresenceTpl,
-	"template/strategies/telepresence.var":   templateStrategiesTelepresenceVar,
+	"template/strategies/_basic-remove.tpl":     templateStrategies_basicRemoveTpl,
+	"template/strategies/_basic-version.tpl":    templateStrategies_basicVersionTpl,
+	"template/strategies/_basic.tpl":            templateStrategies_basicTpl,
+	"template/strategies/debug.tpl":             templateStrategiesDebugTpl,
+	"template/strategies/debug.var":             templateStrategiesDebugVar,
+	"template/strategies/extra-env.smp.yaml":    templateStrategiesExtraEnvSmpYaml,
+	"template/strategies/extra-env.var":         templateStrategiesExtraEnvVar,
+	"template/strategies/mirrord.tpl":           templateStrategiesMirrordTpl,
+	"template/strategies/mirrord.var":           templateStrategiesMirrordVar,
+	"template/strategies/prepared-image.tpl":    templateStrategiesPreparedImageTpl,
+	"template/strategies/prepared-image.var":    templateStrategiesPreparedImageVar,
+	"template/strategies/sync.tpl":              templateStrategiesSyncTpl,
+	"template/strategies/sync.var":              templateStrategiesSyncVar,
+	"template/strategies/telepresence.tpl":      templateStrategiesTelepresenceTpl,
+	"template/strategies/telepresence.var":      templateStrategiesTelepresenceVar,
+	"template/strategies/telepresence2.tpl":     templateStrategiesTelepresence2Tpl,
+	"template/strategies/telepresence2.var":     templateStrategiesTelepresence2Var,
+	"template/strategies/tunnel.tpl":            templateStrategiesTunnelTpl,
+	"template/strategies/tunnel.var":            templateStrategiesTunnelVar,
+	"config/crd/bases/maistra.io_sessions.yaml": configCrdBasesMaistraIo_sessionsYaml,
+	"config/install/bases/operator.yaml":        configInstallBasesOperatorYaml,
 }
 
 // AssetDir returns the file names below a certain
@@ -304,14 +634,39 @@ type bintree struct {
 }
 
 var _bintree = &bintree{nil, map[string]*bintree{
+	"config": &bintree{nil, map[string]*bintree{
+		"crd": &bintree{nil, map[string]*bintree{
+			"bases": &bintree{nil, map[string]*bintree{
+				"maistra.io_sessions.yaml": &bintree{configCrdBasesMaistraIo_sessionsYaml, map[string]*bintree{}},
+			}},
+		}},
+		"install": &bintree{nil, map[string]*bintree{
+			"bases": &bintree{nil, map[string]*bintree{
+				"operator.yaml": &bintree{configInstallBasesOperatorYaml, map[string]*bintree{}},
+			}},
+		}},
+	}},
 	"template": &bintree{nil, map[string]*bintree{
 		"strategies": &bintree{nil, map[string]*bintree{
 			"_basic-remove.tpl":  &bintree{templateStrategies_basicRemoveTpl, map[string]*bintree{}},
 			"_basic-version.tpl": &bintree{templateStrategies_basicVersionTpl, map[string]*bintree{}},
+			"_basic.tpl":         &bintree{templateStrategies_basicTpl, map[string]*bintree{}},
+			"debug.tpl":          &bintree{templateStrategiesDebugTpl, map[string]*bintree{}},
+			"debug.var":          &bintree{templateStrategiesDebugVar, map[string]*bintree{}},
+			"extra-env.smp.yaml": &bintree{templateStrategiesExtraEnvSmpYaml, map[string]*bintree{}},
+			"extra-env.var":      &bintree{templateStrategiesExtraEnvVar, map[string]*bintree{}},
+			"mirrord.tpl":        &bintree{templateStrategiesMirrordTpl, map[string]*bintree{}},
+			"mirrord.var":        &bintree{templateStrategiesMirrordVar, map[string]*bintree{}},
 			"prepared-image.tpl": &bintree{templateStrategiesPreparedImageTpl, map[string]*bintree{}},
 			"prepared-image.var": &bintree{templateStrategiesPreparedImageVar, map[string]*bintree{}},
+			"sync.tpl":           &bintree{templateStrategiesSyncTpl, map[string]*bintree{}},
+			"sync.var":           &bintree{templateStrategiesSyncVar, map[string]*bintree{}},
 			"telepresence.tpl":   &bintree{templateStrategiesTelepresenceTpl, map[string]*bintree{}},
 			"telepresence.var":   &bintree{templateStrategiesTelepresenceVar, map[string]*bintree{}},
+			"telepresence2.tpl":  &bintree{templateStrategiesTelepresence2Tpl, map[string]*bintree{}},
+			"telepresence2.var":  &bintree{templateStrategiesTelepresence2Var, map[string]*bintree{}},
+			"tunnel.tpl":         &bintree{templateStrategiesTunnelTpl, map[string]*bintree{}},
+			"tunnel.var":         &bintree{templateStrategiesTunnelVar, map[string]*bintree{}},
 		}},
 	}},
 }}
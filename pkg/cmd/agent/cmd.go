@@ -0,0 +1,50 @@
+package agent
+
+import (
+	"net"
+	"os"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+
+	"github.com/maistra/istio-workspace/pkg/log"
+	"github.com/maistra/istio-workspace/pkg/tunnel"
+)
+
+var logger = func() logr.Logger {
+	return log.Log.WithValues("type", "agent")
+}
+
+// NewCmd creates instance of "ike agent" Cobra Command which is intended to be ran in the cloned pod
+// as it forwards the inbound traffic to the developer machine using the built-in tunnel.
+func NewCmd() *cobra.Command {
+	agentCmd := &cobra.Command{
+		Use:          "agent",
+		Short:        "Starts the tunnel agent forwarding inbound traffic of the pod to ike develop",
+		Hidden:       true,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			token := os.Getenv(tunnel.TokenEnvVar)
+			if token == "" {
+				return errors.Errorf("%s environment variable with the session token is not set", tunnel.TokenEnvVar)
+			}
+			controlPort, _ := cmd.Flags().GetInt("control-port")
+			// reached through the port-forward only, which connects to the loopback of the pod
+			control, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(controlPort)))
+			if err != nil {
+				return errors.WrapWithDetails(err, "failed listening for control connections", "port", controlPort)
+			}
+			logger().Info("waiting for control connections", "port", controlPort)
+
+			return tunnel.NewAgent("", token).Serve(signals.SetupSignalHandler(), control)
+		},
+	}
+
+	agentCmd.Flags().Int("control-port", tunnel.DefaultControlPort, "port to listen on for control connections")
+
+	return agentCmd
+}
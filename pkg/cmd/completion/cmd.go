@@ -1,9 +1,8 @@
 package completion
 
 import (
-	"bytes"
+	"fmt"
 	"io"
-	"os"
 
 	"emperror.dev/errors"
 	"github.com/spf13/cobra"
@@ -16,6 +15,12 @@ const (
 
   ### generate completion code for zsh
   source <(ike completion zsh)
+
+  ### generate completion code for fish
+  ike completion fish | source
+
+  ### generate completion code for PowerShell
+  ike completion powershell | Out-String | Invoke-Expression
 `
 )
 
@@ -24,16 +29,22 @@ func NewCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:          "completion [SHELL]",
 		Short:        "Prints shell completion scripts",
-		Long:         "This command provides shell completion code for bash and zsh",
+		Long:         "This command provides shell completion code for bash, zsh, fish and PowerShell",
 		Example:      eg,
 		SilenceUsage: true,
-		ValidArgs:    []string{"bash", "zsh"},
+		ValidArgs:    []string{"bash", "zsh", "fish", "powershell"},
+		Args:         cobra.ExactValidArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
 			switch args[0] {
 			case "bash":
-				return errors.WrapIf(cmd.Root().GenBashCompletion(os.Stdout), "failed configuring autocompletion for bash")
+				return errors.WrapIf(cmd.Root().GenBashCompletion(out), "failed configuring autocompletion for bash")
 			case "zsh":
-				return errors.WrapIf(runCompletionZsh(os.Stdout, cmd.Root()), "failed configuring autocompletion for zsh")
+				return errors.WrapIf(runCompletionZsh(out, cmd.Root()), "failed configuring autocompletion for zsh")
+			case "fish":
+				return errors.WrapIf(cmd.Root().GenFishCompletion(out, true), "failed configuring autocompletion for fish")
+			case "powershell":
+				return errors.WrapIf(cmd.Root().GenPowerShellCompletion(out), "failed configuring autocompletion for PowerShell")
 			}
 
 			return nil
@@ -41,38 +52,13 @@ func NewCmd() *cobra.Command {
 	}
 }
 
-// runCompletionZsh generate the zsh completion the same way kubectl is doing it
-// https://git.io/fjRRc
-// We are not using the builtin zsh completion that comes from cobra but instead doing it
-// via bashcompinit and use the GenBashCompletion then
-// This allows us the user to simply do a `source <(ike completion zsh)` to get
-// zsh completion.
+// runCompletionZsh generates the zsh completion and registers it, so the user can simply do
+// `source <(ike completion zsh)` instead of placing the script in one of the directories of $fpath.
 func runCompletionZsh(out io.Writer, ike *cobra.Command) error {
-	zshHead := "#compdef ike\n"
-
-	if _, err := out.Write([]byte(zshHead)); err != nil {
-		return errors.Wrap(err, "failed to write to out stream")
-	}
-
-	if _, err := out.Write([]byte(zshInitialization)); err != nil {
-		return errors.Wrap(err, "failed to write to out stream")
+	if err := ike.GenZshCompletion(out); err != nil {
+		return errors.Wrap(err, "failed to generate zsh completion")
 	}
-
-	buf := new(bytes.Buffer)
-	if err := ike.GenBashCompletion(buf); err != nil {
-		return errors.Wrap(err, "failed to generate bash completion")
-	}
-	if _, err := out.Write(buf.Bytes()); err != nil {
-		return errors.Wrap(err, "failed to write to out stream")
-	}
-
-	zshTail := `
-BASH_COMPLETION_EOF
-}
-__ike_bash_source <(__ike_convert_bash_to_zsh)
-_complete ike 2>/dev/null
-`
-	if _, err := out.Write([]byte(zshTail)); err != nil {
+	if _, err := fmt.Fprintf(out, "compdef _%[1]s %[1]s\n", ike.Name()); err != nil {
 		return errors.Wrap(err, "failed to write to out stream")
 	}
 
@@ -1,225 +1,243 @@
 package completion
 
-import "github.com/spf13/cobra"
+import (
+	"context"
+	"sort"
+	"strings"
 
-var (
-	// bashCompletionFlags maps between a flag (ie: namespace) to a custom shell completion.
-	bashCompletionFlags = map[string]string{
-		"namespace": "__kubectl_get_object namespace",
-		// Telepresence has one flag (deployment) which works for both k8s Deployment and Openshift DeploymentConfig
-		// Hence we combine output for retrieving both object sets
-		"deployment": "__kubectl_get_object_combined deployment deploymentconfig",
-	}
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	istiov1alpha1 "github.com/maistra/istio-workspace/api/maistra/v1alpha1"
+	"github.com/maistra/istio-workspace/pkg/cmd/strategy"
+	"github.com/maistra/istio-workspace/pkg/internal/session"
+	"github.com/maistra/istio-workspace/pkg/kubeconfig"
+	"github.com/maistra/istio-workspace/pkg/log"
+	"github.com/maistra/istio-workspace/pkg/routing"
 )
 
-// AddFlagCompletion adds custom bash completion function defined through bashCompletionFlags map.
-func AddFlagCompletion(cmd *cobra.Command) {
-	for name, completion := range bashCompletionFlags {
-		pflag := cmd.Flags().Lookup(name)
-		if pflag != nil {
-			if pflag.Annotations == nil {
-				pflag.Annotations = map[string][]string{}
-			}
-			pflag.Annotations[cobra.BashCompCustom] = append(
-				pflag.Annotations[cobra.BashCompCustom],
-				completion,
-			)
-		}
-	}
+var logger = func() logr.Logger {
+	return log.Log.WithValues("type", "completion")
 }
 
-const (
-	// BashCompletionFunc the custom bash completion function to complete object.
-	// The bash completion mechanism will launch the __custom_func func if it cannot
-	// find any completion and pass the object in the ${last_command}
-	// variable.
-	//
-	// Additional tweaks:
-	// - namespace-aware completion: whenever namespace has been specified before any namespace-bound parameter (such as --deployment)
-	// we are narrowing the results to that specific namespace (see __kubectl_get)
-	// - combining object sets: we can combine results of multiple kubectl_ctl get by calling __kubectl_get_object_combined with multiple types combined.
-	BashCompletionFunc = `
-__ike_get_object()
-{
-	local type=$1
-	local template
-	template="{{ range .items  }}{{ .metadata.name }} {{ end }}"
-	local ike_out
-	if ike_out=$(ike ${type} ls -o template --template="${template}" 2>/dev/null); then
-		COMPREPLY=( $( compgen -W "${ike_out}" -- "$cur" ) )
-	fi
+// completionFunc provides dynamic completion of a flag or an argument, see cobra.Command.ValidArgsFunction.
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// flagCompletions maps between a flag (ie: namespace) and the function completing its values.
+var flagCompletions = map[string]completionFunc{
+	"namespace":   Namespaces,
+	"session":     Sessions,
+	"deployment":  Deployments,
+	"strategy":    Strategies,
+	"merge-patch": Strategies,
+	"route":       Routes,
 }
 
-__kubectl_get_object_combined()
-{
-	for type in "$@"
-	do
-		COMPREPLY+=($(__kubectl_get $type))
-	done
+// workloads are the kinds which can be targeted by --deployment, completed as kind/name.
+var workloads = []struct {
+	kind     string
+	resource schema.GroupVersionResource
+}{
+	{"deployment", schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}},
+	{"deploymentconfig", schema.GroupVersionResource{Group: "apps.openshift.io", Version: "v1", Resource: "deploymentconfigs"}},
+	{"statefulset", schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}},
 }
 
-__kubectl_get_object()
-{
-    COMPREPLY=($(__kubectl_get $1))
+var namespaces = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+// SessionClient creates the client used to look up sessions in the given namespace.
+var SessionClient = session.DefaultClient
+
+// Clients creates the client used to look up cluster resources together with the namespace of the current context.
+var Clients = func() (dynamic.Interface, string, error) {
+	restCfg, namespace, err := kubeconfig.Load()
+	if err != nil {
+		return nil, "", err
+	}
+	c, err := dynamic.NewForConfig(restCfg)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed creating dynamic client")
+	}
+
+	return c, namespace, nil
 }
 
-__get_selected_namespace() {
-	local namespace
-	namespace=$(echo "${COMP_WORDS[@]}" | sed -E 's/.*(--namespace[= ]+|-n +)([A-Za-z_-]+).*/\2/')
-	if [[ $namespace = *" "* ]]; then
-	    namespace=""
-	fi
-	echo ${namespace}
+// AddFlagCompletion registers dynamic completion of the flags defined through flagCompletions map.
+// Completions are implemented in Go, so they work the same way for all the shells supported by cobra.
+func AddFlagCompletion(cmd *cobra.Command) {
+	for name, complete := range flagCompletions {
+		if cmd.LocalFlags().Lookup(name) == nil {
+			continue
+		}
+		if err := cmd.RegisterFlagCompletionFunc(name, complete); err != nil {
+			logger().Error(err, "failed registering flag completion", "command", cmd.Name(), "flag", name)
+		}
+	}
 }
 
-__kubectl_get()
-{
-	local template="{{ range .items  }}{{ .metadata.name }} {{ end }}"
-    local type=$1
-    local kubectl_out
-	local namespace=$(__get_selected_namespace)
-    if kubectl_out=$(kubectl get $( [[ ! -z "${namespace}" ]] && printf %s "-n ${namespace}" ) -o template --template="${template}" ${type} 2>/dev/null); then
-        echo $( compgen -W "${kubectl_out}" -- "$cur" )
-		return
-    fi
-	echo ""
+// Namespaces completes names of the namespaces in the cluster.
+func Namespaces(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	selectCluster(cmd)
+	c, _, err := Clients()
+	if err != nil {
+		return failed(err)
+	}
+	list, err := c.Resource(namespaces).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return failed(err)
+	}
+	names := make([]string, 0, len(list.Items))
+	for i := range list.Items {
+		names = append(names, list.Items[i].GetName())
+	}
+
+	return matching(names, toComplete), cobra.ShellCompDirectiveNoFileComp
 }
 
-__custom_func() {
-	case ${last_command} in
-		*_describe|*_logs)
-			obj=${last_command/ike_/};
-			obj=${obj/_describe/}; obj=${obj/_logs/};
-			__ike_get_object ${obj}
-			return
-			;;
-		*)
-			;;
-	esac
+// Sessions completes names of the sessions existing in the namespace selected by --namespace flag
+// or the one of the current context.
+func Sessions(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	selectCluster(cmd)
+	sessions, err := listSessions(cmd)
+	if err != nil {
+		return failed(err)
+	}
+	names := make([]string, 0, len(sessions))
+	for i := range sessions {
+		names = append(names, sessions[i].Name)
+	}
+
+	return matching(names, toComplete), cobra.ShellCompDirectiveNoFileComp
 }
-`
-	zshInitialization = `
-__ike_bash_source() {
-	alias shopt=':'
-	alias _expand=_bash_expand
-	alias _complete=_bash_comp
-	emulate -L sh
-	setopt kshglob noshglob braceexpand
-	source "$@"
+
+// Deployments completes Deployments, DeploymentConfigs and StatefulSets of the namespace prefixed by their kind,
+// e.g. deployment/ratings-v1. Kinds not known to the cluster, such as DeploymentConfig outside of OpenShift, are skipped.
+func Deployments(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	selectCluster(cmd)
+	c, namespace, err := Clients()
+	if err != nil {
+		return failed(err)
+	}
+	if ns := namespaceOf(cmd); ns != "" {
+		namespace = ns
+	}
+	var names []string
+	for _, workload := range workloads {
+		list, err := c.Resource(workload.resource).Namespace(namespace).List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			logger().V(1).Info("skipping completion", "kind", workload.kind, "reason", err.Error())
+
+			continue
+		}
+		for i := range list.Items {
+			names = append(names, workload.kind+"/"+list.Items[i].GetName())
+		}
+	}
+
+	return matching(names, toComplete), cobra.ShellCompDirectiveNoFileComp
 }
-__ike_type() {
-	# -t is not supported by zsh
-	if [ "$1" == "-t" ]; then
-		shift
-		# fake Bash 4 to disable "complete -o nospace". Instead
-		# "compopt +-o nospace" is used in the code to toggle trailing
-		# spaces. We don't support that, but leave trailing spaces on
-		# all the time
-		if [ "$1" = "__ike_compopt" ]; then
-			echo builtin
-			return 0
-		fi
-	fi
-	type "$@"
+
+// Strategies completes names of the built-in strategies and the custom ones defined in the namespace.
+// Flags accepting several strategies separated by comma, such as --merge-patch, are completed one by one.
+func Strategies(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	selectCluster(cmd)
+	configMaps, err := strategy.ClusterConfigMaps(namespaceOf(cmd))
+	if err != nil {
+		logger().V(1).Info("completing only local strategies", "reason", err.Error())
+	}
+	completed, current := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		completed, current = toComplete[:i+1], toComplete[i+1:]
+	}
+	var names []string
+	for _, s := range strategy.Catalog(configMaps) {
+		if strings.HasPrefix(s.Name, current) {
+			names = append(names, completed+s.Name)
+		}
+	}
+
+	return names, cobra.ShellCompDirectiveNoFileComp
 }
-__ike_compgen() {
-	local completions w
-	completions=( $(compgen "$@") ) || return $?
-	# filter by given word as prefix
-	while [[ "$1" = -* && "$1" != -- ]]; do
-		shift
-		shift
-	done
-	if [[ "$1" == -- ]]; then
-		shift
-	fi
-	for w in "${completions[@]}"; do
-		if [[ "${w}" = "$1"* ]]; then
-			echo "${w}"
-		fi
-	done
+
+// Routes completes the type and name of the routes, e.g. header:x-workspace-route=, used by the existing sessions.
+// The value is left to the user.
+func Routes(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if strings.Contains(toComplete, "=") {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	selectCluster(cmd)
+	routes := []string{"header:" + routing.DefaultRouteHeaderName + "="}
+	sessions, err := listSessions(cmd)
+	if err != nil {
+		logger().V(1).Info("completing only default route", "reason", err.Error())
+	}
+	for i := range sessions {
+		if route := sessions[i].Spec.Route; route.Type != "" && route.Name != "" {
+			routes = append(routes, route.Type+":"+route.Name+"=")
+		}
+	}
+
+	return matching(routes, toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
 }
-__ike_compopt() {
-	true # don't do anything. Not supported by bashcompinit in zsh
+
+func listSessions(cmd *cobra.Command) ([]istiov1alpha1.Session, error) {
+	c, err := SessionClient(namespaceOf(cmd))
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := c.List(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions.Items, nil
 }
-__ike_ltrim_colon_completions()
-{
-	if [[ "$1" == *:* && "$COMP_WORDBREAKS" == *:* ]]; then
-		# Remove colon-word prefix from COMPREPLY items
-		local colon_word=${1%${1##*:}}
-		local i=${#COMPREPLY[*]}
-		while [[ $((--i)) -ge 0 ]]; do
-			COMPREPLY[$i]=${COMPREPLY[$i]#"$colon_word"}
-		done
-	fi
+
+// selectCluster applies global flags selecting the cluster, as completion is invoked without running the root command
+// which normally takes care of that.
+func selectCluster(cmd *cobra.Command) {
+	selection := kubeconfig.Selected()
+	for name, value := range map[string]*string{
+		"kubeconfig": &selection.Kubeconfig,
+		"context":    &selection.Context,
+		"cluster":    &selection.Cluster,
+	} {
+		if flag := cmd.Root().PersistentFlags().Lookup(name); flag != nil && flag.Changed {
+			*value = flag.Value.String()
+		}
+	}
+	kubeconfig.Select(selection)
 }
-__ike_get_comp_words_by_ref() {
-	cur="${COMP_WORDS[COMP_CWORD]}"
-	prev="${COMP_WORDS[${COMP_CWORD}-1]}"
-	words=("${COMP_WORDS[@]}")
-	cword=("${COMP_CWORD[@]}")
+
+func namespaceOf(cmd *cobra.Command) string {
+	if flag := cmd.Flag("namespace"); flag != nil {
+		return flag.Value.String()
+	}
+
+	return ""
 }
-__ike_filedir() {
-	local RET OLD_IFS w qw
-	if [[ "$1" = \~* ]]; then
-		# somehow does not work. Maybe, zsh does not call this at all
-		eval echo "$1"
-		return 0
-	fi
-	OLD_IFS="$IFS"
-	IFS=$'\n'
-	if [ "$1" = "-d" ]; then
-		shift
-		RET=( $(compgen -d) )
-	else
-		RET=( $(compgen -f) )
-	fi
-	IFS="$OLD_IFS"
-	IFS=","
-	for w in ${RET[@]}; do
-		if [[ ! "${w}" = "${cur}"* ]]; then
-			continue
-		fi
-		if eval "[[ \"\${w}\" = *.$1 || -d \"\${w}\" ]]"; then
-			qw="$(__ike_quote "${w}")"
-			if [ -d "${w}" ]; then
-				COMPREPLY+=("${qw}/")
-			else
-				COMPREPLY+=("${qw}")
-			fi
-		fi
-	done
+
+// matching returns sorted unique candidates starting with the text typed so far.
+func matching(candidates []string, toComplete string) []string {
+	unique := map[string]bool{}
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, toComplete) && !unique[candidate] {
+			unique[candidate] = true
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Strings(matches)
+
+	return matches
 }
-__ike_quote() {
-	if [[ $1 == \'* || $1 == \"* ]]; then
-		# Leave out first character
-		printf %q "${1:1}"
-	else
-	printf %q "$1"
-	fi
+
+func failed(err error) ([]string, cobra.ShellCompDirective) {
+	logger().V(1).Info("failed completing", "reason", err.Error())
+
+	return nil, cobra.ShellCompDirectiveError
 }
-autoload -U +X bashcompinit && bashcompinit
-# use word boundary patterns for BSD or GNU sed
-LWORD='[[:<:]]'
-RWORD='[[:>:]]'
-if sed --help 2>&1 | grep -q GNU; then
-	LWORD='\<'
-	RWORD='\>'
-fi
-__ike_convert_bash_to_zsh() {
-	sed \
-	-e 's/declare -F/whence -w/' \
-	-e 's/_get_comp_words_by_ref "\$@"/_get_comp_words_by_ref "\$*"/' \
-	-e 's/local \([a-zA-Z0-9_]*\)=/local \1; \1=/' \
-	-e 's/flags+=("\(--.*\)=")/flags+=("\1"); two_word_flags+=("\1")/' \
-	-e 's/must_have_one_flag+=("\(--.*\)=")/must_have_one_flag+=("\1")/' \
-	-e "s/${LWORD}_filedir${RWORD}/__ike_filedir/g" \
-	-e "s/${LWORD}_get_comp_words_by_ref${RWORD}/__ike_get_comp_words_by_ref/g" \
-	-e "s/${LWORD}__ltrim_colon_completions${RWORD}/__ike_ltrim_colon_completions/g" \
-	-e "s/${LWORD}compgen${RWORD}/__ike_compgen/g" \
-	-e "s/${LWORD}compopt${RWORD}/__ike_compopt/g" \
-	-e "s/${LWORD}declare${RWORD}/builtin declare/g" \
-	-e "s/\\\$(type${RWORD}/\$(__ike_type/g" \
-	<<'BASH_COMPLETION_EOF'
-`
-)
@@ -0,0 +1,166 @@
+package completion_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	istiov1alpha1 "github.com/maistra/istio-workspace/api/maistra/v1alpha1"
+	"github.com/maistra/istio-workspace/pkg/client/clientset/versioned/fake"
+	. "github.com/maistra/istio-workspace/pkg/cmd"
+	"github.com/maistra/istio-workspace/pkg/cmd/completion"
+	"github.com/maistra/istio-workspace/pkg/cmd/create"
+	"github.com/maistra/istio-workspace/pkg/cmd/status"
+	"github.com/maistra/istio-workspace/pkg/cmd/strategy"
+	"github.com/maistra/istio-workspace/pkg/internal/session"
+	. "github.com/maistra/istio-workspace/test"
+)
+
+var _ = Describe("Dynamic shell completion", func() {
+
+	var (
+		rootCmd               *cobra.Command
+		originalSessionClient = completion.SessionClient
+		originalClients       = completion.Clients
+		originalConfigMaps    = strategy.ClusterConfigMaps
+	)
+
+	BeforeEach(func() {
+		rootCmd = NewCmd()
+		rootCmd.SilenceUsage = true
+		rootCmd.AddCommand(create.NewCmd(), status.NewCmd())
+		VisitAll(rootCmd, completion.AddFlagCompletion)
+
+		sessions := fake.NewSimpleClientset(
+			testSession("test", "feature-x", "header", "x-feature"),
+			testSession("test", "bugfix-y", "", ""),
+			testSession("other", "feature-z", "header", "x-other"),
+		)
+		completion.SessionClient = func(namespace string) (*session.Client, error) {
+			if namespace == "" {
+				namespace = "test"
+			}
+
+			return session.NewClient(sessions, namespace)
+		}
+
+		client := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{
+				{Version: "v1", Resource: "namespaces"}:                                    "NamespaceList",
+				{Group: "apps", Version: "v1", Resource: "deployments"}:                    "DeploymentList",
+				{Group: "apps", Version: "v1", Resource: "statefulsets"}:                   "StatefulSetList",
+				{Group: "apps.openshift.io", Version: "v1", Resource: "deploymentconfigs"}: "DeploymentConfigList",
+			},
+			object("v1", "Namespace", "", "test"),
+			object("v1", "Namespace", "", "other"),
+			object("apps/v1", "Deployment", "test", "ratings-v1"),
+			object("apps/v1", "Deployment", "other", "reviews-v1"),
+			object("apps/v1", "StatefulSet", "test", "redis"),
+		)
+		// DeploymentConfigs are not known outside of OpenShift
+		client.PrependReactor("list", "deploymentconfigs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewNotFound(schema.GroupResource{Group: "apps.openshift.io", Resource: "deploymentconfigs"}, "")
+		})
+		completion.Clients = func() (dynamic.Interface, string, error) {
+			return client, "test", nil
+		}
+		strategy.ClusterConfigMaps = func(namespace string) ([]corev1.ConfigMap, error) {
+			return nil, apierrors.NewServiceUnavailable("cluster not reachable")
+		}
+	})
+
+	AfterEach(func() {
+		completion.SessionClient = originalSessionClient
+		completion.Clients = originalClients
+		strategy.ClusterConfigMaps = originalConfigMaps
+	})
+
+	It("should complete sessions of the current namespace", func() {
+		output, err := Run(rootCmd).Passing("__complete", "create", "--session", "")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(HavePrefix("bugfix-y\nfeature-x\n:4\n"))
+	})
+
+	It("should complete sessions of the selected namespace as argument", func() {
+		output, err := Run(rootCmd).Passing("__complete", "status", "-n", "other", "f")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(HavePrefix("feature-z\n:4\n"))
+	})
+
+	It("should complete deployments and stateful sets prefixed by their kind", func() {
+		output, err := Run(rootCmd).Passing("__complete", "create", "--deployment", "")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(HavePrefix("deployment/ratings-v1\nstatefulset/redis\n:4\n"))
+	})
+
+	It("should complete namespaces", func() {
+		output, err := Run(rootCmd).Passing("__complete", "create", "--namespace", "o")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(HavePrefix("other\n:4\n"))
+	})
+
+	It("should complete each of comma separated strategies", func() {
+		output, err := Run(rootCmd).Passing("__complete", "create", "--merge-patch", "extra-env,tele")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(HavePrefix("extra-env,telepresence\nextra-env,telepresence2\n:4\n"))
+	})
+
+	It("should complete route types and names used by existing sessions", func() {
+		output, err := Run(rootCmd).Passing("__complete", "create", "--route", "header:x-")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(HavePrefix("header:x-feature=\nheader:x-workspace-route=\n:6\n"))
+	})
+
+	It("should not complete route values", func() {
+		output, err := Run(rootCmd).Passing("__complete", "create", "--route", "header:x-feature=")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(HavePrefix(":4\n"))
+	})
+
+	It("should generate completion scripts for all supported shells", func() {
+		for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+			completionCmd := completion.NewCmd()
+			NewCmd().AddCommand(completionCmd)
+
+			output, err := Run(completionCmd).Passing(shell)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output).To(ContainSubstring("__complete"), shell)
+		}
+	})
+})
+
+func testSession(namespace, name, routeType, routeName string) *istiov1alpha1.Session {
+	return &istiov1alpha1.Session{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: istiov1alpha1.SessionSpec{
+			Route: istiov1alpha1.Route{Type: routeType, Name: routeName, Value: name},
+		},
+	}
+}
+
+func object(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+	o := &unstructured.Unstructured{}
+	o.SetAPIVersion(apiVersion)
+	o.SetKind(kind)
+	o.SetNamespace(namespace)
+	o.SetName(name)
+
+	return o
+}
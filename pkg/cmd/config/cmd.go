@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"emperror.dev/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const none = "<none>"
+
+// NewCmd creates instance of "config" Cobra Command with "view" sub-command.
+func NewCmd() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:          "config",
+		Short:        "Inspects configuration of ike commands",
+		SilenceUsage: true,
+	}
+
+	configCmd.AddCommand(newViewCmd())
+
+	return configCmd
+}
+
+func newViewCmd() *cobra.Command {
+	viewCmd := &cobra.Command{
+		Use:   "view [COMMAND] [-- FLAGS]",
+		Short: "Shows effective configuration values and where they come from",
+		Long: "Shows effective values of the flags merged from the config file, the active profile and environment variables, " +
+			"together with the source of each of them.\n\n" +
+			"Without COMMAND only the values which are not defaults are shown. Flags passed after -- are applied to COMMAND, " +
+			"e.g. 'ike config view develop --profile staging -- --port 9090'.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names, flagArgs := args, []string{}
+			if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+				names, flagArgs = args[:dash], args[dash:]
+			}
+
+			commands := configurableCommands(cmd.Root())
+			showAll, _ := cmd.Flags().GetBool("all")
+			if len(names) > 0 {
+				target, rest, err := cmd.Root().Find(names)
+				if err != nil || len(rest) > 0 || target == cmd.Root() {
+					return errors.Errorf("unknown command %q", strings.Join(names, " "))
+				}
+				if err := target.Flags().Parse(flagArgs); err != nil {
+					return errors.Wrapf(err, "failed parsing flags of %s", target.Name())
+				}
+				commands, showAll = []*cobra.Command{target}, true
+			} else if len(flagArgs) > 0 {
+				return errors.New("flags can only be passed together with the command they belong to")
+			}
+
+			return errors.Wrap(printEffective(cmd.OutOrStdout(), commands, showAll), "failed printing configuration")
+		},
+	}
+
+	viewCmd.Flags().Bool("all", false, "shows default values of all the commands as well")
+
+	return viewCmd
+}
+
+func configurableCommands(root *cobra.Command) []*cobra.Command {
+	var commands []*cobra.Command
+	for _, c := range root.Commands() {
+		if c.Hidden || c.Name() == "help" || c.Name() == "config" {
+			continue
+		}
+		if c.Runnable() && c.HasAvailableLocalFlags() {
+			commands = append(commands, c)
+		}
+		commands = append(commands, configurableCommands(c)...)
+	}
+
+	return commands
+}
+
+func printEffective(out io.Writer, commands []*cobra.Command, showAll bool) error {
+	configFile := viper.ConfigFileUsed()
+	if configFile == "" {
+		configFile = none
+	}
+	profile := ActiveProfile()
+	if profile == "" {
+		profile = none
+	}
+	_, _ = fmt.Fprintf(out, "Config file: %s\n", configFile)
+	_, _ = fmt.Fprintf(out, "Profile: %s\n\n", profile)
+
+	w := tabwriter.NewWriter(out, 0, 8, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+	for _, c := range commands {
+		c.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+			if flag.Hidden || flag.Name == "help" {
+				return
+			}
+			value, source := Describe(c, flag)
+			if source == SourceDefault && !showAll {
+				return
+			}
+			if value == "" {
+				value = `""`
+			}
+			_, _ = fmt.Fprintf(w, "%s.%s\t%s\t%s\n", c.Name(), flag.Name, value, source)
+		})
+	}
+
+	return errors.Wrap(w.Flush(), "failed flushing")
+}
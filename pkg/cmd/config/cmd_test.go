@@ -27,6 +27,7 @@ var _ = Describe("Usage of ike command configuration", func() {
 		testCmd.SilenceUsage = true
 		testCmd.SilenceErrors = true
 		NewCmd().AddCommand(testCmd)
+		testCmd.Flags().VisitAll(config.BindFullyQualifiedFlag(testCmd))
 	})
 
 	Context("load from environment", func() {
@@ -130,6 +131,152 @@ test:
 
 	})
 
+	Context("default values", func() {
+
+		AfterEach(func() {
+			CleanUpTmpFiles(GinkgoT())
+		})
+
+		It("should not mark flag as changed when only its default value is present", func() {
+			output, err := Run(testCmd).Passing("--arg", expectedValue)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring("format changed : false"))
+		})
+
+		It("should mark flag as changed when the value comes from the configuration", func() {
+			configFile := TmpFile(GinkgoT(), "env_config.yaml", `test:
+    format: json`)
+			output, err := Run(testCmd).Passing("--config", configFile.Name(), "--arg", expectedValue)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring("format changed : true"))
+		})
+
+	})
+
+	Context("load from profile", func() {
+
+		profiles := fmt.Sprintf(`test:
+    arg: %v
+profiles:
+    staging:
+        test:
+            arg: %v
+    perf:
+        extends: staging
+    loop:
+        extends: cycle
+    cycle:
+        extends: loop`, otherValue, expectedValue)
+
+		AfterEach(func() {
+			CleanUpTmpFiles(GinkgoT())
+		})
+
+		It("should use profile over top level config", func() {
+			configFile := TmpFile(GinkgoT(), "profile_config.yaml", profiles)
+			output, err := Run(testCmd).Passing("--config", configFile.Name(), "--profile", "staging")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring(expectedValue))
+		})
+
+		It("should inherit settings of extended profile", func() {
+			configFile := TmpFile(GinkgoT(), "profile_config.yaml", profiles)
+			output, err := Run(testCmd).Passing("--config", configFile.Name(), "--profile", "perf")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring(expectedValue))
+		})
+
+		It("should select profile from env", func() {
+			defer TemporaryEnvVars("IKE_PROFILE", "staging")()
+			configFile := TmpFile(GinkgoT(), "profile_config.yaml", profiles)
+			output, err := Run(testCmd).Passing("--config", configFile.Name())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring(expectedValue))
+		})
+
+		It("should use top level config when no profile is selected", func() {
+			configFile := TmpFile(GinkgoT(), "profile_config.yaml", profiles)
+			output, err := Run(testCmd).Passing("--config", configFile.Name())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring(otherValue))
+		})
+
+		It("should use arguments over profile", func() {
+			configFile := TmpFile(GinkgoT(), "profile_config.yaml", profiles)
+			output, err := Run(testCmd).Passing("--config", configFile.Name(), "--profile", "staging", "--arg", "from-flag")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring("from-flag"))
+		})
+
+		It("should fail on unknown profile", func() {
+			configFile := TmpFile(GinkgoT(), "profile_config.yaml", profiles)
+			_, err := Run(testCmd).Passing("--config", configFile.Name(), "--profile", "qa")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("profile qa not found, available profiles: [cycle loop perf staging]"))
+		})
+
+		It("should fail on cyclic profiles", func() {
+			configFile := TmpFile(GinkgoT(), "profile_config.yaml", profiles)
+			_, err := Run(testCmd).Passing("--config", configFile.Name(), "--profile", "loop")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("profile loop extends itself"))
+		})
+	})
+
+	Context("view effective configuration", func() {
+
+		var viewCmd *cobra.Command
+
+		BeforeEach(func() {
+			configCmd := config.NewCmd()
+			testCmd.Root().AddCommand(configCmd)
+			viewCmd, _, _ = configCmd.Find([]string{"view"})
+		})
+
+		AfterEach(func() {
+			CleanUpTmpFiles(GinkgoT())
+		})
+
+		It("should show values of the profile and where they come from", func() {
+			configFile := TmpFile(GinkgoT(), "view_config.yaml", `profiles:
+    staging:
+        test:
+            arg: staged
+            other: from-profile
+test:
+    other: from-file`)
+			defer TemporaryEnvVars("IKE_TEST_ARG", "from-env")()
+
+			output, err := Run(viewCmd).Passing("--config", configFile.Name(), "--profile", "staging")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring("Profile: staging"))
+			Expect(output).To(MatchRegexp(`test.arg\s+from-env\s+env IKE_TEST_ARG`))
+			Expect(output).To(MatchRegexp(`test.other\s+from-profile\s+profile staging`))
+			Expect(output).ToNot(ContainSubstring("test.flag"))
+		})
+
+		It("should show values of the file and flags of the command", func() {
+			configFile := TmpFile(GinkgoT(), "view_config.yaml", `test:
+    other: from-file`)
+
+			output, err := Run(viewCmd).Passing("test", "--config", configFile.Name(), "--", "--arg", "from-flag")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring("Profile: <none>"))
+			Expect(output).To(MatchRegexp(`test.arg\s+from-flag\s+flag`))
+			Expect(output).To(MatchRegexp(`test.other\s+from-file\s+file`))
+			Expect(output).To(MatchRegexp(`test.flag\s+false\s+default`))
+		})
+
+		It("should fail on unknown command", func() {
+			_, err := Run(viewCmd).Passing("unknown")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`unknown command "unknown"`))
+		})
+	})
+
 })
 
 func NewTestCmd() *cobra.Command {
@@ -143,12 +290,16 @@ func NewTestCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			value, _ := cmd.Flags().GetString("arg")
 			cmd.Println(expectedOutput, ":", value)
+			cmd.Println("format changed :", cmd.Flag("format").Changed)
 
 			return nil
 		},
 	}
 
 	testCmd.Flags().StringP("arg", "a", "", "test argument")
+	testCmd.Flags().String("other", "", "other test argument")
+	testCmd.Flags().Bool("flag", false, "test flag")
+	testCmd.Flags().String("format", "plain", "test argument with default value")
 
 	_ = testCmd.MarkFlagRequired("arg")
 
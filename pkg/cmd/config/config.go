@@ -29,7 +29,7 @@ func SetupConfig() {
 // Config precedence (each item takes precedence over the item below it):
 // . Flags
 // . Env variables
-// . Config file
+// . Config file (settings of the active profile take precedence over the top level ones, see ActivateProfile)
 //
 // Environment variables are prefixed with `IKE` and have fully qualified names, for example
 // in case of `develop` command and its `port` flag corresponding environment variable is
@@ -77,13 +77,13 @@ func contains(s []string, e string) bool {
 // This way we can make flags required but still have their values provided by the configuration source.
 func SyncFullyQualifiedFlag(cmd *cobra.Command, flagName string) error {
 	value := viper.GetString(cmd.Name() + "." + flagName)
-	if value != "" && !cmd.Flag(flagName).Changed {
+	if isSet(cmd.Flag(flagName), value) {
 		err := cmd.Flags().Set(flagName, value)
 
 		return errors.Wrapf(err, "failed setting flag %s with value %v", flagName, value)
 	}
 	value = viper.GetString(flagName)
-	if value != "" && !cmd.Flag(flagName).Changed {
+	if isSet(cmd.Flag(flagName), value) {
 		err := cmd.Flags().Set(flagName, value)
 
 		return errors.Wrapf(err, "failed setting flag %s with value %v", flagName, value)
@@ -92,6 +92,12 @@ func SyncFullyQualifiedFlag(cmd *cobra.Command, flagName string) error {
 	return nil
 }
 
+// isSet tells if the value coming from the configuration should be applied to the flag. Default values are skipped, as
+// setting them would mark the flag as changed, even though neither the user nor the configuration provided it.
+func isSet(flag *pflag.Flag, value string) bool {
+	return value != "" && value != flag.DefValue && !flag.Changed
+}
+
 // SyncFullyQualifiedFlags ensures that if configuration provide a value for any of defined flags it will be set
 // back to the flag itself.
 //
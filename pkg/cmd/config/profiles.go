@@ -0,0 +1,208 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	profilesKey = "profiles"
+	extendsKey  = "extends"
+)
+
+// Sources of the effective configuration value.
+const (
+	SourceFlag    = "flag"
+	SourceEnv     = "env"
+	SourceFile    = "file"
+	SourceProfile = "profile"
+	SourceDefault = "default"
+)
+
+type profile struct {
+	name     string
+	settings map[string]interface{}
+}
+
+// activeProfiles holds the chain of the active profile starting with the most generic one.
+var activeProfiles []profile
+
+// ActivateProfile overlays the settings of the given profile on top of the ones defined at the top level
+// of the config file. Profiles are defined under the profiles key and can extend each other:
+//
+//	profiles:
+//	  staging:
+//	    develop:
+//	      namespace: staging
+//	  perf:
+//	    extends: staging
+//	    develop:
+//	      route: header:x-perf=true
+//
+// Settings of a profile take precedence over the ones of the profile it extends, which in turn take precedence over
+// the top level ones. Flags and environment variables still take precedence over all of them.
+func ActivateProfile(name string) error {
+	activeProfiles = nil
+	if name == "" {
+		return nil
+	}
+
+	chain, err := resolveProfile(viper.GetStringMap(profilesKey), name)
+	if err != nil {
+		return err
+	}
+	for _, p := range chain {
+		// merging links nested maps into the config, so copy them to keep settings of each profile intact
+		if err := viper.MergeConfigMap(deepCopy(p.settings)); err != nil {
+			return errors.WrapWithDetails(err, "failed merging profile", "profile", p.name)
+		}
+	}
+	activeProfiles = chain
+
+	return nil
+}
+
+// ActiveProfile returns the name of the active profile or empty string if there is none.
+func ActiveProfile() string {
+	if len(activeProfiles) == 0 {
+		return ""
+	}
+
+	return activeProfiles[len(activeProfiles)-1].name
+}
+
+// resolveProfile walks the extends chain of the profile and returns it starting with the most generic profile.
+func resolveProfile(profiles map[string]interface{}, name string) ([]profile, error) {
+	var chain []profile
+	visited := map[string]bool{}
+	for name != "" {
+		key := strings.ToLower(name)
+		if visited[key] {
+			return nil, errors.Errorf("profile %s extends itself", name)
+		}
+		visited[key] = true
+
+		definition, found := profiles[key]
+		if !found {
+			return nil, errors.Errorf("profile %s not found, available profiles: %v", name, profileNames(profiles))
+		}
+		settings, ok := definition.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("profile %s has to be a map of settings", name)
+		}
+
+		withoutExtends := make(map[string]interface{}, len(settings))
+		for k, v := range settings {
+			if k != extendsKey {
+				withoutExtends[k] = v
+			}
+		}
+		chain = append([]profile{{name: key, settings: withoutExtends}}, chain...)
+
+		name, _ = settings[extendsKey].(string)
+	}
+
+	return chain, nil
+}
+
+func profileNames(profiles map[string]interface{}) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func deepCopy(settings map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(settings))
+	for k, v := range settings {
+		if nested, ok := v.(map[string]interface{}); ok {
+			v = deepCopy(nested)
+		}
+		c[k] = v
+	}
+
+	return c
+}
+
+// Describe returns the effective value of the command flag together with the source it comes from, following
+// the same precedence as SyncFullyQualifiedFlag. Source is one of flag, env, profile, file or default, followed
+// by the name of the environment variable or the profile when applicable.
+func Describe(cmd *cobra.Command, flag *pflag.Flag) (value, source string) {
+	if flag.Changed {
+		return flag.Value.String(), SourceFlag
+	}
+
+	file := fileSettings()
+	for _, key := range []string{cmd.Name() + "." + flag.Name, flag.Name} {
+		envName := "IKE_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+		if env := os.Getenv(envName); env != "" {
+			return env, SourceEnv + " " + envName
+		}
+		for i := len(activeProfiles) - 1; i >= 0; i-- {
+			if v, found := lookup(activeProfiles[i].settings, key); found {
+				return v, SourceProfile + " " + activeProfiles[i].name
+			}
+		}
+		if v, found := lookup(file, key); found {
+			return v, SourceFile
+		}
+	}
+
+	return flag.DefValue, SourceDefault
+}
+
+// Lookup returns the value of the key defined in the config file, taking the active profile into account.
+// Unlike viper it ignores flags bound to the key, so it can be used for the keys shared by flags of different types.
+func Lookup(key string) (string, bool) {
+	for i := len(activeProfiles) - 1; i >= 0; i-- {
+		if v, found := lookup(activeProfiles[i].settings, key); found {
+			return v, true
+		}
+	}
+
+	return lookup(fileSettings(), key)
+}
+
+// fileSettings reads the settings of the config file in use without any flags or environment variables bound.
+func fileSettings() map[string]interface{} {
+	if viper.ConfigFileUsed() == "" {
+		return nil
+	}
+	v := viper.New()
+	v.SetConfigFile(viper.ConfigFileUsed())
+	if err := v.ReadInConfig(); err != nil {
+		return nil
+	}
+
+	return v.AllSettings()
+}
+
+// lookup finds the value of fully qualified key, e.g. develop.port, in nested settings.
+func lookup(settings map[string]interface{}, key string) (string, bool) {
+	path := strings.Split(strings.ToLower(key), ".")
+	current := settings
+	for i, segment := range path {
+		value, found := current[segment]
+		if !found || value == nil {
+			return "", false
+		}
+		if i == len(path)-1 {
+			return fmt.Sprint(value), true
+		}
+		if current, found = value.(map[string]interface{}); !found {
+			return "", false
+		}
+	}
+
+	return "", false
+}
@@ -1,15 +1,13 @@
 package create
 
 import (
-	"encoding/json"
-	"fmt"
-
 	"emperror.dev/errors"
 	"github.com/go-logr/logr"
 	"github.com/spf13/cobra"
 
 	"github.com/maistra/istio-workspace/pkg/cmd/config"
 	internal "github.com/maistra/istio-workspace/pkg/cmd/internal/session"
+	"github.com/maistra/istio-workspace/pkg/cmd/output"
 	"github.com/maistra/istio-workspace/pkg/log"
 )
 
@@ -24,36 +22,64 @@ func NewCmd() *cobra.Command {
 		Short:        "Creates a new Session",
 		SilenceUsage: true,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
-			return errors.Wrap(config.SyncFullyQualifiedFlags(cmd), "failed syncing flags")
+			if err := config.SyncFullyQualifiedFlags(cmd); err != nil {
+				return errors.Wrap(err, "failed syncing flags")
+			}
+			if outputJSON, _ := cmd.Flags().GetBool("json"); outputJSON && !cmd.Flag("output").Changed {
+				if err := cmd.Flags().Set("output", output.JSON); err != nil {
+					return errors.Wrap(err, "failed setting output format")
+				}
+			}
+			if format := cmd.Flag("output").Value.String(); format != "" {
+				_, err := output.NewPrinter(format)
+
+				return err
+			}
+
+			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			state, _, _, err := internal.Sessions(cmd)
-			if outputJSON, _ := cmd.Flags().GetBool("json"); outputJSON {
-				b, _ := json.MarshalIndent(&state.RefStatus, "", "  ")
-				fmt.Println(string(b))
+			if err != nil {
+				return errors.WrapIf(err, "failed executing command")
+			}
+			if format := cmd.Flag("output").Value.String(); format != "" && state.Session != nil {
+				return output.Print(cmd.OutOrStdout(), format, output.Session(state.Session))
 			}
 
-			return errors.WrapIf(err, "failed executing command")
+			return nil
 		},
 	}
 
 	createCmd.Flags().StringP("deployment", "d", "", "name of the deployment or deployment config")
 	createCmd.Flags().StringP("session", "s", "", "create or join an existing session")
-	createCmd.Flags().StringP("image", "i", "", "create a prepared session with the given image")
+	createCmd.Flags().StringP("image", "i", "", "create a prepared session with the given image "+
+		"or comma-separated list of container=image pairs to replace images of multiple containers")
 	createCmd.Flags().StringP("route", "", "", "specifies traffic route options in the format of type:name=value. "+
 		"Defaults to X-Workspace-Route header with current session name value")
 	createCmd.Flags().StringP("namespace", "n", "", "target namespace to develop against "+
 		"(defaults to default for the current context)")
+	createCmd.Flags().String("container", "", "name of the container to target in multi-container pods "+
+		"(defaults to the one annotated with kubectl.kubernetes.io/default-container, named after the deployment or the first non istio-proxy one)")
+	createCmd.Flags().String("subset-label", "", "name of the pod label identifying version subsets, e.g. app.kubernetes.io/version "+
+		"(detected from existing DestinationRules when not provided)")
+	createCmd.Flags().StringSlice("merge-patch", []string{}, "strategic merge patch strategies applied to the cloned deployment "+
+		"on top of the strategy in the given order, e.g. extra-env (see ike strategy list)")
+	createCmd.Flags().StringArray("var", []string{}, "strategy variable in the form of name=value, can be repeated")
 	createCmd.Flags().Bool("offline", false, "avoid calling external sources")
 	if err := createCmd.Flags().MarkHidden("offline"); err != nil {
 		logger().Error(err, "failed while trying to hide a flag")
 	}
+	output.AddFlag(createCmd, "")
 	createCmd.Flags().Bool("json", false, "return result in json")
 	jsonFlag := createCmd.Flag("json")
 	if jsonFlag.Annotations == nil {
 		jsonFlag.Annotations = map[string][]string{}
 	}
 	jsonFlag.Annotations["silent"] = []string{"true"}
+	if err := createCmd.Flags().MarkDeprecated("json", "use -o json instead"); err != nil {
+		logger().Error(err, "failed while trying to deprecate a flag")
+	}
 
 	createCmd.Flags().VisitAll(config.BindFullyQualifiedFlag(createCmd))
 
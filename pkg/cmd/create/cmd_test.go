@@ -51,4 +51,46 @@ var _ = Describe("Usage of ike create command", func() {
 
 	})
 
+	Describe("output", func() {
+
+		It("should print the session as json", func() {
+			output, err := Run(createCmd).Passing("--deployment", "ratings-v1", "--image", "x", "--session", "feature-x",
+				"--offline", "-o", "json")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring(`"kind": "Session"`))
+			Expect(output).To(ContainSubstring(`"name": "feature-x"`))
+		})
+
+		It("should keep deprecated json flag printing the session", func() {
+			output, err := Run(createCmd).Passing("--deployment", "ratings-v1", "--image", "x", "--session", "feature-x",
+				"--offline", "--json")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring(`"kind": "Session"`))
+		})
+
+		It("should print session name only", func() {
+			output, err := Run(createCmd).Passing("--deployment", "ratings-v1", "--image", "x", "--session", "feature-x",
+				"--offline", "-o", "name")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(Equal("session.maistra.io/feature-x\n"))
+		})
+
+		It("should print fields selected by jsonpath", func() {
+			output, err := Run(createCmd).Passing("--deployment", "ratings-v1", "--image", "x", "--session", "feature-x",
+				"--offline", "-o", "jsonpath={.spec.ref[0].name}")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(Equal("ratings-v1"))
+		})
+
+		It("should fail on unknown output format", func() {
+			_, err := ValidateArgumentsOf(createCmd).Passing("--deployment", "ratings-v1", "--image", "x", "-o", "xml")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unknown output format xml"))
+		})
+	})
 })
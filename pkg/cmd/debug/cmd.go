@@ -0,0 +1,165 @@
+package debug
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+
+	"github.com/maistra/istio-workspace/pkg/cmd/config"
+	"github.com/maistra/istio-workspace/pkg/cmd/develop"
+	internal "github.com/maistra/istio-workspace/pkg/cmd/internal/session"
+	"github.com/maistra/istio-workspace/pkg/log"
+	"github.com/maistra/istio-workspace/pkg/proxy"
+)
+
+var logger = func() logr.Logger {
+	return log.Log.WithValues("type", "debug")
+}
+
+const debugStrategy = "debug"
+
+// NewCmd creates instance of "debug" Cobra Command with flags and execution logic defined.
+func NewCmd() *cobra.Command {
+	debugCmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Starts the cloned deployment under a debugger and forwards its port",
+		Long: "Starts the cloned deployment under a language debugger (Delve for Go, JDWP for JVM, debugpy for Python), " +
+			"forwards the debugger port to your machine and prints instructions on how to attach your IDE.\n\n" +
+			"The session is removed once the command is interrupted.",
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return errors.Wrap(config.SyncFullyQualifiedFlags(cmd), "failed syncing flags")
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			debugger, port, err := setStrategyVariables(cmd)
+			if err != nil {
+				return err
+			}
+
+			sessionState, _, sessionClose, err := internal.Sessions(cmd)
+			if err != nil {
+				return errors.Wrap(err, "failed setting up session")
+			}
+			defer sessionClose()
+
+			c, restCfg, namespace, err := proxy.ClusterClient()
+			if err != nil {
+				return err
+			}
+			if ns := cmd.Flag("namespace").Value.String(); ns != "" {
+				namespace = ns
+			}
+			pod, err := proxy.WaitForPod(c, namespace, sessionState.DeploymentName)
+			if err != nil {
+				return err
+			}
+
+			localPort, _ := cmd.Flags().GetInt("local-port") // ignore error, should only occur if flag does not exist
+			if localPort == 0 {
+				localPort = port
+			}
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			address, err := proxy.PortForward(restCfg, c, namespace, pod, localPort, port, stopCh)
+			if err != nil {
+				return errors.WrapIfWithDetails(err, "failed forwarding debugger port", "debugger", debugger.Name)
+			}
+
+			host, forwardedPort, err := net.SplitHostPort(address)
+			if err != nil {
+				return errors.WrapWithDetails(err, "invalid forwarded address", "address", address)
+			}
+			p, _ := strconv.Atoi(forwardedPort)
+			instructions, err := debugger.Instructions(host, p)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), instructions)
+
+			if hint, err := develop.Hint(&sessionState.RefStatus, &sessionState.Route); err == nil {
+				logger().Info(hint)
+			}
+
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+			defer signal.Stop(signals)
+			<-signals
+
+			return nil
+		},
+	}
+	debugCmd.Annotations = map[string]string{
+		internal.AnnotationRevert:   "true",
+		internal.AnnotationStrategy: debugStrategy,
+	}
+
+	debugCmd.Flags().StringP("deployment", "d", "", "name of the deployment or deployment config")
+	debugCmd.Flags().StringP("language", "l", "", fmt.Sprintf("language of the application, one of %v", Languages()))
+	debugCmd.Flags().String("command", "", "command starting your application in the container, e.g. /app/server --port 9080 for Go "+
+		"or python app.py for Python (keeps the original one for Java)")
+	debugCmd.Flags().IntP("port", "p", 0, "port the debugger listens on in the container (defaults to 2345 for Go, 5005 for Java and 5678 for Python)")
+	debugCmd.Flags().Int("local-port", 0, "local port forwarded to the debugger (defaults to --port)")
+	debugCmd.Flags().Bool("suspend", false, "wait for the debugger to attach before running your application")
+	debugCmd.Flags().String("debugger-image", "", "image to run in place of the original one, e.g. extended with the debugger")
+	debugCmd.Flags().StringP("session", "s", "", "create or join an existing session")
+	debugCmd.Flags().StringP("route", "", "", "specifies traffic route options in the format of type:name=value. "+
+		"Defaults to X-Workspace-Route header with current session name value")
+	debugCmd.Flags().StringP("namespace", "n", "", "target namespace to develop against "+
+		"(defaults to default for the current context)")
+	debugCmd.Flags().String("container", "", "name of the container to target in multi-container pods "+
+		"(defaults to the one annotated with kubectl.kubernetes.io/default-container, named after the deployment or the first non istio-proxy one)")
+	debugCmd.Flags().String("subset-label", "", "name of the pod label identifying version subsets, e.g. app.kubernetes.io/version "+
+		"(detected from existing DestinationRules when not provided)")
+	debugCmd.Flags().StringSlice("merge-patch", []string{}, "strategic merge patch strategies applied to the cloned deployment "+
+		"on top of the strategy in the given order, e.g. extra-env (see ike strategy list)")
+	debugCmd.Flags().StringArray("var", []string{}, "strategy variable in the form of name=value, can be repeated")
+	debugCmd.Flags().Bool("offline", false, "avoid calling external sources")
+	if err := debugCmd.Flags().MarkHidden("offline"); err != nil {
+		logger().Error(err, "failed while trying to hide a flag")
+	}
+
+	debugCmd.Flags().VisitAll(config.BindFullyQualifiedFlag(debugCmd))
+
+	_ = debugCmd.MarkFlagRequired("deployment")
+	_ = debugCmd.MarkFlagRequired("language")
+
+	return debugCmd
+}
+
+// setStrategyVariables validates the debugger flags and passes them to the debug strategy as variables.
+func setStrategyVariables(cmd *cobra.Command) (Debugger, int, error) {
+	debugger, err := Lookup(cmd.Flag("language").Value.String())
+	if err != nil {
+		return Debugger{}, 0, err
+	}
+	command := cmd.Flag("command").Value.String()
+	if debugger.RequiresCommand && command == "" {
+		return Debugger{}, 0, errors.Errorf("command starting the application has to be defined for %s", debugger.Language)
+	}
+	port, _ := cmd.Flags().GetInt("port") // ignore error, should only occur if flag does not exist
+	if port == 0 {
+		port = debugger.DefaultPort
+	}
+
+	vars := map[string]string{
+		"language": debugger.Language,
+		"port":     strconv.Itoa(port),
+		"command":  command,
+		"image":    cmd.Flag("debugger-image").Value.String(),
+		"suspend":  cmd.Flag("suspend").Value.String(),
+	}
+	for _, name := range []string{"language", "port", "command", "image", "suspend"} {
+		if err := cmd.Flags().Set("var", name+"="+vars[name]); err != nil {
+			return Debugger{}, 0, errors.Wrapf(err, "failed setting %s variable", name)
+		}
+	}
+
+	return debugger, port, nil
+}
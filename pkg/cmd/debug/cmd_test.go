@@ -0,0 +1,106 @@
+package debug_test
+
+import (
+	"emperror.dev/errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	. "github.com/maistra/istio-workspace/pkg/cmd"
+	"github.com/maistra/istio-workspace/pkg/cmd/debug"
+	"github.com/maistra/istio-workspace/pkg/proxy"
+	. "github.com/maistra/istio-workspace/test"
+)
+
+var _ = Describe("Usage of ike debug command", func() {
+
+	var (
+		debugCmd       *cobra.Command
+		originalClient = proxy.ClusterClient
+	)
+
+	BeforeEach(func() {
+		debugCmd = debug.NewCmd()
+		debugCmd.SilenceUsage = true
+		debugCmd.SilenceErrors = true
+		NewCmd().AddCommand(debugCmd)
+
+		proxy.ClusterClient = func() (kubernetes.Interface, *rest.Config, string, error) {
+			return nil, nil, "", errors.New("no cluster available")
+		}
+	})
+
+	AfterEach(func() {
+		proxy.ClusterClient = originalClient
+	})
+
+	Context("input validation", func() {
+
+		It("should fail when language is not specified", func() {
+			_, err := ValidateArgumentsOf(debugCmd).Passing("--deployment", "ratings-v1")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(And(ContainSubstring("required flag(s)"), ContainSubstring("language")))
+		})
+
+		It("should fail for unsupported language", func() {
+			_, err := Run(debugCmd).Passing("--deployment", "ratings-v1", "--language", "cobol", "--offline")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unsupported language cobol, expected one of [go java python]"))
+		})
+
+		It("should require command for go", func() {
+			_, err := Run(debugCmd).Passing("--deployment", "ratings-v1", "--language", "go", "--offline")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("command starting the application has to be defined for go"))
+		})
+	})
+
+	Context("strategy variables", func() {
+
+		It("should use default port of the language", func() {
+			_, err := Run(debugCmd).Passing("--deployment", "ratings-v1", "--language", "java", "--offline")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("no cluster available"))
+			vars, _ := debugCmd.Flags().GetStringArray("var")
+			Expect(vars).To(ContainElements("language=java", "port=5005", "suspend=false"))
+		})
+
+		It("should pass debugger flags", func() {
+			_, err := Run(debugCmd).Passing("--deployment", "ratings-v1", "--language", "python", "--offline",
+				"--command", "python ratings.py", "--port", "9999", "--suspend", "--debugger-image", "quay.io/test/ratings:debug")
+
+			Expect(err).To(HaveOccurred())
+			vars, _ := debugCmd.Flags().GetStringArray("var")
+			Expect(vars).To(ContainElements("language=python", "port=9999", "command=python ratings.py",
+				"suspend=true", "image=quay.io/test/ratings:debug"))
+		})
+	})
+
+	Context("attach instructions", func() {
+
+		It("should describe how to attach to Delve", func() {
+			debugger, err := debug.Lookup("go")
+			Expect(err).ToNot(HaveOccurred())
+
+			instructions, err := debugger.Instructions("localhost", 2345)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instructions).To(ContainSubstring("$ dlv connect localhost:2345"))
+			Expect(instructions).To(ContainSubstring(`"mode": "remote", "host": "localhost", "port": 2345`))
+		})
+
+		It("should describe how to attach to JDWP agent", func() {
+			debugger, err := debug.Lookup("java")
+			Expect(err).ToNot(HaveOccurred())
+
+			instructions, err := debugger.Instructions("localhost", 5005)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instructions).To(ContainSubstring("$ jdb -attach localhost:5005"))
+		})
+	})
+})
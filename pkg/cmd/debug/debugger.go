@@ -0,0 +1,93 @@
+package debug
+
+import (
+	"bytes"
+	"sort"
+	"text/template"
+
+	"emperror.dev/errors"
+)
+
+// Debugger describes the debugger the clone is started with for a given language.
+type Debugger struct {
+	Language        string
+	Name            string
+	DefaultPort     int
+	RequiresCommand bool // the command has to be known to start it under the debugger
+	instructions    string
+}
+
+var debuggers = map[string]Debugger{
+	"go": {
+		Language:        "go",
+		Name:            "Delve",
+		DefaultPort:     2345,
+		RequiresCommand: true,
+		instructions: `Delve is listening on {{.Host}}:{{.Port}}
+
+  CLI       $ dlv connect {{.Host}}:{{.Port}}
+  GoLand    Run > Edit Configurations > Go Remote, host {{.Host}} and port {{.Port}}
+  VS Code   add the following configuration to your launch.json
+            {"name": "ike debug", "type": "go", "request": "attach", "mode": "remote", "host": "{{.Host}}", "port": {{.Port}}}`,
+	},
+	"java": {
+		Language:    "java",
+		Name:        "JDWP",
+		DefaultPort: 5005,
+		instructions: `JDWP agent is listening on {{.Host}}:{{.Port}}
+
+  CLI       $ jdb -attach {{.Host}}:{{.Port}}
+  IntelliJ  Run > Edit Configurations > Remote JVM Debug, host {{.Host}} and port {{.Port}}
+  VS Code   add the following configuration to your launch.json
+            {"name": "ike debug", "type": "java", "request": "attach", "hostName": "{{.Host}}", "port": {{.Port}}}`,
+	},
+	"python": {
+		Language:        "python",
+		Name:            "debugpy",
+		DefaultPort:     5678,
+		RequiresCommand: true,
+		instructions: `debugpy is listening on {{.Host}}:{{.Port}}
+
+  VS Code   add the following configuration to your launch.json
+            {"name": "ike debug", "type": "python", "request": "attach", "connect": {"host": "{{.Host}}", "port": {{.Port}}},
+             "pathMappings": [{"localRoot": "${workspaceFolder}", "remoteRoot": "."}]}
+  Other     any client speaking Debug Adapter Protocol can attach to {{.Host}}:{{.Port}}`,
+	},
+}
+
+// Lookup returns the Debugger of the given language.
+func Lookup(language string) (Debugger, error) {
+	debugger, found := debuggers[language]
+	if !found {
+		return Debugger{}, errors.Errorf("unsupported language %s, expected one of %v", language, Languages())
+	}
+
+	return debugger, nil
+}
+
+// Languages returns sorted list of all supported languages.
+func Languages() []string {
+	languages := make([]string, 0, len(debuggers))
+	for language := range debuggers {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+
+	return languages
+}
+
+// Instructions returns the help on how to attach the IDE to the debugger forwarded to the given local host and port.
+func (d Debugger) Instructions(host string, port int) (string, error) {
+	tmpl, err := template.New(d.Language).Parse(d.instructions)
+	if err != nil {
+		return "", errors.Wrap(err, "failed parsing template")
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, struct {
+		Host string
+		Port int
+	}{Host: host, Port: port})
+
+	return buf.String(), errors.Wrap(err, "failed rendering instructions")
+}
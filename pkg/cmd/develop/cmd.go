@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"emperror.dev/errors"
 	gocmd "github.com/go-cmd/cmd"
@@ -11,20 +13,21 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	istiov1alpha1 "github.com/maistra/istio-workspace/api/maistra/v1alpha1"
 	"github.com/maistra/istio-workspace/pkg/cmd/config"
 	"github.com/maistra/istio-workspace/pkg/cmd/execute"
 	internal "github.com/maistra/istio-workspace/pkg/cmd/internal/session"
+	"github.com/maistra/istio-workspace/pkg/cmd/output"
+	"github.com/maistra/istio-workspace/pkg/environment"
+	"github.com/maistra/istio-workspace/pkg/internal/session"
 	"github.com/maistra/istio-workspace/pkg/log"
-	"github.com/maistra/istio-workspace/pkg/shell"
-	"github.com/maistra/istio-workspace/pkg/telepresence"
+	"github.com/maistra/istio-workspace/pkg/proxy"
 )
 
 var logger = func() logr.Logger {
 	return log.Log.WithValues("type", "develop")
 }
 
-var errorTpNotAvailable = errors.Errorf("unable to find %s on your $PATH", telepresence.BinaryName)
-
 // NewCmd creates instance of "develop" Cobra Command with flags and execution logic defined.
 func NewCmd() *cobra.Command {
 	developCmd := &cobra.Command{
@@ -32,45 +35,107 @@ func NewCmd() *cobra.Command {
 		Short:        "Starts the development flow",
 		SilenceUsage: true,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
-			if !telepresence.BinaryAvailable() {
-				return errorTpNotAvailable
+			if err := config.SyncFullyQualifiedFlags(cmd); err != nil {
+				return errors.Wrap(err, "failed syncing flags")
+			}
+			if err := syncDeploymentsSection(cmd); err != nil {
+				return err
+			}
+			if cmd.Flag("deployment").Changed {
+				if _, err := deploymentRuns(cmd); err != nil {
+					return err
+				}
+			}
+			if format := cmd.Flag("output").Value.String(); format != "" {
+				if _, err := output.NewPrinter(format); err != nil {
+					return err
+				}
+			}
+			backend, err := internal.Proxy(cmd.Flags())
+			if err != nil {
+				return err
 			}
 
-			return errors.Wrap(config.SyncFullyQualifiedFlags(cmd), "failed syncing flags")
+			return backend.Available()
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			dir, err := os.Getwd()
 			if err != nil {
 				return errors.Wrap(err, "failed obtaining working directory")
 			}
-			sessionState, _, sessionClose, err := internal.Sessions(cmd)
+			runs, err := deploymentRuns(cmd)
 			if err != nil {
-				return errors.Wrap(err, "failed setting up session")
+				return err
 			}
-			defer sessionClose()
-
-			// HACK: need contract with TP cmd?
-			if err := cmd.Flags().Set("deployment", sessionState.DeploymentName); err != nil {
-				return errors.Wrapf(err, "failed to set deployment flag")
+			if err := validateEnvironmentFlags(cmd, runs); err != nil {
+				return err
 			}
 
-			done := make(chan gocmd.Status, 1)
-			defer close(done)
-
-			arguments := createTpCommand(cmd)
+			// not closed, as the proxies stopped after the first one finished still report their status
+			done := make(chan gocmd.Status, len(runs))
 
-			go func() {
-				tp := gocmd.NewCmdOptions(shell.StreamOutput, telepresence.BinaryName, arguments...)
-				tp.Dir = dir
-				shell.RedirectStreams(tp, cmd.OutOrStdout(), cmd.OutOrStderr())
-				shell.ShutdownHookForChildCommand(tp)
-				shell.Start(tp, done)
+			var cleanups []func()
+			defer func() {
+				for i := len(cleanups) - 1; i >= 0; i-- {
+					cleanups[i]()
+				}
 			}()
 
-			if hint, err := Hint(&sessionState.RefStatus, &sessionState.Route); err == nil {
-				logger().Info(hint)
+			sessionName := cmd.Flag("session").Value.String()
+			states := make([]session.State, len(runs))
+			strategyArgs := make([]map[string]string, len(runs))
+			for i, run := range runs {
+				sessionState, options, sessionClose, err := internal.DeploymentSessions(cmd, run.deployment, run.command, sessionName)
+				if err != nil {
+					return errors.WrapWithDetails(err, "failed setting up session", "deployment", run.deployment)
+				}
+				cleanups = append(cleanups, sessionClose)
+				if sessionState.SessionName != "" {
+					sessionName = sessionState.SessionName // the rest of deployments joins the session created by the first one
+				}
+				states[i], strategyArgs[i] = sessionState, options.StrategyArgs
+			}
+
+			// the last state holds the session with all the refs, printed before local processes start writing to stdout
+			if format := cmd.Flag("output").Value.String(); format != "" && states[len(states)-1].Session != nil {
+				if err := output.Print(cmd.OutOrStdout(), format, output.Session(states[len(states)-1].Session)); err != nil {
+					return err
+				}
 			}
 
+			var outputLock sync.Mutex
+			for i, run := range runs {
+				sessionState := states[i]
+				backend, err := internal.Proxy(cmd.Flags())
+				if err != nil {
+					return err
+				}
+				env, cleanup, err := importEnvironment(cmd, run.deployment)
+				if err != nil {
+					return err
+				}
+				cleanups = append(cleanups, cleanup)
+				target := createTarget(cmd, dir, sessionState.DeploymentName, run, &sessionState.Route, strategyArgs[i])
+				target.Env = append(target.Env, env...)
+				if len(runs) > 1 {
+					target.Stdout = newPrefixedWriter(target.Stdout, run.deployment, &outputLock)
+					target.Stderr = newPrefixedWriter(target.Stderr, run.deployment, &outputLock)
+				}
+				if err := backend.Start(target, done); err != nil {
+					return errors.WrapIfWithDetails(err, "failed starting proxy", "proxy", backend.Name(), "deployment", run.deployment)
+				}
+				cleanups = append(cleanups, func() {
+					if err := backend.Stop(); err != nil {
+						logger().Error(err, "failed stopping proxy", "proxy", backend.Name())
+					}
+				})
+
+				if hint, err := Hint(&sessionState.RefStatus, &sessionState.Route); err == nil {
+					logger().Info(hint)
+				}
+			}
+
+			// the first local process to finish ends the development of all the deployments
 			finalStatus := <-done
 
 			return errors.WrapIf(finalStatus.Error, "failed executing sub command")
@@ -81,14 +146,26 @@ func NewCmd() *cobra.Command {
 	}
 	developCmd.Annotations[internal.AnnotationRevert] = "true"
 
-	developCmd.Flags().StringP("deployment", "d", "", "name of the deployment or deployment config")
+	developCmd.Flags().VarP(&deployments{}, "deployment", "d", "name of the deployment or deployment config. "+
+		"Can be repeated in the form of name=run-command to develop several deployments within the same session at once")
 	developCmd.Flags().StringSliceP("port", "p", []string{}, "list of ports to be exposed in format local[:remote].")
 	developCmd.Flags().StringP(execute.RunFlagName, "r", "", "command to run your application")
 	developCmd.Flags().StringP(execute.BuildFlagName, "b", "", "command to build your application before run")
 	developCmd.Flags().Bool(execute.NoBuildFlagName, false, "always skips build")
+	developCmd.Flags().Bool(execute.ShellFlagName, false, "run build and run commands through /bin/sh -c "+
+		"(used automatically when they contain pipes, redirects, variables or start with env assignments)")
+	developCmd.Flags().Int(execute.MaxRestartsFlagName, 5, "number of consecutive restarts of the crashing process before giving up (negative for no limit)")
+	developCmd.Flags().Duration(execute.RestartBackoffFlagName, time.Second, "delay before restarting the crashed process, doubled with each consecutive crash")
+	developCmd.Flags().Duration(execute.StopTimeoutFlagName, 10*time.Second, "time given to your application to stop gracefully before it is killed")
 	developCmd.Flags().Bool("watch", false, "enables watch")
 	developCmd.Flags().StringSliceP("watch-include", "w", []string{"."}, "list of directories to watch (relative to the one from which ike has been started)")
 	developCmd.Flags().StringSlice("watch-exclude", execute.DefaultExclusions, fmt.Sprintf("list of patterns to exclude (always excludes %v)", execute.DefaultExclusions))
+	developCmd.Flags().StringArray("watch-rule", []string{}, "action taken when watched files matching the pattern change in the form of pattern=action, "+
+		"where action is one of build (and restart), restart (without build), none or hook:command. Can be repeated, first matching rule wins "+
+		"(files not matching any of them are rebuilt)")
+	developCmd.Flags().Duration("watch-quiet-period", 0, "time without further changes to wait for before handling them, e.g. 300ms")
+	developCmd.Flags().Bool("watch-poll", false, "detect changes by periodically scanning watched directories instead of relying on file system notifications, "+
+		"e.g. on network file systems, Docker Desktop bind mounts or WSL (used automatically when inotify limits are exhausted)")
 	developCmd.Flags().Int64("watch-interval", 500, "watch interval (in ms)")
 	if err := developCmd.Flags().MarkHidden("watch-interval"); err != nil {
 		logger().Error(err, "failed while trying to hide a flag")
@@ -97,48 +174,78 @@ func NewCmd() *cobra.Command {
 	if err := developCmd.Flags().MarkHidden("offline"); err != nil {
 		logger().Error(err, "failed while trying to hide a flag")
 	}
-	developCmd.Flags().StringP("method", "m", "inject-tcp", "telepresence proxying mode - see https://www.telepresence.io/reference/methods")
+	developCmd.Flags().String("proxy", proxy.DefaultBackend, fmt.Sprintf("local proxy connecting the cloned deployment with your process, one of %v", proxy.Names()))
+	developCmd.Flags().Bool("sync", false, "copy changed files into the cloned deployment running the original image and restart "+
+		"the command defined by --run there, instead of proxying the traffic to your local process (same as --proxy sync)")
+	developCmd.Flags().String("sync-dir", ".", "directory in the container the watched files are copied to "+
+		"(relative to its working directory)")
+	developCmd.Flags().String("sync-restart", "", "command run in the container to restart your application once files are copied "+
+		"(defaults to restarting the command defined by --run)")
+	developCmd.Flags().StringP("method", "m", "inject-tcp", "telepresence proxying mode - see https://www.telepresence.io/reference/methods (ignored by Telepresence 2)")
+	developCmd.Flags().Bool("personal-intercept", false, "intercept only requests matching the session route header instead of all the traffic "+
+		"reaching the cloned deployment (Telepresence 2 only)")
 	developCmd.Flags().StringP("session", "s", "", "create or join an existing session")
 	developCmd.Flags().StringP("route", "", "", "specifies traffic route options in the format of type:name=value. "+
 		"Defaults to X-Workspace-Route header with current session name value")
 	developCmd.Flags().StringP("namespace", "n", "", "target namespace to develop against "+
 		"(defaults to default for the current context)")
+	developCmd.Flags().String("container", "", "name of the container to target in multi-container pods "+
+		"(defaults to the one annotated with kubectl.kubernetes.io/default-container, named after the deployment or the first non istio-proxy one)")
+	developCmd.Flags().String("subset-label", "", "name of the pod label identifying version subsets, e.g. app.kubernetes.io/version "+
+		"(detected from existing DestinationRules when not provided)")
+	developCmd.Flags().StringSlice("merge-patch", []string{}, "strategic merge patch strategies applied to the cloned deployment "+
+		"on top of the strategy in the given order, e.g. extra-env (see ike strategy list)")
+	developCmd.Flags().StringArray("var", []string{}, "strategy variable in the form of name=value, can be repeated")
+	developCmd.Flags().Bool("import-env", false, "import env, envFrom ConfigMaps and Secrets of the target container into the local process "+
+		"and write its ConfigMap and Secret volumes under their mount paths to the directory exposed as $"+environment.MountRootEnv)
+	developCmd.Flags().String("env-file", "", "export the imported environment to the file in KEY=VALUE format (implies --import-env)")
+	developCmd.Flags().String("mount-root", "", "directory the volumes of the target container are written to "+
+		"(defaults to a temporary directory removed on exit, implies --import-env)")
+	output.AddFlag(developCmd, "")
 
 	developCmd.Flags().VisitAll(config.BindFullyQualifiedFlag(developCmd))
 
 	_ = developCmd.MarkFlagRequired("deployment")
-	_ = developCmd.MarkFlagRequired(execute.RunFlagName)
 
 	return developCmd
 }
 
-func createTpCommand(cmd *cobra.Command) []string {
-	tpArgs := []string{
-		"--deployment", cmd.Flag("deployment").Value.String(),
-		"--method", cmd.Flag("method").Value.String(),
-	}
-	if cmd.Flags().Changed("port") {
-		ports, _ := cmd.Flags().GetStringSlice("port") // ignore error, should only occur if flag does not exist. If it doesn't, it won't be Changed()
-		for _, port := range ports {
-			tpArgs = append(tpArgs, "--expose", port)
-		}
-	}
-
-	tpArgs = append(tpArgs, "--run")
-	var tpCmd []string
-	tpCmd = tpArgs
-	tpCmd = append(tpCmd, createWrapperCmd(cmd)...)
+func createTarget(cmd *cobra.Command, dir, deployment string, run deploymentRun, route *istiov1alpha1.Route, strategyArgs map[string]string) proxy.Target {
+	ports, _ := cmd.Flags().GetStringSlice("port")                    // ignore error, should only occur if flag does not exist
+	personalIntercept, _ := cmd.Flags().GetBool("personal-intercept") // ignore error, should only occur if flag does not exist
+	watchInclude, _ := cmd.Flags().GetStringSlice("watch-include")    // ignore error, should only occur if flag does not exist
+	watchExclude, _ := cmd.Flags().GetStringSlice("watch-exclude")    // ignore error, should only occur if flag does not exist
+	watchInterval, _ := cmd.Flags().GetInt64("watch-interval")        // ignore error, should only occur if flag does not exist
+	watchPoll, _ := cmd.Flags().GetBool("watch-poll")                 // ignore error, should only occur if flag does not exist
 
-	namespaceFlag := cmd.Flag("namespace")
-	if namespaceFlag.Changed {
-		tpCmd = append([]string{"--" + namespaceFlag.Name, namespaceFlag.Value.String()}, tpCmd...)
+	if len(run.ports) > 0 && !cmd.Flag("port").Changed {
+		ports = run.ports
 	}
 
-	return tpCmd
+	return proxy.Target{
+		Namespace:         cmd.Flag("namespace").Value.String(),
+		Deployment:        deployment,
+		Ports:             ports,
+		Route:             route,
+		StrategyArgs:      strategyArgs,
+		Method:            cmd.Flag("method").Value.String(),
+		PersonalIntercept: personalIntercept,
+		Command:           createWrapperCmd(cmd, run.command),
+		Dir:               dir,
+		Stdout:            cmd.OutOrStdout(),
+		Stderr:            cmd.OutOrStderr(),
+		Sync: proxy.SyncOptions{
+			RemoteDir: cmd.Flag("sync-dir").Value.String(),
+			Restart:   cmd.Flag("sync-restart").Value.String(),
+			Paths:     watchInclude,
+			Exclude:   watchExclude,
+			Interval:  watchInterval,
+			Poll:      watchPoll,
+		},
+	}
 }
 
-func createWrapperCmd(cmd *cobra.Command) []string {
-	run := cmd.Flag(execute.RunFlagName).Value.String()
+func createWrapperCmd(cmd *cobra.Command, run string) []string {
 	executable, err := os.Executable()
 	if err != nil {
 		logger().Error(err, "unable to execute wrapped command")
@@ -154,6 +261,11 @@ func createWrapperCmd(cmd *cobra.Command) []string {
 	if cmd.Flag(execute.BuildFlagName).Changed {
 		executeArgs = append(executeArgs, "--"+execute.BuildFlagName, cmd.Flag(execute.BuildFlagName).Value.String())
 	}
+	for _, name := range []string{execute.ShellFlagName, execute.MaxRestartsFlagName, execute.RestartBackoffFlagName, execute.StopTimeoutFlagName} {
+		if cmd.Flag(name).Changed {
+			executeArgs = append(executeArgs, "--"+name+"="+cmd.Flag(name).Value.String())
+		}
+	}
 
 	watch, _ := cmd.Flags().GetBool("watch")
 	if watch {
@@ -162,7 +274,15 @@ func createWrapperCmd(cmd *cobra.Command) []string {
 			"--dir", stringSliceToCSV(cmd.Flags(), "watch-include"),
 			"--exclude", stringSliceToCSV(cmd.Flags(), "watch-exclude"),
 			"--interval", cmd.Flag("watch-interval").Value.String(),
+			"--"+execute.QuietPeriodFlagName, cmd.Flag("watch-quiet-period").Value.String(),
 		)
+		if poll, _ := cmd.Flags().GetBool("watch-poll"); poll {
+			executeArgs = append(executeArgs, "--"+execute.PollFlagName)
+		}
+		rules, _ := cmd.Flags().GetStringArray("watch-rule")
+		for _, rule := range rules {
+			executeArgs = append(executeArgs, "--"+execute.RuleFlagName, rule)
+		}
 	}
 
 	return executeArgs
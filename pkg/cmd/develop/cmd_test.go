@@ -1,6 +1,7 @@
 package develop_test
 
 import (
+	"io/ioutil"
 	"os"
 	"path"
 
@@ -8,9 +9,19 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
 
 	. "github.com/maistra/istio-workspace/pkg/cmd"
 	"github.com/maistra/istio-workspace/pkg/cmd/develop"
+	"github.com/maistra/istio-workspace/pkg/environment"
+	"github.com/maistra/istio-workspace/pkg/kubeconfig"
 	. "github.com/maistra/istio-workspace/test"
 	"github.com/maistra/istio-workspace/test/shell"
 )
@@ -211,6 +222,30 @@ var _ = Describe("Usage of ike develop command", func() {
 			Expect(output).To(ContainSubstring("--run java -jar rating.jar"))
 		})
 
+		It("should print the initial session state before starting the proxy", func() {
+			output, err := Run(developCmd).Passing("--deployment", "rating-service",
+				"--run", "java -jar rating.jar",
+				"--session", "feature-x",
+				"-o", "jsonpath={.metadata.name}:{.spec.ref[0].name}",
+				"--offline")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(HavePrefix("feature-x:rating-service"))
+			Expect(output).To(ContainSubstring("--deployment rating-service"))
+		})
+
+		It("should pass selected kube context", func() {
+			defer kubeconfig.Select(kubeconfig.Selection{})
+
+			output, err := Run(developCmd).Passing("--deployment", "rating-service",
+				"--run", "java -jar rating.jar",
+				"--context", "staging",
+				"--offline")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring("--context staging --deployment rating-service"))
+		})
+
 		It("should pass specified parameters and defaults", func() {
 			output, err := Run(developCmd).Passing("--deployment", "rating-service",
 				"--run", "java -jar rating.jar",
@@ -249,6 +284,43 @@ var _ = Describe("Usage of ike develop command", func() {
 			Expect(output).To(ContainSubstring("execute --run java -jar rating.jar --build mvn clean install"))
 		})
 
+		It("should pass process supervision settings when specified", func() {
+			output, err := Run(developCmd).Passing("--deployment", "rating-service",
+				"--run", "java -jar rating.jar",
+				"--shell",
+				"--max-restarts", "3",
+				"--stop-timeout", "30s",
+				"--offline")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring("execute --run java -jar rating.jar --shell=true --max-restarts=3 --stop-timeout=30s"))
+			Expect(output).ToNot(ContainSubstring("--restart-backoff"))
+		})
+
+		It("should pass watch rules and quiet period when watching", func() {
+			output, err := Run(developCmd).Passing("--deployment", "rating-service",
+				"--run", "java -jar rating.jar",
+				"--watch",
+				"--watch-rule", "static/**=none",
+				"--watch-rule", "*.properties=restart",
+				"--watch-quiet-period", "300ms",
+				"--offline")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring("--quiet-period 300ms --rule static/**=none --rule *.properties=restart"))
+		})
+
+		It("should pass polling when watching", func() {
+			output, err := Run(developCmd).Passing("--deployment", "rating-service",
+				"--run", "java -jar rating.jar",
+				"--watch",
+				"--watch-poll",
+				"--offline")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring("--quiet-period 0s --poll"))
+		})
+
 		It("should call ike execute with full parent command path", func() {
 			output, err := Run(developCmd).Passing("--deployment", "rating-service",
 				"--run", "java -jar rating.jar",
@@ -275,4 +347,239 @@ var _ = Describe("Usage of ike develop command", func() {
 
 	})
 
+	Context("telepresence 2 arguments delegation", func() {
+
+		tmpPath := NewTmpPath()
+		var restoreEnvVars func()
+		BeforeEach(func() {
+			tmpPath.SetPath(path.Dir(shell.MvnBin), path.Dir(shell.TpSleepBin))
+			restoreEnvVars = TemporaryEnvVars("TELEPRESENCE_VERSION", "Client: v2.4.5 (api v3)")
+		})
+		AfterEach(func() {
+			restoreEnvVars()
+			tmpPath.Restore()
+		})
+
+		It("should connect and intercept the cloned deployment", func() {
+			output, err := Run(developCmd).Passing("--deployment", "rating-service",
+				"--run", "java -jar rating.jar",
+				"--port", "4321:5000",
+				"--namespace", "my-project",
+				"--offline")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring("telepresence connect"))
+			Expect(output).To(ContainSubstring("intercept rating-service --namespace my-project --port 4321:5000 --"))
+			Expect(output).To(ContainSubstring("execute --run java -jar rating.jar"))
+			Expect(output).ToNot(ContainSubstring("--method"))
+			Expect(output).ToNot(ContainSubstring("--http-header"))
+		})
+
+		It("should only pass the first port", func() {
+			output, err := Run(developCmd).Passing("--deployment", "rating-service",
+				"--run", "java -jar rating.jar",
+				"--port", "4321:5000",
+				"--port", "4322:5001",
+				"--offline")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring("--port 4321:5000"))
+			Expect(output).ToNot(ContainSubstring("4322:5001"))
+		})
+
+		It("should fail personal intercept without header based route", func() {
+			_, err := Run(developCmd).Passing("--deployment", "rating-service",
+				"--run", "java -jar rating.jar",
+				"--personal-intercept",
+				"--offline")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("personal intercept requires header based route"))
+		})
+
+	})
+
+	Context("proxy selection", func() {
+
+		tmpPath := NewTmpPath()
+		BeforeEach(func() {
+			tmpPath.SetPath(path.Dir(shell.MvnBin), path.Dir(shell.MirrordBin))
+		})
+		AfterEach(tmpPath.Restore)
+
+		It("should fail when proxy is unknown", func() {
+			_, err := ValidateArgumentsOf(developCmd).Passing("-r", "./test.sh", "-d", "hello-world", "--proxy", "kubefwd")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unknown proxy kubefwd"))
+		})
+
+		It("should fail when telepresence is not available for the default proxy", func() {
+			_, err := ValidateArgumentsOf(developCmd).Passing("-r", "./test.sh", "-d", "hello-world")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("unable to find telepresence on your $PATH"))
+		})
+
+		It("should run the process through mirrord", func() {
+			output, err := Run(developCmd).Passing("--deployment", "rating-service",
+				"--run", "java -jar rating.jar",
+				"--proxy", "mirrord",
+				"--offline")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring("exec --target deployment/rating-service --steal --"))
+			Expect(output).To(ContainSubstring("execute --run java -jar rating.jar"))
+		})
+
+	})
+
+	Context("importing environment", func() {
+
+		tmpPath := NewTmpPath()
+		originalClients := environment.Clients
+		BeforeEach(func() {
+			tmpPath.SetPath(path.Dir(shell.MvnBin), path.Dir(shell.TpSleepBin))
+			deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"name": "rating-service", "namespace": "test"},
+				"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{
+						"name":         "rating-service",
+						"env":          []interface{}{map[string]interface{}{"name": "LOG_LEVEL", "value": "debug"}},
+						"volumeMounts": []interface{}{map[string]interface{}{"name": "config", "mountPath": "/etc/ratings"}},
+					}},
+					"volumes": []interface{}{map[string]interface{}{"name": "config", "configMap": map[string]interface{}{"name": "settings"}}},
+				}}},
+			}}
+			settings := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "test"},
+				Data:       map[string]string{"app.properties": "stars=5"},
+			}
+			environment.Clients = func() (dynamic.Interface, kubernetes.Interface, string, error) {
+				return fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), deployment), fake.NewSimpleClientset(settings), "test", nil
+			}
+		})
+		AfterEach(func() {
+			tmpPath.Restore()
+			environment.Clients = originalClients
+		})
+
+		It("should export environment and write volumes of the target container", func() {
+			dir := TmpDir(GinkgoT(), "import-env")
+			envFile := path.Join(dir, ".env")
+			mountRoot := path.Join(dir, "root")
+
+			_, err := Run(developCmd).Passing("--deployment", "rating-service",
+				"--run", "java -jar rating.jar",
+				"--env-file", envFile,
+				"--mount-root", mountRoot,
+				"--offline")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.ReadFile(envFile)).To(Equal([]byte("LOG_LEVEL=debug\n")))
+			Expect(ioutil.ReadFile(path.Join(mountRoot, "etc", "ratings", "app.properties"))).To(Equal([]byte("stars=5")))
+		})
+
+		It("should fail when target does not exist", func() {
+			_, err := Run(developCmd).Passing("--deployment", "details",
+				"--run", "java -jar details.jar",
+				"--import-env",
+				"--offline")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed importing environment"))
+		})
+
+		It("should not share env file between several deployments", func() {
+			_, err := Run(developCmd).Passing("-d", "ratings-v1", "-d", "reviews-v1",
+				"--run", "java -jar app.jar",
+				"--env-file", ".env",
+				"--offline")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("--env-file can only be used when developing a single deployment"))
+		})
+	})
+
+	Context("multiple deployments", func() {
+
+		tmpPath := NewTmpPath()
+		BeforeEach(func() {
+			tmpPath.SetPath(path.Dir(shell.MvnBin), path.Dir(shell.MirrordBin), path.Dir(shell.TpSleepBin))
+		})
+		AfterEach(tmpPath.Restore)
+
+		It("should fail when deployment without run command is mixed with mappings", func() {
+			_, err := ValidateArgumentsOf(developCmd).Passing("-d", "ratings-v1=go run .", "-d", "reviews-v1")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(And(ContainSubstring("required flag(s)"), ContainSubstring("run")))
+		})
+
+		It("should fail when deployment is repeated", func() {
+			_, err := ValidateArgumentsOf(developCmd).Passing("-d", "ratings-v1=go run .", "-d", "ratings-v1=mvn quarkus:dev")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("deployment ratings-v1 is defined more than once"))
+		})
+
+		It("should start proxy with own command for each deployment", func() {
+			output, err := Run(developCmd).Passing("-d", "ratings-v1=java -jar ratings.jar",
+				"-d", "reviews-v1",
+				"--run", "java -jar reviews.jar",
+				"--session", "feature-x",
+				"--proxy", "mirrord",
+				"--offline")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(MatchRegexp(`\[ratings-v1\] .*--target deployment/ratings-v1 .*--run java -jar ratings.jar`))
+			Expect(output).To(MatchRegexp(`\[reviews-v1\] .*--target deployment/reviews-v1 .*--run java -jar reviews.jar`))
+		})
+
+		It("should load deployments from config file section", func() {
+			configFile := TmpFile(GinkgoT(), "config.yaml", `develop:
+  deployments:
+    ratings-v1: java -jar ratings.jar
+    reviews-v1: java -jar reviews.jar
+  proxy: mirrord
+`)
+
+			_, err := ValidateArgumentsOf(developCmd).Passing("--config", configFile.Name())
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(developCmd.Flag("deployment").Value.String()).To(Equal("ratings-v1=java -jar ratings.jar,reviews-v1=java -jar reviews.jar"))
+		})
+
+		It("should fail when port is shared by several deployments", func() {
+			_, err := ValidateArgumentsOf(developCmd).Passing("-d", "ratings-v1=java -jar ratings.jar", "-d", "reviews-v1=java -jar reviews.jar",
+				"--port", "9080")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("--port can only be used when developing a single deployment"))
+		})
+
+		It("should expose own ports of each deployment defined in config file section", func() {
+			configFile := TmpFile(GinkgoT(), "config.yaml", `develop:
+  deployments:
+    ratings-v1:
+      run: java -jar ratings.jar
+      port: ["4321:9080"]
+    reviews-v1:
+      run: java -jar reviews.jar
+      port: ["4322:9080", "4323:9090"]
+`)
+
+			output, err := Run(developCmd).Passing("--config", configFile.Name(), "--session", "feature-x", "--offline")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(MatchRegexp(`\[ratings-v1\] .*--deployment ratings-v1 .*--expose 4321:9080`))
+			Expect(output).To(MatchRegexp(`\[reviews-v1\] .*--deployment reviews-v1 .*--expose 4322:9080 --expose 4323:9090`))
+			Expect(output).ToNot(MatchRegexp(`\[ratings-v1\] .*--expose 432[23]`))
+			Expect(output).ToNot(MatchRegexp(`\[reviews-v1\] .*--expose 4321`))
+		})
+
+	})
+
 })
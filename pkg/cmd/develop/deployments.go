@@ -0,0 +1,125 @@
+package develop
+
+import (
+	"sort"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/spf13/cast"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/maistra/istio-workspace/pkg/cmd/execute"
+)
+
+// deployments holds the values of repeated deployment flag. It is seen as a plain string flag when defined once,
+// so the flag works the same way for all the commands sharing the session logic.
+type deployments struct {
+	values  []string
+	changed bool
+}
+
+func (d *deployments) Set(value string) error {
+	if !d.changed {
+		d.values = nil
+		d.changed = true
+	}
+	d.values = append(d.values, value)
+
+	return nil
+}
+
+func (d *deployments) String() string {
+	return strings.Join(d.values, ",")
+}
+
+func (d *deployments) Type() string {
+	return "string"
+}
+
+// deploymentRun is the deployment to develop against together with the command running its local counterpart
+// and the ports it listens on, if defined for the deployment.
+type deploymentRun struct {
+	deployment string
+	command    string
+	ports      []string
+}
+
+// deploymentRuns pairs each of the deployments with the command running it. Deployments defined as name=run-command
+// use their own command, the others use the one defined by the run flag. Ports of each deployment can be only
+// defined in the deployments section of the config file, as the port flag would make all the local processes
+// listen on the same ports.
+func deploymentRuns(cmd *cobra.Command) ([]deploymentRun, error) {
+	values := cmd.Flag("deployment").Value.(*deployments).values
+	run := cmd.Flag(execute.RunFlagName).Value.String()
+	if len(values) > 1 && cmd.Flag("port").Changed {
+		return nil, errors.Errorf("--port can only be used when developing a single deployment, " +
+			"define ports of each deployment in the deployments section of the config file instead")
+	}
+
+	runs := make([]deploymentRun, 0, len(values))
+	seen := map[string]bool{}
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		current := deploymentRun{deployment: parts[0], command: run}
+		if len(parts) == 2 {
+			current.command = parts[1]
+		}
+		if current.deployment == "" {
+			return nil, errors.Errorf("expected deployment in format name[=run-command], got %s", value)
+		}
+		if current.command == "" {
+			return nil, errors.Errorf(`required flag(s) "%s" not set`, execute.RunFlagName)
+		}
+		if seen[current.deployment] {
+			return nil, errors.Errorf("deployment %s is defined more than once", current.deployment)
+		}
+		seen[current.deployment] = true
+		current.ports = cast.ToStringSlice(viper.Get(cmd.Name() + ".deployments." + current.deployment + ".port"))
+		runs = append(runs, current)
+	}
+
+	return runs, nil
+}
+
+// syncDeploymentsSection sets deployment flag from the deployments section of the config file mapping deployment
+// names to the commands running them, unless the flag has been already set. Deployments listening on their own
+// ports define the command as run, e.g.
+//
+//	develop:
+//	  deployments:
+//	    ratings-v1: go run ./cmd/ratings
+//	    reviews-v1:
+//	      run: mvn quarkus:dev
+//	      port: [9080]
+func syncDeploymentsSection(cmd *cobra.Command) error {
+	section := viper.GetStringMap(cmd.Name() + ".deployments")
+	if cmd.Flag("deployment").Changed || len(section) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(section))
+	for name := range section {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := name
+		if run := deploymentCommand(section[name]); run != "" {
+			value += "=" + run
+		}
+		if err := cmd.Flags().Set("deployment", value); err != nil {
+			return errors.Wrapf(err, "failed setting deployment %s from config", name)
+		}
+	}
+
+	return nil
+}
+
+// deploymentCommand returns the command of the deployment defined either directly or as run.
+func deploymentCommand(definition interface{}) string {
+	if fields, ok := definition.(map[string]interface{}); ok {
+		return cast.ToString(fields["run"])
+	}
+
+	return cast.ToString(definition)
+}
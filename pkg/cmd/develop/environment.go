@@ -0,0 +1,72 @@
+package develop
+
+import (
+	"io/ioutil"
+	"os"
+
+	"emperror.dev/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/maistra/istio-workspace/pkg/environment"
+)
+
+// importEnvironment resolves the environment of the target container in the cluster, so the local process sees
+// the same variables and mounted ConfigMaps and Secrets regardless of the proxy used. The returned function removes
+// the temporary directory the volumes are written to.
+func importEnvironment(cmd *cobra.Command, deployment string) ([]string, func(), error) {
+	noop := func() {}
+	importEnv, _ := cmd.Flags().GetBool("import-env") // ignore error, should only occur if flag does not exist
+	envFile := cmd.Flag("env-file").Value.String()
+	mountRoot := cmd.Flag("mount-root").Value.String()
+	if !importEnv && envFile == "" && mountRoot == "" {
+		return nil, noop, nil
+	}
+
+	env, err := environment.Resolve(cmd.Flag("namespace").Value.String(), deployment, cmd.Flag("container").Value.String())
+	if err != nil {
+		return nil, noop, errors.WrapIfWithDetails(err, "failed importing environment", "deployment", deployment)
+	}
+	if envFile != "" {
+		if err := env.WriteEnvFile(envFile); err != nil {
+			return nil, noop, err
+		}
+	}
+	logger().Info("imported environment", "deployment", deployment, "environment", env.String())
+	if len(env.Files) == 0 {
+		return env.Env(), noop, nil
+	}
+
+	cleanup := noop
+	if mountRoot == "" {
+		if mountRoot, err = ioutil.TempDir("", "ike-mount-"); err != nil {
+			return nil, noop, errors.Wrap(err, "failed creating mount root")
+		}
+		root := mountRoot
+		cleanup = func() {
+			if err := os.RemoveAll(root); err != nil {
+				logger().Error(err, "failed removing mount root", "path", root)
+			}
+		}
+	}
+	if err := env.WriteFiles(mountRoot); err != nil {
+		cleanup()
+
+		return nil, noop, err
+	}
+
+	return append(env.Env(), environment.MountRootEnv+"="+mountRoot), cleanup, nil
+}
+
+// validateEnvironmentFlags ensures the files shared by all the local processes are not overwritten by each of them.
+func validateEnvironmentFlags(cmd *cobra.Command, runs []deploymentRun) error {
+	if len(runs) < 2 {
+		return nil
+	}
+	for _, name := range []string{"env-file", "mount-root"} {
+		if cmd.Flag(name).Value.String() != "" {
+			return errors.Errorf("--%s can only be used when developing a single deployment", name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package develop
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// prefixedWriter prefixes each line with the name of the deployment, so the output of several local processes
+// sharing the same terminal can be told apart. Writers sharing the same lock never interleave their writes.
+type prefixedWriter struct {
+	out       io.Writer
+	prefix    []byte
+	lock      *sync.Mutex
+	midOfLine bool
+}
+
+func newPrefixedWriter(out io.Writer, deployment string, lock *sync.Mutex) *prefixedWriter {
+	return &prefixedWriter{out: out, prefix: []byte("[" + deployment + "] "), lock: lock}
+}
+
+func (w *prefixedWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var buf bytes.Buffer
+	for _, line := range bytes.SplitAfter(p, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		if !w.midOfLine {
+			buf.Write(w.prefix)
+		}
+		buf.Write(line)
+		w.midOfLine = line[len(line)-1] != '\n'
+	}
+	if _, err := w.out.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
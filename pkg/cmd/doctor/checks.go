@@ -0,0 +1,371 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	istionetworkv1alpha3 "istio.io/api/networking/v1alpha3"
+	istioclient "istio.io/client-go/pkg/clientset/versioned"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	istiov1alpha1 "github.com/maistra/istio-workspace/api/maistra/v1alpha1"
+	controller "github.com/maistra/istio-workspace/controllers/session"
+	"github.com/maistra/istio-workspace/pkg/internal/session"
+	"github.com/maistra/istio-workspace/pkg/model"
+	"github.com/maistra/istio-workspace/pkg/proxy"
+	"github.com/maistra/istio-workspace/pkg/routing"
+	"github.com/maistra/istio-workspace/pkg/telepresence"
+)
+
+// Status is the outcome of a single check.
+type Status string
+
+const (
+	// Pass denotes the check succeeded.
+	Pass Status = "PASS"
+	// Warn denotes the check found something which might not work as expected.
+	Warn Status = "WARN"
+	// Fail denotes the check found something which has to be fixed.
+	Fail Status = "FAIL"
+	// Skip denotes the check could not be performed, e.g. because the previous one failed.
+	Skip Status = "SKIP"
+)
+
+const (
+	operatorSelector   = "app=istio-workspace"
+	watchNamespaceEnv  = "WATCH_NAMESPACE"
+	istioProxyName     = "istio-proxy"
+	sidecarInjectLabel = "sidecar.istio.io/inject"
+)
+
+// Result holds the outcome of a single check together with the hint on how to fix it.
+type Result struct {
+	Check   string
+	Status  Status
+	Message string
+	Hint    string
+}
+
+// checker runs the checks against the cluster, remembering what has been found by the previous checks.
+type checker struct {
+	kube        kubernetes.Interface
+	istio       istioclient.Interface
+	namespace   string
+	deployment  string
+	subsetLabel string
+	route       string
+	proxy       string
+
+	target   *appsv1.Deployment
+	services []corev1.Service
+	subset   string
+}
+
+type check struct {
+	name string
+	run  func(ctx context.Context) Result
+}
+
+func (c *checker) checks() []check {
+	return []check{
+		{"Session CRD", c.sessionCRD},
+		{"Operator", c.operator},
+		{"Sidecar", c.sidecar},
+		{"Service", c.service},
+		{"DestinationRule", c.destinationRule},
+		{"VirtualService", c.virtualService},
+		{"Route header", c.routeHeader},
+		{"Proxy", c.localProxy},
+	}
+}
+
+func (c *checker) sessionCRD(_ context.Context) Result {
+	group := istiov1alpha1.SchemeGroupVersion.Group
+	expected := istiov1alpha1.SchemeGroupVersion.String()
+	groups, err := c.kube.Discovery().ServerGroups()
+	if err != nil {
+		return Result{Status: Fail, Message: fmt.Sprintf("failed discovering API groups: %s", err)}
+	}
+	var served []string
+	for _, g := range groups.Groups {
+		if g.Name != group {
+			continue
+		}
+		for _, v := range g.Versions {
+			served = append(served, v.GroupVersion)
+		}
+	}
+	if len(served) == 0 {
+		return Result{Status: Fail, Message: fmt.Sprintf("%s API group is not served, the Session CRD is not installed", group),
+			Hint: "install istio-workspace operator into the cluster using 'ike install'"}
+	}
+	resources, err := c.kube.Discovery().ServerResourcesForGroupVersion(expected)
+	if err != nil {
+		return Result{Status: Fail, Message: fmt.Sprintf("served versions %v do not include %s used by ike", served, expected),
+			Hint: "align the version of ike with the version of the installed operator"}
+	}
+	for _, r := range resources.APIResources {
+		if r.Name == "sessions" {
+			return Result{Status: Pass, Message: fmt.Sprintf("sessions.%s is served", expected)}
+		}
+	}
+
+	return Result{Status: Fail, Message: fmt.Sprintf("%s does not serve sessions", expected),
+		Hint: "install istio-workspace operator into the cluster using 'ike install'"}
+}
+
+func (c *checker) operator(ctx context.Context) Result {
+	deployments, err := c.kube.AppsV1().Deployments(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: operatorSelector})
+	if k8sErrors.IsForbidden(err) {
+		deployments, err = c.kube.AppsV1().Deployments(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: operatorSelector})
+	}
+	if err != nil {
+		return Result{Status: Fail, Message: fmt.Sprintf("failed looking up the operator: %s", err)}
+	}
+	if len(deployments.Items) == 0 {
+		return Result{Status: Fail, Message: fmt.Sprintf("no deployment labeled %s found", operatorSelector),
+			Hint: "install istio-workspace operator into the cluster using 'ike install'"}
+	}
+
+	var notWatching []string
+	for i := range deployments.Items {
+		operator := &deployments.Items[i]
+		name := operator.Namespace + "/" + operator.Name
+		if operator.Status.AvailableReplicas == 0 {
+			notWatching = append(notWatching, name+" (not running)")
+
+			continue
+		}
+		watched := watchedNamespaces(operator)
+		if len(watched) == 0 {
+			return Result{Status: Pass, Message: fmt.Sprintf("%s is running and watching all namespaces", name)}
+		}
+		for _, ns := range watched {
+			if ns == c.namespace {
+				return Result{Status: Pass, Message: fmt.Sprintf("%s is running and watching %s", name, c.namespace)}
+			}
+		}
+		notWatching = append(notWatching, fmt.Sprintf("%s (watching %s)", name, strings.Join(watched, ",")))
+	}
+
+	return Result{Status: Fail, Message: fmt.Sprintf("no running operator watches %s: %s", c.namespace, strings.Join(notWatching, ", ")),
+		Hint: fmt.Sprintf("add %s to %s environment variable of the operator or make sure its pod is running", c.namespace, watchNamespaceEnv)}
+}
+
+// watchedNamespaces returns namespaces the operator is restricted to, empty for all namespaces.
+func watchedNamespaces(operator *appsv1.Deployment) []string {
+	for _, container := range operator.Spec.Template.Spec.Containers {
+		for _, env := range container.Env {
+			if env.Name != watchNamespaceEnv {
+				continue
+			}
+			if env.ValueFrom != nil && env.ValueFrom.FieldRef != nil && env.ValueFrom.FieldRef.FieldPath == "metadata.namespace" {
+				return []string{operator.Namespace}
+			}
+			var namespaces []string
+			for _, ns := range strings.Split(env.Value, ",") {
+				if ns = strings.TrimSpace(ns); ns != "" {
+					namespaces = append(namespaces, ns)
+				}
+			}
+
+			return namespaces
+		}
+	}
+
+	return nil
+}
+
+func (c *checker) sidecar(ctx context.Context) Result {
+	if c.deployment == "" {
+		return Result{Status: Skip, Message: "no deployment defined, use --deployment to check it"}
+	}
+	target, err := c.kube.AppsV1().Deployments(c.namespace).Get(ctx, c.deployment, metav1.GetOptions{})
+	if err != nil {
+		return Result{Status: Fail, Message: fmt.Sprintf("deployment %s not found in %s: %s", c.deployment, c.namespace, err),
+			Hint: "check the name of the deployment and the namespace, DeploymentConfigs are not checked"}
+	}
+	c.target = target
+
+	selector, err := metav1.LabelSelectorAsSelector(target.Spec.Selector)
+	if err != nil {
+		return Result{Status: Fail, Message: fmt.Sprintf("invalid selector of deployment %s: %s", c.deployment, err)}
+	}
+	pods, err := c.kube.CoreV1().Pods(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return Result{Status: Fail, Message: fmt.Sprintf("failed listing pods of %s: %s", c.deployment, err)}
+	}
+	hint := fmt.Sprintf("label %s namespace with istio-injection=enabled or annotate the pod template with %s: \"true\" and restart the deployment",
+		c.namespace, sidecarInjectLabel)
+	if len(pods.Items) == 0 {
+		if target.Spec.Template.Annotations[sidecarInjectLabel] == "true" {
+			return Result{Status: Warn, Message: fmt.Sprintf("no pods of %s are running, the template requests sidecar injection", c.deployment)}
+		}
+
+		return Result{Status: Warn, Message: fmt.Sprintf("no pods of %s are running to check the sidecar", c.deployment), Hint: hint}
+	}
+	for _, container := range pods.Items[0].Spec.Containers {
+		if container.Name == istioProxyName {
+			return Result{Status: Pass, Message: fmt.Sprintf("pod %s has %s sidecar", pods.Items[0].Name, istioProxyName)}
+		}
+	}
+
+	return Result{Status: Fail, Message: fmt.Sprintf("pod %s has no %s sidecar", pods.Items[0].Name, istioProxyName), Hint: hint}
+}
+
+func (c *checker) service(ctx context.Context) Result {
+	if c.target == nil {
+		return Result{Status: Skip, Message: "requires the deployment"}
+	}
+	services, err := c.kube.CoreV1().Services(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return Result{Status: Fail, Message: fmt.Sprintf("failed listing services: %s", err)}
+	}
+	podLabels := labels.Set(c.target.Spec.Template.Labels)
+	var names []string
+	for i := range services.Items {
+		svc := services.Items[i]
+		if len(svc.Spec.Selector) > 0 && labels.SelectorFromSet(svc.Spec.Selector).Matches(podLabels) {
+			c.services = append(c.services, svc)
+			names = append(names, svc.Name)
+		}
+	}
+	if len(names) == 0 {
+		return Result{Status: Fail, Message: fmt.Sprintf("no service selects pods of %s", c.deployment),
+			Hint: "create a Service with selector matching the labels of the pod template"}
+	}
+
+	return Result{Status: Pass, Message: fmt.Sprintf("selected by %s", strings.Join(names, ", "))}
+}
+
+func (c *checker) destinationRule(ctx context.Context) Result {
+	if len(c.services) == 0 {
+		return Result{Status: Skip, Message: "requires the service"}
+	}
+	version, found := c.target.Spec.Template.Labels[c.subsetLabel]
+	if !found {
+		return Result{Status: Fail, Message: fmt.Sprintf("pod template of %s has no %s label", c.deployment, c.subsetLabel),
+			Hint: "label the pods with the version or use --subset-label with the label identifying the version subsets"}
+	}
+	rules, err := c.istio.NetworkingV1alpha3().DestinationRules(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return Result{Status: Fail, Message: fmt.Sprintf("failed listing destination rules: %s", err)}
+	}
+	for _, rule := range rules.Items {
+		if !c.routesToService(rule.Spec.Host) {
+			continue
+		}
+		for _, subset := range rule.Spec.Subsets {
+			if subset.Labels[c.subsetLabel] == version {
+				c.subset = subset.Name
+
+				return Result{Status: Pass, Message: fmt.Sprintf("%s defines subset %s for %s=%s", rule.Name, subset.Name, c.subsetLabel, version)}
+			}
+		}
+	}
+
+	return Result{Status: Fail, Message: fmt.Sprintf("no DestinationRule defines a subset for %s=%s", c.subsetLabel, version),
+		Hint: fmt.Sprintf("create a DestinationRule for %s with subset selecting %s=%s", c.services[0].Name, c.subsetLabel, version)}
+}
+
+func (c *checker) virtualService(ctx context.Context) Result {
+	if len(c.services) == 0 {
+		return Result{Status: Skip, Message: "requires the service"}
+	}
+	virtualServices, err := c.istio.NetworkingV1alpha3().VirtualServices(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return Result{Status: Fail, Message: fmt.Sprintf("failed listing virtual services: %s", err)}
+	}
+	for _, vs := range virtualServices.Items {
+		for _, route := range vs.Spec.Http {
+			if name, found := c.routeDestination(route.Route); found {
+				return Result{Status: Pass, Message: fmt.Sprintf("%s routes to %s", vs.Name, name)}
+			}
+		}
+	}
+
+	return Result{Status: Fail, Message: fmt.Sprintf("no VirtualService routes to %s", c.services[0].Name),
+		Hint: "create a VirtualService with http route to the service, ike clones it to route the session traffic"}
+}
+
+func (c *checker) routeDestination(destinations []*istionetworkv1alpha3.HTTPRouteDestination) (string, bool) {
+	for _, destination := range destinations {
+		if destination.Destination == nil || !c.routesToService(destination.Destination.Host) {
+			continue
+		}
+		if destination.Destination.Subset == "" || destination.Destination.Subset == c.subset {
+			return destination.Destination.Host, true
+		}
+	}
+
+	return "", false
+}
+
+func (c *checker) routesToService(host string) bool {
+	for _, svc := range c.services {
+		hostName := model.HostName{Name: svc.Name, Namespace: svc.Namespace}
+		if hostName.Match(host) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *checker) routeHeader(_ context.Context) Result {
+	route, err := session.ParseRoute(c.route)
+	if err != nil {
+		return Result{Status: Fail, Message: err.Error()}
+	}
+	if route == nil {
+		route = &istiov1alpha1.Route{Type: controller.RouteStrategyHeader, Name: routing.DefaultRouteHeaderName}
+	}
+	if route.Type != controller.RouteStrategyHeader {
+		return Result{Status: Skip, Message: fmt.Sprintf("route of type %s is not header based", route.Type)}
+	}
+	for _, header := range routing.PropagatedHeaders {
+		if strings.EqualFold(header, route.Name) {
+			return Result{Status: Pass, Message: fmt.Sprintf("%s is among commonly propagated headers", route.Name)}
+		}
+	}
+	known := append([]string{}, routing.PropagatedHeaders...)
+	sort.Strings(known)
+
+	return Result{Status: Warn, Message: fmt.Sprintf("%s is not among commonly propagated headers %v", route.Name, known),
+		Hint: fmt.Sprintf("make sure all the services on the call path pass %s header along to the services they call", route.Name)}
+}
+
+func (c *checker) localProxy(_ context.Context) Result {
+	backend, err := proxy.Lookup(c.proxy)
+	if err != nil {
+		return Result{Status: Fail, Message: err.Error()}
+	}
+	if err := backend.Available(); err != nil {
+		return Result{Status: Fail, Message: fmt.Sprintf("%s is not available: %s", c.proxy, err)}
+	}
+	if c.proxy != proxy.DefaultBackend {
+		return Result{Status: Pass, Message: fmt.Sprintf("%s is available", c.proxy)}
+	}
+
+	version, err := telepresence.GetVersion()
+	if err != nil {
+		return Result{Status: Fail, Message: err.Error()}
+	}
+	major, err := telepresence.MajorVersion(version)
+	if err != nil {
+		return Result{Status: Fail, Message: err.Error()}
+	}
+	if major != 0 && major != 2 {
+		return Result{Status: Fail, Message: fmt.Sprintf("telepresence %s is not supported", version),
+			Hint: "install Telepresence 2 or the legacy 0.x version"}
+	}
+
+	return Result{Status: Pass, Message: fmt.Sprintf("telepresence %s is available", version)}
+}
@@ -0,0 +1,94 @@
+package doctor
+
+import (
+	"fmt"
+	"io"
+
+	"emperror.dev/errors"
+	"github.com/spf13/cobra"
+	istioclient "istio.io/client-go/pkg/clientset/versioned"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/maistra/istio-workspace/pkg/cmd/config"
+	"github.com/maistra/istio-workspace/pkg/model"
+	"github.com/maistra/istio-workspace/pkg/proxy"
+)
+
+// Clients creates the clients used to inspect the cluster together with the namespace of the current context.
+var Clients = func() (kubernetes.Interface, istioclient.Interface, string, error) {
+	c, restCfg, namespace, err := proxy.ClusterClient()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	ic, err := istioclient.NewForConfig(restCfg)
+	if err != nil {
+		return nil, nil, "", errors.Wrap(err, "failed to create istio client set")
+	}
+
+	return c, ic, namespace, nil
+}
+
+// NewCmd creates instance of "doctor" Cobra Command with flags and execution logic defined.
+func NewCmd() *cobra.Command {
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Verifies the cluster and your machine are ready for istio-workspace",
+		Long: "Runs preflight checks of the Session CRD, the operator, the mesh configuration of the deployment " +
+			"and the local proxy, reporting the result of each of them together with hints on how to fix the failing ones.",
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return errors.Wrap(config.SyncFullyQualifiedFlags(cmd), "failed syncing flags")
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kube, istio, namespace, err := Clients()
+			if err != nil {
+				return err
+			}
+			if ns := cmd.Flag("namespace").Value.String(); ns != "" {
+				namespace = ns
+			}
+			c := &checker{
+				kube:        kube,
+				istio:       istio,
+				namespace:   namespace,
+				deployment:  cmd.Flag("deployment").Value.String(),
+				subsetLabel: cmd.Flag("subset-label").Value.String(),
+				route:       cmd.Flag("route").Value.String(),
+				proxy:       cmd.Flag("proxy").Value.String(),
+			}
+
+			failed := 0
+			for _, check := range c.checks() {
+				result := check.run(cmd.Context())
+				result.Check = check.name
+				printResult(cmd.OutOrStdout(), result)
+				if result.Status == Fail {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return errors.Errorf("%d check(s) failed", failed)
+			}
+
+			return nil
+		},
+	}
+
+	doctorCmd.Flags().StringP("deployment", "d", "", "name of the deployment to check (only cluster wide checks are performed when not defined)")
+	doctorCmd.Flags().StringP("namespace", "n", "", "namespace of the deployment (defaults to default for the current context)")
+	doctorCmd.Flags().String("subset-label", model.DefaultSubsetLabel, "name of the pod label identifying version subsets")
+	doctorCmd.Flags().String("route", "", "traffic route in the format of type:name=value you are going to use "+
+		"(defaults to X-Workspace-Route header)")
+	doctorCmd.Flags().String("proxy", proxy.DefaultBackend, fmt.Sprintf("local proxy you are going to use, one of %v", proxy.Names()))
+
+	doctorCmd.Flags().VisitAll(config.BindFullyQualifiedFlag(doctorCmd))
+
+	return doctorCmd
+}
+
+func printResult(out io.Writer, result Result) {
+	_, _ = fmt.Fprintf(out, "[%s] %-16s %s\n", result.Status, result.Check, result.Message)
+	if result.Hint != "" && (result.Status == Fail || result.Status == Warn) {
+		_, _ = fmt.Fprintf(out, "       %-16s Hint: %s\n", "", result.Hint)
+	}
+}
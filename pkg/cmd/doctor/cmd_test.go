@@ -0,0 +1,227 @@
+package doctor_test
+
+import (
+	"path"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	istionetworkv1alpha3 "istio.io/api/networking/v1alpha3"
+	istionetwork "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	istioclient "istio.io/client-go/pkg/clientset/versioned"
+	istiofake "istio.io/client-go/pkg/clientset/versioned/fake"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	. "github.com/maistra/istio-workspace/pkg/cmd"
+	"github.com/maistra/istio-workspace/pkg/cmd/doctor"
+	. "github.com/maistra/istio-workspace/test"
+	"github.com/maistra/istio-workspace/test/shell"
+)
+
+var _ = Describe("Usage of ike doctor command", func() {
+
+	var (
+		doctorCmd      *cobra.Command
+		kubeObjects    []runtime.Object
+		istioObjects   []runtime.Object
+		crdInstalled   bool
+		originalClient = doctor.Clients
+		tmpPath        = NewTmpPath()
+	)
+
+	BeforeEach(func() {
+		doctorCmd = doctor.NewCmd()
+		doctorCmd.SilenceUsage = true
+		doctorCmd.SilenceErrors = true
+		NewCmd().AddCommand(doctorCmd)
+
+		tmpPath.SetPath(path.Dir(shell.MirrordBin))
+		crdInstalled = true
+		kubeObjects = []runtime.Object{
+			operator("istio-workspace-system", corev1.EnvVar{Name: "WATCH_NAMESPACE", Value: ""}),
+			deployment("ratings-v1", "v1", true),
+			pod("ratings-v1-abcd", "v1", true),
+			service("ratings"),
+		}
+		istioObjects = []runtime.Object{
+			destinationRule("ratings", "v1"),
+			virtualService("ratings", "v1"),
+		}
+		doctor.Clients = func() (kubernetes.Interface, istioclient.Interface, string, error) {
+			kube := fake.NewSimpleClientset(kubeObjects...)
+			if crdInstalled {
+				kube.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+					{GroupVersion: "maistra.io/v1alpha1", APIResources: []metav1.APIResource{{Name: "sessions", Kind: "Session"}}},
+				}
+			}
+
+			return kube, istiofake.NewSimpleClientset(istioObjects...), "test", nil
+		}
+	})
+
+	AfterEach(func() {
+		doctor.Clients = originalClient
+		tmpPath.Restore()
+	})
+
+	It("should pass all checks of properly configured deployment", func() {
+		output, err := Run(doctorCmd).Passing("-d", "ratings-v1", "--proxy", "mirrord")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(ContainSubstring("[PASS] Session CRD      sessions.maistra.io/v1alpha1 is served"))
+		Expect(output).To(ContainSubstring("[PASS] Operator         istio-workspace-system/istio-workspace is running and watching all namespaces"))
+		Expect(output).To(ContainSubstring("[PASS] Sidecar          pod ratings-v1-abcd has istio-proxy sidecar"))
+		Expect(output).To(ContainSubstring("[PASS] Service          selected by ratings"))
+		Expect(output).To(ContainSubstring("[PASS] DestinationRule  ratings defines subset v1 for version=v1"))
+		Expect(output).To(ContainSubstring("[PASS] VirtualService   ratings routes to ratings"))
+		Expect(output).To(ContainSubstring("[PASS] Route header     x-workspace-route is among commonly propagated headers"))
+		Expect(output).To(ContainSubstring("[PASS] Proxy            mirrord is available"))
+	})
+
+	It("should skip deployment checks when deployment is not defined", func() {
+		output, err := Run(doctorCmd).Passing("--proxy", "mirrord")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(ContainSubstring("[SKIP] Sidecar          no deployment defined"))
+		Expect(output).To(ContainSubstring("[SKIP] DestinationRule  requires the service"))
+	})
+
+	It("should fail when session CRD is not installed", func() {
+		crdInstalled = false
+
+		output, err := Run(doctorCmd).Passing("--proxy", "mirrord")
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("1 check(s) failed"))
+		Expect(output).To(ContainSubstring("[FAIL] Session CRD      maistra.io API group is not served"))
+		Expect(output).To(ContainSubstring("Hint: install istio-workspace operator into the cluster"))
+	})
+
+	It("should fail when operator does not watch the namespace", func() {
+		kubeObjects[0] = operator("istio-workspace-system", corev1.EnvVar{Name: "WATCH_NAMESPACE", Value: "prod,stage"})
+
+		output, err := Run(doctorCmd).Passing("--proxy", "mirrord")
+
+		Expect(err).To(HaveOccurred())
+		Expect(output).To(ContainSubstring("[FAIL] Operator         no running operator watches test: istio-workspace-system/istio-workspace (watching prod,stage)"))
+		Expect(output).To(ContainSubstring("Hint: add test to WATCH_NAMESPACE environment variable of the operator"))
+	})
+
+	It("should fail when deployment has no sidecar and no service", func() {
+		kubeObjects = append(kubeObjects, deployment("details-v1", "v2", false), pod("details-v1-abcd", "v2", false))
+
+		output, err := Run(doctorCmd).Passing("-d", "details-v1", "--proxy", "mirrord")
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("2 check(s) failed"))
+		Expect(output).To(ContainSubstring("[FAIL] Sidecar          pod details-v1-abcd has no istio-proxy sidecar"))
+		Expect(output).To(ContainSubstring("[FAIL] Service          no service selects pods of details-v1"))
+		Expect(output).To(ContainSubstring("[SKIP] VirtualService   requires the service"))
+	})
+
+	It("should fail when no subset matches the version of the deployment", func() {
+		istioObjects = []runtime.Object{destinationRule("ratings", "v2"), virtualService("ratings", "v2")}
+
+		output, err := Run(doctorCmd).Passing("-d", "ratings-v1", "--proxy", "mirrord")
+
+		Expect(err).To(HaveOccurred())
+		Expect(output).To(ContainSubstring("[FAIL] DestinationRule  no DestinationRule defines a subset for version=v1"))
+		Expect(output).To(ContainSubstring("[FAIL] VirtualService   no VirtualService routes to ratings"))
+	})
+
+	It("should warn when route header is not commonly propagated", func() {
+		output, err := Run(doctorCmd).Passing("--route", "header:x-my-route=feature", "--proxy", "mirrord")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(ContainSubstring("[WARN] Route header     x-my-route is not among commonly propagated headers"))
+		Expect(output).To(ContainSubstring("Hint: make sure all the services on the call path pass x-my-route header along"))
+	})
+
+	It("should check telepresence version", func() {
+		tmpPath.SetPath(path.Dir(shell.TpVersionBin))
+		restore := TemporaryEnvVars("TELEPRESENCE_VERSION", "Client: v1.0.1 (api v3)")
+		defer restore()
+
+		output, err := Run(doctorCmd).Passing()
+
+		Expect(err).To(HaveOccurred())
+		Expect(output).To(ContainSubstring("[FAIL] Proxy            telepresence Client: v1.0.1 (api v3) is not supported"))
+	})
+})
+
+func operator(namespace string, watch corev1.EnvVar) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "istio-workspace", Namespace: namespace, Labels: map[string]string{"app": "istio-workspace"}},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "istio-workspace", Env: []corev1.EnvVar{watch}}}},
+			},
+		},
+		Status: appsv1.DeploymentStatus{AvailableReplicas: 1},
+	}
+}
+
+func deployment(name, version string, inMesh bool) *appsv1.Deployment {
+	app := name[:len(name)-3]
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app, "version": version}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"app": app, "version": version},
+					Annotations: map[string]string{"sidecar.istio.io/inject": map[bool]string{true: "true", false: "false"}[inMesh]},
+				},
+			},
+		},
+	}
+}
+
+func pod(name, version string, inMesh bool) *corev1.Pod {
+	app := name[:len(name)-8]
+	containers := []corev1.Container{{Name: app}}
+	if inMesh {
+		containers = append(containers, corev1.Container{Name: "istio-proxy"})
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test", Labels: map[string]string{"app": app, "version": version}},
+		Spec:       corev1.PodSpec{Containers: containers},
+	}
+}
+
+func service(name string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": name}},
+	}
+}
+
+func destinationRule(host, version string) *istionetwork.DestinationRule {
+	return &istionetwork.DestinationRule{
+		ObjectMeta: metav1.ObjectMeta{Name: host, Namespace: "test"},
+		Spec: istionetworkv1alpha3.DestinationRule{
+			Host:    host,
+			Subsets: []*istionetworkv1alpha3.Subset{{Name: version, Labels: map[string]string{"version": version}}},
+		},
+	}
+}
+
+func virtualService(host, subset string) *istionetwork.VirtualService {
+	return &istionetwork.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{Name: host, Namespace: "test"},
+		Spec: istionetworkv1alpha3.VirtualService{
+			Hosts: []string{host},
+			Http: []*istionetworkv1alpha3.HTTPRoute{
+				{Route: []*istionetworkv1alpha3.HTTPRouteDestination{{Destination: &istionetworkv1alpha3.Destination{Host: host, Subset: subset}}}},
+			},
+		},
+	}
+}
@@ -1,22 +1,18 @@
 package execute
 
 import (
-	"fmt"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
 	"emperror.dev/errors"
 	"github.com/fsnotify/fsnotify"
-	gocmd "github.com/go-cmd/cmd"
 	"github.com/go-logr/logr"
 	"github.com/spf13/cobra"
 
 	"github.com/maistra/istio-workspace/pkg/cmd/config"
 	"github.com/maistra/istio-workspace/pkg/log"
-	"github.com/maistra/istio-workspace/pkg/shell"
 	"github.com/maistra/istio-workspace/pkg/watch"
 )
 
@@ -27,6 +23,20 @@ const (
 	NoBuildFlagName = "no-build"
 	// RunFlagName is a name of the flag which defines process to be executed.
 	RunFlagName = "run"
+	// ShellFlagName is a name of the flag which enforces running build and run commands through the shell.
+	ShellFlagName = "shell"
+	// MaxRestartsFlagName is a name of the flag limiting restarts of the crashing process.
+	MaxRestartsFlagName = "max-restarts"
+	// RestartBackoffFlagName is a name of the flag defining the initial delay before restarting the crashed process.
+	RestartBackoffFlagName = "restart-backoff"
+	// StopTimeoutFlagName is a name of the flag defining how long to wait for processes to stop before killing them.
+	StopTimeoutFlagName = "stop-timeout"
+	// RuleFlagName is a name of the flag defining actions taken when watched files matching the pattern change.
+	RuleFlagName = "rule"
+	// PollFlagName is a name of the flag which enables detecting changes by polling.
+	PollFlagName = "poll"
+	// QuietPeriodFlagName is a name of the flag defining how long watched files should stay unchanged before handling their changes.
+	QuietPeriodFlagName = "quiet-period"
 )
 
 // DefaultExclusions is a slices with glob patterns excluded by default.
@@ -54,10 +64,21 @@ func NewCmd() *cobra.Command {
 	executeCmd.Flags().StringP(BuildFlagName, "b", "", "command to build your application before run")
 	executeCmd.Flags().Bool(NoBuildFlagName, false, "always skips build")
 	executeCmd.Flags().StringP(RunFlagName, "r", "", "command to run your application")
+	executeCmd.Flags().Bool(ShellFlagName, false, "run build and run commands through "+shellBin+" -c "+
+		"(used automatically when they contain pipes, redirects, variables or start with env assignments)")
+	executeCmd.Flags().Int(MaxRestartsFlagName, 5, "number of consecutive restarts of the crashing process before giving up (negative for no limit)")
+	executeCmd.Flags().Duration(RestartBackoffFlagName, time.Second, "delay before restarting the crashed process, doubled with each consecutive crash")
+	executeCmd.Flags().Duration(StopTimeoutFlagName, 10*time.Second, "time given to the processes to stop gracefully before they are killed")
 	// Watch config
 	executeCmd.Flags().Bool("watch", false, "enables watch")
 	executeCmd.Flags().StringSliceP("dir", "w", []string{"."}, "list of directories to watch")
 	executeCmd.Flags().StringSlice("exclude", DefaultExclusions, "list of patterns to exclude (defaults to telepresence.log which is always excluded)")
+	executeCmd.Flags().StringArray(RuleFlagName, []string{}, "action taken when files matching the pattern change in the form of pattern=action, "+
+		"where action is one of build (and restart), restart (without build), none or hook:command. Can be repeated, first matching rule wins "+
+		"(files not matching any of them are rebuilt)")
+	executeCmd.Flags().Duration(QuietPeriodFlagName, 0, "time without further changes to wait for before handling them")
+	executeCmd.Flags().Bool(PollFlagName, false, "detect changes by periodically scanning watched directories instead of relying on file system notifications "+
+		"(used automatically when inotify limits are exhausted)")
 	executeCmd.Flags().Int64("interval", 500, "watch interval (in ms)")
 	if err := executeCmd.Flags().MarkHidden("interval"); err != nil {
 		logger().Error(err, "failed while trying to hide a flag")
@@ -73,61 +94,26 @@ func NewCmd() *cobra.Command {
 }
 
 func execute(command *cobra.Command, args []string) error {
-	watcher := func(restart chan int32) (func(), error) {
-		dirs, _ := command.Flags().GetStringSlice("dir")
-		excluded, e := command.Flags().GetStringSlice("exclude")
-		if e != nil {
-			return nil, errors.Wrap(e, "failed obtaining exclude flag")
-		}
-		excluded = append(excluded, DefaultExclusions...)
-
-		ms, _ := command.Flags().GetInt64("interval")
-		w, err := watch.CreateWatch(ms).
-			WithHandlers(func(events []fsnotify.Event) error {
-				for _, event := range events {
-					_, _ = command.OutOrStdout().Write([]byte(event.Name + " changed. Restarting process.\n"))
-				}
-				restart <- 1
-
-				return nil
-			}).
-			Excluding(excluded...).
-			OnPaths(dirs...)
-
-		if err != nil {
-			return nil, errors.WrapIf(err, "failed handling watch event")
-		}
-
-		w.Start()
-
-		return w.Close, nil
+	s, err := newSupervisor(command)
+	if err != nil {
+		return err
 	}
 
-	kill := make(chan struct{})
-	defer close(kill)
-
-	restart := make(chan int32)
-	defer close(restart)
+	watching, e := command.Flags().GetBool("watch")
+	if e != nil {
+		return errors.Wrap(e, "failed obtaining watch flag")
+	}
 
-	if w, e := command.Flags().GetBool("watch"); w && e == nil {
-		closeWatch, err := watcher(restart)
+	var changes *changes
+	if watching {
+		changes = newChanges()
+		closeWatch, err := watcher(command, changes)
 		if err != nil {
 			return errors.WrapIf(err, "failed watching")
 		}
 		defer closeWatch()
-	} else if e != nil {
-		return errors.Wrap(e, "failed obtaining watch flag")
 	}
 
-	go func() {
-		for i := range restart {
-			if i > 0 { // not initial restart
-				kill <- struct{}{}
-			}
-			go buildAndRun(buildExecutor(command), runExecutor(command), kill, restart)
-		}
-	}()
-
 	hookChan := make(chan os.Signal, 1)
 	testSigtermGuard := make(chan struct{})
 	defer close(testSigtermGuard)
@@ -140,85 +126,56 @@ func execute(command *cobra.Command, args []string) error {
 		close(hookChan)
 	}()
 
-	restart <- 0
-
-	<-hookChan
+	err = s.supervise(changes, hookChan)
+	s.printSummary(command.OutOrStdout())
 
-	kill <- struct{}{}
-
-	return nil
+	return err
 }
 
-type stopper func() error
-type executor func(restart chan int32) stopper
-
-func buildExecutor(command *cobra.Command) executor {
-	return func(chan int32) stopper {
-		buildFlag := command.Flag(BuildFlagName)
-
-		skipBuild, _ := command.Flags().GetBool(NoBuildFlagName)
-
-		shouldRunBuild := buildFlag.Changed && !skipBuild
-		if !shouldRunBuild {
-			return func() error { return nil }
-		}
-
-		buildCmd := command.Flag("build").Value.String()
-		buildArgs := strings.Split(buildCmd, " ")
-
-		b := gocmd.NewCmdOptions(shell.StreamOutput, buildArgs[0], buildArgs[1:]...)
-		b.Env = os.Environ()
-		shell.RedirectStreams(b, command.OutOrStdout(), command.OutOrStderr())
-		logger().V(1).Info("starting build command",
-			"cmd", b.Name,
-			"args", fmt.Sprint(b.Args),
-		)
-
-		<-b.Start()
-		<-b.Done()
-
-		status := b.Status()
-		if status.Error != nil {
-			logger().Error(status.Error, "failed to run build command")
-		}
+// watcher notifies about changes in the watched directories with the actions of the rules they match. Changes
+// occurring while the previous ones are still being handled (e.g. during the build) are accumulated.
+func watcher(command *cobra.Command, changes *changes) (func(), error) {
+	dirs, _ := command.Flags().GetStringSlice("dir")
+	excluded, e := command.Flags().GetStringSlice("exclude")
+	if e != nil {
+		return nil, errors.Wrap(e, "failed obtaining exclude flag")
+	}
+	excluded = append(excluded, DefaultExclusions...)
 
-		return b.Stop
+	definitions, _ := command.Flags().GetStringArray(RuleFlagName)
+	useShell, _ := command.Flags().GetBool(ShellFlagName)
+	rules, e := newRules(dirs, definitions, useShell)
+	if e != nil {
+		return nil, errors.WrapIf(e, "invalid watch rules")
 	}
-}
 
-func runExecutor(command *cobra.Command) executor {
-	return func(restart chan int32) stopper {
-		runCmd := command.Flag("run").Value.String()
-		runArgs := strings.Split(runCmd, " ")
-		r := gocmd.NewCmdOptions(shell.StreamOutput, runArgs[0], runArgs[1:]...)
-		r.Env = os.Environ()
-		shell.RedirectStreams(r, command.OutOrStdout(), command.OutOrStderr())
-		logger().V(1).Info("starting run command",
-			"cmd", r.Name,
-			"args", fmt.Sprint(r.Args),
-		)
-		go func(statusChan <-chan gocmd.Status) {
-			status := <-statusChan
-			if status.Exit > 0 {
-				logger().Error(status.Error, fmt.Sprintf("failed to run [%s] command", command.Name()))
-				time.Sleep(5000 * time.Millisecond) // to avoid too frequent restarts of instantly failing process so that user can actually notice
-				restart <- 10
+	ms, _ := command.Flags().GetInt64("interval")
+	quietPeriod, _ := command.Flags().GetDuration(QuietPeriodFlagName)
+	builder := watch.CreateWatch(ms)
+	if poll, _ := command.Flags().GetBool(PollFlagName); poll {
+		builder = builder.Polling(watch.DefaultPollInterval)
+	}
+	w, err := builder.
+		WithHandlers(func(events []fsnotify.Event) error {
+			for _, event := range events {
+				r := rules.match(event.Name)
+				_, _ = command.OutOrStdout().Write([]byte(message(event, r) + "\n"))
+				changes.add(r)
 			}
-		}(r.Start())
 
-		return r.Stop
-	}
-}
+			return nil
+		}).
+		Debouncing(quietPeriod).
+		Excluding(excluded...).
+		OnPaths(dirs...)
 
-func buildAndRun(builder, runner executor, kill chan struct{}, restart chan int32) {
-	_ = builder(restart)
-	stopRun := runner(restart)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed handling watch event")
+	}
 
-	<-kill
+	w.Start()
 
-	if e := stopRun(); e != nil {
-		logger().Error(e, "failed while trying to stop RUN process")
-	}
+	return w.Close, nil
 }
 
 // simulateSigterm allow us to simulate a SIGTERM when running cobra command inside a test.
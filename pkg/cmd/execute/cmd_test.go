@@ -159,7 +159,8 @@ var _ = Describe("Usage of ike execute command", func() {
 
 		It("should only re-run java process when --no-build flag specified but build defined in config", func() {
 			// given
-			configFile := TmpFile(GinkgoT(), "config.yaml", `watch:
+			// flags are read from the section named after the command, run command has to be present as empty one fails
+			configFile := TmpFile(GinkgoT(), "config.yaml", `execute:
   run: "java -jar config.jar"
   build: "mvn clean install"
 `)
@@ -188,10 +189,193 @@ var _ = Describe("Usage of ike execute command", func() {
 			var output string
 			Eventually(outputChan).Should(Receive(&output))
 			Expect(output).To(ContainSubstring("rating.java changed. Restarting process."))
+			Expect(output).To(ContainSubstring("java -jar config.jar"))
 			Expect(strings.Count(output, "mvn clean install")).To(Equal(0), "Expected build to not be executed.")
 		})
 	})
 
+	Context("watching file changes with rules", func() {
+
+		tmpPath := NewTmpPath()
+		BeforeEach(func() {
+			tmpPath.SetPath(path.Dir(shell.MvnBin), path.Dir(shell.JavaBin))
+		})
+
+		AfterEach(tmpPath.Restore)
+
+		It("should only re-run java process when matching rule skips build", func() {
+			// given
+			tmpDir := TmpDir(GinkgoT(), "rule-restart")
+			code := TmpFile(GinkgoT(), tmpDir+"/watch-test/rating.java", "content")
+			outputChan := make(chan string)
+
+			go shell.ExecuteCommand(outputChan, func() (string, error) {
+				return Run(executeCmd).Passing(
+					"--run", "java -jar rating.jar",
+					"--build", "mvn clean install",
+					"--watch",
+					"--dir", tmpDir+"/watch-test",
+					"--rule", "*.java=restart",
+					"--interval", "10",
+				)
+			})()
+
+			// when
+			time.Sleep(25 * time.Millisecond)
+			_, _ = code.WriteString("modified!")
+			time.Sleep(50 * time.Millisecond)
+			simulateSigterm(executeCmd)
+
+			// then
+			var output string
+			Eventually(outputChan).Should(Receive(&output))
+			Expect(output).To(ContainSubstring("rating.java changed. Restarting process."))
+			Expect(strings.Count(output, "mvn clean install")).To(Equal(1), "Expected build to not be re-run.")
+			Expect(strings.Count(output, "java -jar rating.jar")).To(Equal(2), "Expected process to be restarted.")
+		})
+
+		It("should build again on restart rule change when previous build failed", func() {
+			// given
+			tmpDir := TmpDir(GinkgoT(), "rule-failed-build")
+			code := TmpFile(GinkgoT(), tmpDir+"/watch-test/rating.java", "content")
+			outputChan := make(chan string)
+
+			go shell.ExecuteCommand(outputChan, func() (string, error) {
+				return Run(executeCmd).Passing(
+					"--run", "java -jar rating.jar",
+					"--build", "mvn clean install && exit 3",
+					"--watch",
+					"--dir", tmpDir+"/watch-test",
+					"--rule", "*.java=restart",
+					"--interval", "10",
+				)
+			})()
+
+			// when
+			time.Sleep(25 * time.Millisecond)
+			_, _ = code.WriteString("modified!")
+			time.Sleep(50 * time.Millisecond)
+			simulateSigterm(executeCmd)
+
+			// then
+			var output string
+			Eventually(outputChan).Should(Receive(&output))
+			Expect(output).To(ContainSubstring("rating.java changed. Restarting process."))
+			Expect(output).To(ContainSubstring("build #2 exited with code 3"))
+			Expect(output).ToNot(ContainSubstring("java -jar rating.jar"))
+		})
+
+		It("should run hook without restarting java process", func() {
+			// given
+			tmpDir := TmpDir(GinkgoT(), "rule-hook")
+			config := TmpFile(GinkgoT(), tmpDir+"/watch-test/config/ratings.yaml", "content")
+			static := TmpFile(GinkgoT(), tmpDir+"/watch-test/static/index.html", "content")
+			outputChan := make(chan string)
+
+			go shell.ExecuteCommand(outputChan, func() (string, error) {
+				return Run(executeCmd).Passing(
+					"--run", "java -jar rating.jar",
+					"--watch",
+					"--dir", tmpDir+"/watch-test",
+					"--rule", "config/*.yaml=hook:mvn generate-resources",
+					"--rule", "static/**=none",
+					"--interval", "10",
+				)
+			})()
+
+			// when
+			time.Sleep(25 * time.Millisecond)
+			_, _ = config.WriteString("modified!")
+			_, _ = static.WriteString("modified!")
+			time.Sleep(50 * time.Millisecond)
+			simulateSigterm(executeCmd)
+
+			// then
+			var output string
+			Eventually(outputChan).Should(Receive(&output))
+			Expect(output).To(ContainSubstring("ratings.yaml changed. Running hook."))
+			Expect(output).To(ContainSubstring("index.html changed.\n"))
+			Expect(output).To(ContainSubstring("[mvn generate-resources]"))
+			Expect(output).To(ContainSubstring("hook #1 exited with code 0"))
+			Expect(strings.Count(output, "java -jar rating.jar")).To(Equal(1), "Expected process to be executed once.")
+		})
+
+		It("should fail on unknown rule action", func() {
+			_, err := Run(executeCmd).Passing(
+				"--run", "java -jar rating.jar",
+				"--watch",
+				"--rule", "*.java=deploy",
+			)
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`unknown action "deploy" of rule "*.java=deploy"`))
+		})
+	})
+
+	Context("supervising processes", func() {
+
+		tmpPath := NewTmpPath()
+		BeforeEach(func() {
+			tmpPath.SetPath(path.Dir(shell.MvnBin), path.Dir(shell.JavaBin))
+		})
+
+		AfterEach(tmpPath.Restore)
+
+		It("should keep quoted arguments together", func() {
+			output, err := Run(executeCmd).Passing("--run", "mvn 'clean   install' -Dmessage=\"hello world\"")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output).To(ContainSubstring("[mvn clean   install -Dmessage=hello world]"))
+			Expect(output).To(ContainSubstring("run #1 exited with code 0"))
+		})
+
+		It("should not run the process when build fails", func() {
+			output, err := Run(executeCmd).Passing(
+				"--build", "mvn clean install && exit 3",
+				"--run", "java -jar rating.jar",
+			)
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("build exited with code 3, skipping run"))
+			Expect(output).To(ContainSubstring("build #1 exited with code 3"))
+			Expect(output).ToNot(ContainSubstring("java -jar rating.jar"))
+		})
+
+		It("should give up restarting crashing process after max restarts", func() {
+			output, err := Run(executeCmd).Passing(
+				"--run", "mvn test; exit 2",
+				"--max-restarts", "2",
+				"--restart-backoff", "1ms",
+			)
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("run exited with code 2, giving up after 2 restarts"))
+			Expect(strings.Count(output, "[mvn test]")).To(Equal(3), "Expected process to be restarted twice.")
+			Expect(output).To(ContainSubstring("run #3 exited with code 2"))
+		})
+
+		It("should kill the process group when it does not stop in time", func() {
+			// given
+			outputChan := make(chan string)
+			go shell.ExecuteCommand(outputChan, func() (string, error) {
+				return Run(executeCmd).Passing(
+					// the shell ignores SIGTERM and keeps starting java again
+					"--run", "trap '' TERM; while true; do java -jar rating.jar; done",
+					"--stop-timeout", "100ms",
+				)
+			})()
+
+			// when
+			time.Sleep(50 * time.Millisecond)
+			simulateSigterm(executeCmd)
+
+			// then
+			var output string
+			Eventually(outputChan).Should(Receive(&output))
+			Expect(output).To(ContainSubstring("run #1 killed after stop timeout"))
+		})
+	})
+
 })
 
 func simulateSigterm(cmd *cobra.Command) {
@@ -0,0 +1,185 @@
+package execute
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"emperror.dev/errors"
+	"github.com/fsnotify/fsnotify"
+	ignore "github.com/sabhiram/go-gitignore"
+)
+
+// restart defines how the supervised process reacts on the change. Higher values include the lower ones.
+type restart int
+
+const (
+	noRestart restart = iota
+	restartRun
+	rebuild
+)
+
+const hookActionPrefix = "hook:"
+
+// actions lists actions which can be associated with the watched files, apart from running the hook.
+var actions = map[string]restart{
+	"none":    noRestart,
+	"restart": restartRun,
+	"build":   rebuild,
+}
+
+// rule associates files matching the pattern with the action taken when they change.
+type rule struct {
+	pattern string
+	matcher *ignore.GitIgnore
+	restart restart
+	hook    []string
+}
+
+// parseRule parses rule defined as pattern=action, where pattern follows .gitignore format and action is one of
+// none, restart (without build), build (and restart) or hook:command.
+func parseRule(definition string, useShell bool) (rule, error) {
+	parts := strings.SplitN(definition, "=", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+		return rule{}, errors.Errorf("rule %q is not in the form of pattern=action", definition)
+	}
+	pattern, action := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	matcher, err := ignore.CompileIgnoreLines(pattern)
+	if err != nil {
+		return rule{}, errors.WrapIfWithDetails(err, "failed compiling rule pattern", "pattern", pattern)
+	}
+
+	r := rule{pattern: pattern, matcher: matcher}
+	if strings.HasPrefix(action, hookActionPrefix) {
+		hook, err := parseCommand(strings.TrimPrefix(action, hookActionPrefix), useShell)
+		if err != nil {
+			return rule{}, errors.WrapIfWithDetails(err, "invalid hook", "rule", definition)
+		}
+		r.hook = hook
+
+		return r, nil
+	}
+	restart, found := actions[action]
+	if !found {
+		return rule{}, errors.Errorf("unknown action %q of rule %q, use one of none, restart, build or hook:command", action, definition)
+	}
+	r.restart = restart
+
+	return r, nil
+}
+
+// rules determine actions for changed files, relative to the watched directories. First matching rule wins,
+// files not matching any of them are rebuilt.
+type rules struct {
+	dirs  []string
+	rules []rule
+}
+
+func newRules(dirs []string, definitions []string, useShell bool) (*rules, error) {
+	r := &rules{}
+	for _, dir := range dirs {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, errors.WrapIfWithDetails(err, "failed resolving watched directory", "dir", dir)
+		}
+		r.dirs = append(r.dirs, abs)
+	}
+	for _, definition := range definitions {
+		parsed, err := parseRule(definition, useShell)
+		if err != nil {
+			return nil, err
+		}
+		r.rules = append(r.rules, parsed)
+	}
+
+	return r, nil
+}
+
+func (r *rules) match(path string) rule {
+	relative := r.relative(path)
+	for _, candidate := range r.rules {
+		if candidate.matcher.MatchesPath(relative) {
+			return candidate
+		}
+	}
+
+	return rule{pattern: "*", restart: rebuild}
+}
+
+func (r *rules) relative(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	for _, dir := range r.dirs {
+		if relative, err := filepath.Rel(dir, abs); err == nil && !strings.HasPrefix(relative, "..") {
+			if relative == "." { // watched file itself
+				return filepath.Base(abs)
+			}
+
+			return relative
+		}
+	}
+
+	return path
+}
+
+// changes accumulates actions requested by the watched files until the supervisor handles them.
+type changes struct {
+	sync.Mutex
+	notify  chan struct{}
+	restart restart
+	hooks   [][]string
+}
+
+func newChanges() *changes {
+	return &changes{notify: make(chan struct{}, 1)}
+}
+
+func (c *changes) add(r rule) {
+	c.Lock()
+	if r.restart > c.restart {
+		c.restart = r.restart
+	}
+	if r.hook != nil && !c.hasHook(r.hook) {
+		c.hooks = append(c.hooks, r.hook)
+	}
+	c.Unlock()
+
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (c *changes) hasHook(hook []string) bool {
+	for _, h := range c.hooks {
+		if strings.Join(h, " ") == strings.Join(hook, " ") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// take returns accumulated actions and resets them.
+func (c *changes) take() (restart, [][]string) {
+	c.Lock()
+	defer c.Unlock()
+	r, hooks := c.restart, c.hooks
+	c.restart, c.hooks = noRestart, nil
+
+	return r, hooks
+}
+
+// message describes what happens when the file changes.
+func message(event fsnotify.Event, r rule) string {
+	switch {
+	case r.hook != nil:
+		return event.Name + " changed. Running hook."
+	case r.restart == noRestart:
+		return event.Name + " changed."
+	default:
+		return event.Name + " changed. Restarting process."
+	}
+}
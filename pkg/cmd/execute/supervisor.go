@@ -0,0 +1,280 @@
+package execute
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	gocmd "github.com/go-cmd/cmd"
+	"github.com/google/shlex"
+	"github.com/spf13/cobra"
+
+	"github.com/maistra/istio-workspace/pkg/shell"
+)
+
+const (
+	shellBin = "/bin/sh"
+	// shellMetacharacters indicate that the command relies on the shell, e.g. to pipe or redirect its output,
+	// expand variables or globs, or to chain several commands.
+	shellMetacharacters = "|&;<>()$`*?#"
+	// maxRestartBackoff caps the exponential delay between restarts of the crashing process.
+	maxRestartBackoff = time.Minute
+)
+
+var envAssignment = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
+
+// parseCommand splits the command into the executable and its arguments honouring quotes. Commands relying on the shell,
+// such as pipes, redirects, variable expansion or leading env assignments, are run through `sh -c` instead.
+func parseCommand(command string, useShell bool) ([]string, error) {
+	if strings.TrimSpace(command) == "" {
+		return nil, errors.New("command is empty")
+	}
+	if useShell || strings.ContainsAny(command, shellMetacharacters) {
+		return []string{shellBin, "-c", command}, nil
+	}
+	args, err := shlex.Split(command)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed parsing command", "command", command)
+	}
+	if envAssignment.MatchString(args[0]) {
+		return []string{shellBin, "-c", command}, nil
+	}
+
+	return args, nil
+}
+
+type event int
+
+const (
+	exited event = iota
+	changed
+	stopped
+)
+
+// execution records how a single build or run ended, so that the summary can be printed on exit.
+type execution struct {
+	phase   string
+	attempt int
+	status  gocmd.Status
+	stopped bool
+	killed  bool
+}
+
+func (e execution) String() string {
+	switch {
+	case e.killed:
+		return "killed after stop timeout"
+	case e.stopped:
+		return "stopped"
+	case e.status.Error != nil && e.status.Exit < 0:
+		return "failed: " + e.status.Error.Error()
+	default:
+		return fmt.Sprintf("exited with code %d", e.status.Exit)
+	}
+}
+
+func (e execution) failed() bool {
+	return !e.stopped && (e.status.Error != nil || e.status.Exit != 0)
+}
+
+// supervisor builds and runs the process, restarts it when it crashes and makes sure neither the process
+// nor its children are left behind when it is stopped.
+type supervisor struct {
+	command        *cobra.Command
+	build, run     []string
+	maxRestarts    int
+	restartBackoff time.Duration
+	stopTimeout    time.Duration
+	executions     []execution
+}
+
+func newSupervisor(command *cobra.Command) (*supervisor, error) {
+	useShell, _ := command.Flags().GetBool(ShellFlagName)
+	s := &supervisor{command: command}
+	s.maxRestarts, _ = command.Flags().GetInt(MaxRestartsFlagName)
+	s.restartBackoff, _ = command.Flags().GetDuration(RestartBackoffFlagName)
+	s.stopTimeout, _ = command.Flags().GetDuration(StopTimeoutFlagName)
+
+	skipBuild, _ := command.Flags().GetBool(NoBuildFlagName)
+	if buildCmd := command.Flag(BuildFlagName).Value.String(); buildCmd != "" && !skipBuild {
+		build, err := parseCommand(buildCmd, useShell)
+		if err != nil {
+			return nil, errors.WrapIf(err, "invalid build command")
+		}
+		s.build = build
+	}
+
+	run, err := parseCommand(command.Flag(RunFlagName).Value.String(), useShell)
+	if err != nil {
+		return nil, errors.WrapIf(err, "invalid run command")
+	}
+	s.run = run
+
+	return s, nil
+}
+
+// supervise builds and runs the process until stop is received. Without changes to watch it returns as soon as the
+// process ends, otherwise it waits for the next change to start over.
+func (s *supervisor) supervise(changes *changes, stop <-chan os.Signal) error {
+	next := rebuild
+	for {
+		r, ev, err := s.buildAndRun(next, changes, stop)
+		switch ev {
+		case stopped:
+			return nil
+		case changed:
+			next = r
+		case exited:
+			if changes == nil {
+				return err
+			}
+			if err != nil {
+				logger().Error(err, "waiting for changes to start over")
+			}
+			if next, ev = s.await(nil, changes, restartRun, stop); ev == stopped {
+				return nil
+			}
+			if s.buildFailed() { // there is nothing to restart without successful build
+				next = rebuild
+			}
+		}
+	}
+}
+
+// buildAndRun skips the run when the build fails and restarts the crashing process with exponential backoff.
+// Changes requiring rebuild cancel the build in progress.
+func (s *supervisor) buildAndRun(next restart, changes *changes, stop <-chan os.Signal) (restart, event, error) {
+	if next == rebuild && s.build != nil {
+		build, r, ev := s.execute("build", s.build, changes, rebuild, stop)
+		if ev != exited {
+			return r, ev, nil
+		}
+		if build.failed() {
+			return noRestart, exited, errors.Errorf("build %s, skipping run", build)
+		}
+	}
+
+	for restarts := 0; ; restarts++ {
+		run, r, ev := s.execute("run", s.run, changes, restartRun, stop)
+		if ev != exited || !run.failed() {
+			return r, ev, nil
+		}
+		if s.maxRestarts >= 0 && restarts >= s.maxRestarts {
+			return noRestart, exited, errors.Errorf("run %s, giving up after %d restarts", run, restarts)
+		}
+
+		delay := backoff(s.restartBackoff, restarts)
+		logger().Info(fmt.Sprintf("run %s, restarting in %s", run, delay))
+		if r, ev := s.await(after(delay), changes, restartRun, stop); ev != exited {
+			return r, ev, nil
+		}
+	}
+}
+
+// execute starts the command and waits until it exits, or stops it gracefully when a change requiring at least
+// the given restart or stop is received. Processes it left behind are terminated in either case.
+func (s *supervisor) execute(phase string, args []string, changes *changes, threshold restart, stop <-chan os.Signal) (execution, restart, event) {
+	c := gocmd.NewCmdOptions(shell.StreamOutput, args[0], args[1:]...)
+	c.Env = os.Environ()
+	redirected := shell.RedirectStreams(c, s.command.OutOrStdout(), s.command.OutOrStderr())
+	logger().V(1).Info(fmt.Sprintf("starting %s command", phase),
+		"cmd", c.Name,
+		"args", fmt.Sprint(c.Args),
+	)
+
+	e := execution{phase: phase, attempt: s.attempts(phase) + 1}
+	c.Start()
+	r, ev := s.await(c.Done(), changes, threshold, stop)
+	e.stopped = ev != exited
+	e.killed = shell.Stop(c, s.stopTimeout) && e.stopped
+	e.status = c.Status()
+	<-redirected
+	s.executions = append(s.executions, e)
+
+	return e, r, ev
+}
+
+// await waits until done is closed, stop is received or changes require at least the given restart.
+// Hooks of the changes are run meanwhile.
+func (s *supervisor) await(done <-chan struct{}, changes *changes, threshold restart, stop <-chan os.Signal) (restart, event) {
+	var notify chan struct{}
+	if changes != nil {
+		notify = changes.notify
+	}
+	for {
+		select {
+		case <-done:
+			return noRestart, exited
+		case <-stop:
+			return noRestart, stopped
+		case <-notify:
+			r, hooks := changes.take()
+			for _, hook := range hooks {
+				if _, _, ev := s.execute("hook", hook, nil, noRestart, stop); ev == stopped {
+					return noRestart, stopped
+				}
+			}
+			if r != noRestart && r >= threshold {
+				return r, changed
+			}
+		}
+	}
+}
+
+// buildFailed tells if the most recent build did not succeed.
+func (s *supervisor) buildFailed() bool {
+	for i := len(s.executions) - 1; i >= 0; i-- {
+		if s.executions[i].phase == "build" {
+			return s.executions[i].failed()
+		}
+	}
+
+	return false
+}
+
+func (s *supervisor) attempts(phase string) int {
+	count := 0
+	for _, e := range s.executions {
+		if e.phase == phase {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (s *supervisor) printSummary(out io.Writer) {
+	if len(s.executions) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(out, "Summary of executions:")
+	for _, e := range s.executions {
+		_, _ = fmt.Fprintf(out, "  %s #%d %s\n", e.phase, e.attempt, e)
+	}
+}
+
+func backoff(initial time.Duration, restarts int) time.Duration {
+	delay := initial
+	for i := 0; i < restarts && delay < maxRestartBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxRestartBackoff {
+		return maxRestartBackoff
+	}
+
+	return delay
+}
+
+// after returns channel closed once the delay elapses.
+func after(delay time.Duration) <-chan struct{} {
+	elapsed := make(chan struct{})
+	time.AfterFunc(delay, func() {
+		close(elapsed)
+	})
+
+	return elapsed
+}
@@ -0,0 +1,127 @@
+package install
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/maistra/istio-workspace/pkg/cmd/config"
+	"github.com/maistra/istio-workspace/pkg/log"
+)
+
+var logger = func() logr.Logger {
+	return log.Log.WithValues("type", "install")
+}
+
+const (
+	yamlOutput = "yaml"
+	jsonOutput = "json"
+)
+
+// NewCmd creates instance of "install" Cobra Command with flags and execution logic defined.
+func NewCmd() *cobra.Command {
+	installCmd := &cobra.Command{
+		Use:   "install",
+		Short: "Installs istio-workspace operator to the cluster",
+		Long: "Installs the Session CRD, the RBAC rules and the operator Deployment matching the version of ike.\n\n" +
+			"The image can be overridden using --image flag or IKE_DOCKER_REGISTRY, IKE_DOCKER_REPOSITORY and IKE_IMAGE_TAG environment variables.",
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := config.SyncFullyQualifiedFlags(cmd); err != nil {
+				return errors.Wrap(err, "failed syncing flags")
+			}
+			if output := cmd.Flag("output").Value.String(); output != yamlOutput && output != jsonOutput {
+				return errors.Errorf("unknown output format %s, expected one of [%s %s]", output, jsonOutput, yamlOutput)
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := ToOptions(cmd)
+			if err != nil {
+				return err
+			}
+			objects, err := Render(opts)
+			if err != nil {
+				return err
+			}
+
+			if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+				return printResources(cmd.OutOrStdout(), cmd.Flag("output").Value.String(), objects)
+			}
+
+			c, mapper, err := Clients()
+			if err != nil {
+				return err
+			}
+			if err := Apply(c, mapper, objects); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "istio-workspace operator %s installed in namespace %s\n", opts.Image, opts.Namespace)
+
+			return nil
+		},
+	}
+
+	installCmd.Flags().StringP("namespace", "n", DefaultNamespace, "namespace the operator is installed to")
+	installCmd.Flags().StringSlice("watch-namespaces", []string{}, "namespaces watched by the operator (defaults to all namespaces)")
+	installCmd.Flags().String("image", "", "image of the operator (defaults to the one matching the version of ike)")
+	installCmd.Flags().Bool("dry-run", false, "only prints the resources which would be applied")
+	installCmd.Flags().StringP("output", "o", yamlOutput, fmt.Sprintf("output format of --dry-run, one of [%s %s]", jsonOutput, yamlOutput))
+
+	installCmd.Flags().VisitAll(config.BindFullyQualifiedFlag(installCmd))
+
+	return installCmd
+}
+
+// ToOptions converts install flags to Options.
+func ToOptions(cmd *cobra.Command) (Options, error) {
+	watchNamespaces, err := cmd.Flags().GetStringSlice("watch-namespaces")
+	if err != nil {
+		return Options{}, errors.Wrap(err, "failed reading watch namespaces")
+	}
+	image := cmd.Flag("image").Value.String()
+	if image == "" {
+		image = DefaultImage()
+	}
+
+	return Options{
+		Namespace:       cmd.Flag("namespace").Value.String(),
+		WatchNamespaces: watchNamespaces,
+		Image:           image,
+	}, nil
+}
+
+func printResources(out io.Writer, output string, objects []*unstructured.Unstructured) error {
+	if output == jsonOutput {
+		list := &unstructured.UnstructuredList{Object: map[string]interface{}{"apiVersion": "v1", "kind": "List"}}
+		for _, object := range objects {
+			list.Items = append(list.Items, *object)
+		}
+		b, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed marshaling resources")
+		}
+		_, err = fmt.Fprintln(out, string(b))
+
+		return errors.Wrap(err, "failed printing resources")
+	}
+
+	for _, object := range objects {
+		b, err := yaml.Marshal(object.Object)
+		if err != nil {
+			return errors.Wrap(err, "failed marshaling resources")
+		}
+		if _, err := fmt.Fprintf(out, "---\n%s", b); err != nil {
+			return errors.Wrap(err, "failed printing resources")
+		}
+	}
+
+	return nil
+}
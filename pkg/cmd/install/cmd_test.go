@@ -0,0 +1,165 @@
+package install_test
+
+import (
+	"context"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+
+	. "github.com/maistra/istio-workspace/pkg/cmd"
+	"github.com/maistra/istio-workspace/pkg/cmd/install"
+	. "github.com/maistra/istio-workspace/test"
+	"github.com/maistra/istio-workspace/test/testclient"
+)
+
+var _ = Describe("Usage of ike install command", func() {
+
+	var (
+		installCmd     *cobra.Command
+		client         *fakedynamic.FakeDynamicClient
+		originalClient = install.Clients
+	)
+
+	BeforeEach(func() {
+		installCmd = install.NewCmd()
+		installCmd.SilenceUsage = true
+		installCmd.SilenceErrors = true
+		NewCmd().AddCommand(installCmd)
+
+		client = fakedynamic.NewSimpleDynamicClient(runtime.NewScheme())
+		install.Clients = func() (dynamic.Interface, meta.RESTMapper, error) {
+			return client, testclient.RESTMapper(), nil
+		}
+	})
+
+	AfterEach(func() {
+		install.Clients = originalClient
+	})
+
+	Context("dry run", func() {
+
+		It("should render cluster wide operator", func() {
+			output, err := Run(installCmd).Passing("--dry-run")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output).To(ContainSubstring("kind: CustomResourceDefinition"))
+			Expect(output).To(ContainSubstring("kind: ClusterRole\n"))
+			Expect(output).To(ContainSubstring("kind: ClusterRoleBinding"))
+			Expect(output).To(ContainSubstring("namespace: " + install.DefaultNamespace))
+			Expect(output).To(ContainSubstring("image: quay.io/maistra/istio-workspace:"))
+			Expect(client.Actions()).To(BeEmpty())
+		})
+
+		It("should bind the operator to watched namespaces and its own one only", func() {
+			output, err := Run(installCmd).Passing("--dry-run", "-n", "ike-operator", "--watch-namespaces", "team-a,team-b")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output).ToNot(ContainSubstring("kind: ClusterRoleBinding"))
+			Expect(output).To(MatchRegexp(`kind: RoleBinding\nmetadata:\n(.*\n)*\s+namespace: team-a`))
+			Expect(output).To(MatchRegexp(`kind: RoleBinding\nmetadata:\n(.*\n)*\s+namespace: team-b`))
+			Expect(output).To(ContainSubstring("kind: RoleBinding\nmetadata:\n  name: istio-workspace-operator-manager-rolebinding\n  namespace: ike-operator\n"))
+			Expect(output).To(MatchRegexp(`name: WATCH_NAMESPACE\n\s+value: team-a,team-b`))
+			Expect(output).To(ContainSubstring("namespace: ike-operator"))
+		})
+
+		It("should render operator built from the deploy assets", func() {
+			output, err := Run(installCmd).Passing("--dry-run", "-n", "ike-operator")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output).To(ContainSubstring("kind: ServiceAccount"))
+			Expect(output).To(MatchRegexp(`subjects:\n- kind: ServiceAccount\n\s+name: istio-workspace-operator-istio-workspace\n\s+namespace: ike-operator`))
+			Expect(output).To(ContainSubstring("path: /readyz"))
+			Expect(output).ToNot(ContainSubstring("namespace: " + install.DefaultNamespace))
+		})
+
+		It("should override image using environment variables", func() {
+			registry, tag := os.Getenv("IKE_DOCKER_REGISTRY"), os.Getenv("IKE_IMAGE_TAG")
+			defer func() {
+				_ = os.Setenv("IKE_DOCKER_REGISTRY", registry)
+				_ = os.Setenv("IKE_IMAGE_TAG", tag)
+			}()
+			_ = os.Setenv("IKE_DOCKER_REGISTRY", "registry.local:5000")
+			_ = os.Setenv("IKE_IMAGE_TAG", "latest")
+
+			output, err := Run(installCmd).Passing("--dry-run")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output).To(ContainSubstring("image: registry.local:5000/maistra/istio-workspace:latest"))
+		})
+
+		It("should prefer image flag over environment variables", func() {
+			output, err := Run(installCmd).Passing("--dry-run", "--image", "quay.io/me/ike:dev")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output).To(ContainSubstring("image: quay.io/me/ike:dev"))
+		})
+
+		It("should render resources as json list", func() {
+			output, err := Run(installCmd).Passing("--dry-run", "-o", "json")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output).To(ContainSubstring(`"kind": "List"`))
+			Expect(output).To(ContainSubstring(`"kind": "Deployment"`))
+		})
+
+		It("should fail on unknown output format", func() {
+			_, err := Run(installCmd).Passing("--dry-run", "-o", "wide")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unknown output format wide"))
+		})
+	})
+
+	It("should create all the resources", func() {
+		output, err := Run(installCmd).Passing("--watch-namespaces", "team-a")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(ContainSubstring("installed in namespace " + install.DefaultNamespace))
+		Expect(get(client, "apiextensions.k8s.io", "v1", "customresourcedefinitions", "", "sessions.maistra.io")).ToNot(BeNil())
+		Expect(get(client, "rbac.authorization.k8s.io", "v1", "clusterroles", "", "istio-workspace-operator-istio-workspace")).ToNot(BeNil())
+		Expect(get(client, "rbac.authorization.k8s.io", "v1", "rolebindings", "team-a", "istio-workspace-operator-manager-rolebinding")).ToNot(BeNil())
+		Expect(get(client, "apps", "v1", "deployments", install.DefaultNamespace, "istio-workspace-operator-controller-manager")).ToNot(BeNil())
+	})
+
+	It("should update already installed operator", func() {
+		_, err := Run(install.NewCmd()).Passing("--image", "quay.io/maistra/istio-workspace:v0.1.0")
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = Run(installCmd).Passing("--image", "quay.io/maistra/istio-workspace:v0.2.0")
+
+		Expect(err).ToNot(HaveOccurred())
+		deployment := get(client, "apps", "v1", "deployments", install.DefaultNamespace, "istio-workspace-operator-controller-manager")
+		containers, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+		Expect(containers[0].(map[string]interface{})["image"]).To(Equal("quay.io/maistra/istio-workspace:v0.2.0"))
+	})
+
+	It("should leave existing namespace untouched", func() {
+		existing := &unstructured.Unstructured{}
+		existing.SetAPIVersion("v1")
+		existing.SetKind("Namespace")
+		existing.SetName("shared")
+		client = fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), existing)
+
+		_, err := Run(installCmd).Passing("-n", "shared")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(get(client, "", "v1", "namespaces", "", "shared").GetLabels()).To(BeEmpty())
+	})
+})
+
+func get(c dynamic.Interface, group, version, resource, namespace, name string) *unstructured.Unstructured {
+	gvr := schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
+	object, err := c.Resource(gvr).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	Expect(err).ToNot(HaveOccurred())
+
+	return object
+}
@@ -0,0 +1,328 @@
+package install
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"emperror.dev/errors"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+
+	"github.com/maistra/istio-workspace/pkg/assets"
+	"github.com/maistra/istio-workspace/pkg/cmd/config"
+	"github.com/maistra/istio-workspace/pkg/proxy"
+	"github.com/maistra/istio-workspace/version"
+)
+
+const (
+	// DefaultNamespace is the namespace the operator is installed to if none is specified.
+	DefaultNamespace = "istio-workspace-operator-system"
+
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedByIke   = "ike"
+)
+
+// manifests are rendered in the given order and removed in the reverse one. The operator manifest is built
+// from config/install, so it matches the RBAC rules and the Deployment of the bundle.
+var manifests = []string{
+	"config/crd/bases/maistra.io_sessions.yaml",
+	"config/install/bases/operator.yaml",
+}
+
+// Options defines how the operator is installed.
+type Options struct {
+	Namespace       string   // namespace the operator is installed to
+	WatchNamespaces []string // namespaces watched by the operator, all of them if empty
+	Image           string   // image of the operator
+}
+
+// DefaultImage returns the operator image matching the version of ike. Registry, repository and tag can be overridden
+// using IKE_DOCKER_REGISTRY, IKE_DOCKER_REPOSITORY and IKE_IMAGE_TAG environment variables, the same way as for the image builds.
+func DefaultImage() string {
+	registry := getEnvOrDefault(config.EnvImageRegistry, "quay.io")
+	repository := getEnvOrDefault(config.EnvImageRepository, "maistra")
+	tag := getEnvOrDefault(config.EnvImageTag, version.Version)
+
+	return registry + "/" + repository + "/istio-workspace:" + tag
+}
+
+func getEnvOrDefault(name, defaultValue string) string {
+	if value, found := os.LookupEnv(name); found && value != "" {
+		return value
+	}
+
+	return defaultValue
+}
+
+// Render renders all the resources of the operator from the embedded manifests.
+func Render(opts Options) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	for _, manifest := range manifests {
+		raw, err := assets.Load(manifest)
+		if err != nil {
+			return nil, errors.WrapWithDetails(err, "failed loading manifest", "manifest", manifest)
+		}
+
+		decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(raw), len(raw))
+		for {
+			object := &unstructured.Unstructured{}
+			if err := decoder.Decode(&object.Object); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+
+				return nil, errors.WrapWithDetails(err, "failed decoding manifest", "manifest", manifest)
+			}
+			if len(object.Object) == 0 {
+				continue
+			}
+			customized, err := customize(object, opts)
+			if err != nil {
+				return nil, errors.WrapWithDetails(err, "failed customizing manifest", "manifest", manifest,
+					"kind", object.GetKind(), "name", object.GetName())
+			}
+			objects = append(objects, customized...)
+		}
+	}
+
+	return objects, nil
+}
+
+// customize applies the options to the object built from config/install. Cluster wide binding of the operator
+// is replaced by the bindings in each of the watched namespaces, if there are any.
+func customize(object *unstructured.Unstructured, opts Options) ([]*unstructured.Unstructured, error) {
+	if object.GetNamespace() != "" {
+		object.SetNamespace(opts.Namespace)
+	}
+
+	switch object.GetKind() {
+	case "Namespace":
+		object.SetName(opts.Namespace)
+		labels := object.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[managedByLabel] = managedByIke
+		object.SetLabels(labels)
+	case "RoleBinding":
+		return []*unstructured.Unstructured{object}, setSubjectsNamespace(object, opts.Namespace)
+	case "ClusterRoleBinding":
+		if err := setSubjectsNamespace(object, opts.Namespace); err != nil {
+			return nil, err
+		}
+		if len(opts.WatchNamespaces) == 0 {
+			return []*unstructured.Unstructured{object}, nil
+		}
+		bindings := make([]*unstructured.Unstructured, 0, len(opts.WatchNamespaces)+1)
+		for _, namespace := range boundNamespaces(opts) {
+			binding := object.DeepCopy()
+			binding.SetKind("RoleBinding")
+			binding.SetNamespace(namespace)
+			bindings = append(bindings, binding)
+		}
+
+		return bindings, nil
+	case "Deployment":
+		return []*unstructured.Unstructured{object}, customizeOperator(object, opts)
+	}
+
+	return []*unstructured.Unstructured{object}, nil
+}
+
+// boundNamespaces returns the watched namespaces together with the operator one, in which the operator
+// reads custom strategies and has to be granted the same permissions.
+func boundNamespaces(opts Options) []string {
+	for _, namespace := range opts.WatchNamespaces {
+		if namespace == opts.Namespace {
+			return opts.WatchNamespaces
+		}
+	}
+
+	return append(append([]string{}, opts.WatchNamespaces...), opts.Namespace)
+}
+
+func setSubjectsNamespace(binding *unstructured.Unstructured, namespace string) error {
+	subjects, _, err := unstructured.NestedSlice(binding.Object, "subjects")
+	if err != nil {
+		return errors.Wrap(err, "failed reading subjects")
+	}
+	for _, subject := range subjects {
+		if s, ok := subject.(map[string]interface{}); ok && s["kind"] == "ServiceAccount" {
+			s["namespace"] = namespace
+		}
+	}
+
+	return errors.Wrap(unstructured.SetNestedSlice(binding.Object, subjects, "subjects"), "failed setting subjects")
+}
+
+func customizeOperator(deployment *unstructured.Unstructured, opts Options) error {
+	containers, _, err := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+	if err != nil {
+		return errors.Wrap(err, "failed reading containers")
+	}
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		container["image"] = opts.Image
+		env, _, _ := unstructured.NestedSlice(container, "env")
+		for _, e := range env {
+			if variable, ok := e.(map[string]interface{}); ok && variable["name"] == "WATCH_NAMESPACE" {
+				variable["value"] = strings.Join(opts.WatchNamespaces, ",")
+			}
+		}
+		if err := unstructured.SetNestedSlice(container, env, "env"); err != nil {
+			return errors.Wrap(err, "failed setting env")
+		}
+	}
+
+	return errors.Wrap(unstructured.SetNestedSlice(deployment.Object, containers, "spec", "template", "spec", "containers"),
+		"failed setting containers")
+}
+
+// Clients creates the dynamic client and the mapper of kinds to resources for the current kube config.
+var Clients = func() (dynamic.Interface, meta.RESTMapper, error) {
+	_, restCfg, _, err := proxy.ClusterClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := dynamic.NewForConfig(restCfg)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create dynamic client")
+	}
+	d, err := discovery.NewDiscoveryClientForConfig(restCfg)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create discovery client")
+	}
+
+	return c, restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(d)), nil
+}
+
+// Apply creates the objects or updates them if they already exist. Existing namespace is left untouched.
+func Apply(c dynamic.Interface, mapper meta.RESTMapper, objects []*unstructured.Unstructured) error {
+	for _, object := range objects {
+		resource, err := resourceOf(c, mapper, object)
+		if err != nil {
+			return err
+		}
+		existing, err := resource.Get(context.Background(), object.GetName(), metav1.GetOptions{})
+		switch {
+		case k8sErrors.IsNotFound(err):
+			_, err = resource.Create(context.Background(), object, metav1.CreateOptions{})
+		case err != nil:
+		case object.GetKind() == "Namespace":
+			continue
+		default:
+			object.SetResourceVersion(existing.GetResourceVersion())
+			_, err = resource.Update(context.Background(), object, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			return errors.WrapWithDetails(err, "failed applying", "kind", object.GetKind(), "name", object.GetName())
+		}
+		logger().Info("applied", "kind", object.GetKind(), "name", object.GetName(), "namespace", object.GetNamespace())
+	}
+
+	return nil
+}
+
+// Delete removes the objects in the reverse order, skipping the ones which no longer exist.
+// Namespace is only removed if it has been created by ike.
+func Delete(c dynamic.Interface, mapper meta.RESTMapper, objects []*unstructured.Unstructured) error {
+	for i := len(objects) - 1; i >= 0; i-- {
+		object := objects[i]
+		resource, err := resourceOf(c, mapper, object)
+		if meta.IsNoMatchError(err) {
+			continue // e.g. the CRD has been already removed
+		}
+		if err != nil {
+			return err
+		}
+		if object.GetKind() == "Namespace" {
+			existing, err := resource.Get(context.Background(), object.GetName(), metav1.GetOptions{})
+			if err != nil || existing.GetLabels()[managedByLabel] != managedByIke {
+				continue
+			}
+		}
+		err = resource.Delete(context.Background(), object.GetName(), metav1.DeleteOptions{})
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return errors.WrapWithDetails(err, "failed deleting", "kind", object.GetKind(), "name", object.GetName())
+		}
+		logger().Info("deleted", "kind", object.GetKind(), "name", object.GetName(), "namespace", object.GetNamespace())
+	}
+
+	return nil
+}
+
+func resourceOf(c dynamic.Interface, mapper meta.RESTMapper, object *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	gvk := object.GroupVersionKind()
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, errors.WrapWithDetails(err, "failed finding resource", "kind", gvk.String())
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return c.Resource(mapping.Resource).Namespace(object.GetNamespace()), nil
+	}
+
+	return c.Resource(mapping.Resource), nil
+}
+
+// Installed reconstructs the options of the operator installed in the given namespace, so all the resources
+// created for the watched namespaces can be found again. Defaults are returned if the operator is not found.
+func Installed(c dynamic.Interface, namespace string) (Options, error) {
+	opts := Options{Namespace: namespace}
+	name, err := operatorName()
+	if err != nil {
+		return opts, err
+	}
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	deployment, err := c.Resource(deployments).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if k8sErrors.IsNotFound(err) {
+		return opts, nil
+	}
+	if err != nil {
+		return opts, errors.WrapWithDetails(err, "failed finding operator", "namespace", namespace)
+	}
+
+	containers, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+	for _, container := range containers {
+		env, _, _ := unstructured.NestedSlice(container.(map[string]interface{}), "env")
+		for _, e := range env {
+			variable := e.(map[string]interface{})
+			if variable["name"] != "WATCH_NAMESPACE" {
+				continue
+			}
+			if value, ok := variable["value"].(string); ok && value != "" {
+				opts.WatchNamespaces = strings.Split(value, ",")
+			}
+		}
+	}
+
+	return opts, nil
+}
+
+// operatorName returns the name of the operator Deployment defined by the embedded manifests.
+func operatorName() (string, error) {
+	objects, err := Render(Options{})
+	if err != nil {
+		return "", err
+	}
+	for _, object := range objects {
+		if object.GetKind() == "Deployment" {
+			return object.GetName(), nil
+		}
+	}
+
+	return "", errors.New("no operator deployment in the embedded manifests")
+}
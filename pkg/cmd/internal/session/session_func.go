@@ -1,18 +1,55 @@
 package internal
 
 import (
+	"strings"
+
 	"emperror.dev/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
 	"github.com/maistra/istio-workspace/pkg/internal/session"
-	"github.com/maistra/istio-workspace/pkg/telepresence"
+	"github.com/maistra/istio-workspace/pkg/proxy"
 )
 
 // Sessions creates a Handler for the given session operation
 // session expects that cmd has offline, namespace, route, deployment and session flags defined.
 // otherwise it fails.
 func Sessions(cmd *cobra.Command) (session.State, session.Options, func(), error) {
+	return sessions(cmd, session.CreateOrJoinHandler)
+}
+
+// JoinSessions adds the ref to an existing session, failing if the session does not exist
+// or the ref is already part of it. It expects the same flags as Sessions.
+func JoinSessions(cmd *cobra.Command) (session.State, session.Options, func(), error) {
+	return sessions(cmd, session.JoinHandler)
+}
+
+// DeploymentSessions creates or joins the session for the given deployment instead of the one defined by the deployment flag.
+// The run command is used by the strategies running it in the cloned deployment, such as the one of the sync proxy.
+// Empty sessionName results in a new session with generated name.
+func DeploymentSessions(cmd *cobra.Command, deployment, run, sessionName string) (session.State, session.Options, func(), error) {
+	return sessions(cmd, session.CreateOrJoinHandler, func(options *session.Options) error {
+		options.DeploymentName = deployment
+		options.SessionName = sessionName
+		if _, found := cmd.Annotations[AnnotationStrategy]; found {
+			return nil
+		}
+		if i, _ := cmd.Flags().GetString("image"); i != "" { // ignore error, not a required argument
+			return nil
+		}
+		backend, err := Proxy(cmd.Flags())
+		if err != nil {
+			return err
+		}
+		if backend.Name() == proxy.SyncBackendName {
+			options.StrategyArgs["command"] = run
+		}
+
+		return nil
+	})
+}
+
+func sessions(cmd *cobra.Command, onlineHandler session.Handler, customizations ...func(*session.Options) error) (session.State, session.Options, func(), error) {
 	var sessionHandler session.Handler = session.Offline
 	var client *session.Client
 
@@ -20,9 +57,14 @@ func Sessions(cmd *cobra.Command) (session.State, session.Options, func(), error
 	if err != nil {
 		return session.State{}, options, nil, err
 	}
+	for _, customize := range customizations {
+		if err := customize(&options); err != nil {
+			return session.State{}, options, nil, err
+		}
+	}
 
 	if offline, e := cmd.Flags().GetBool("offline"); e == nil && !offline {
-		sessionHandler = session.CreateOrJoinHandler
+		sessionHandler = onlineHandler
 		c, e2 := session.DefaultClient(options.NamespaceName)
 		if e2 != nil {
 			return session.State{}, options, func() {}, errors.WrapIf(e2, "failed to get default client")
@@ -52,15 +94,32 @@ func RemoveSessions(cmd *cobra.Command) (session.State, func(), error) {
 	return handler, f, nil
 }
 
+// LeaveSessions removes the ref from an existing session, failing if the session does not exist
+// or the ref is not part of it.
+// session expects that cmd has namespace, deployment, session and optionally keep-session flags defined.
+func LeaveSessions(cmd *cobra.Command) error {
+	options, err := ToRemoveOptions(cmd.Flags())
+	if err != nil {
+		return errors.WrapIf(err, "failed to create options")
+	}
+	client, err := session.DefaultClient(options.NamespaceName)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get default client")
+	}
+
+	return session.Leave(options, client)
+}
+
 const (
 	// AnnotationRevert is the name of the command annotation that is used to control the Revert flag.
-	AnnotationRevert     = "revert"
-	telepresenceStrategy = "telepresence"
+	AnnotationRevert = "revert"
+	// AnnotationStrategy is the name of the command annotation defining the strategy used instead of the proxy or image based one.
+	AnnotationStrategy = "strategy"
 )
 
 // ToOptions converts between FlagSet to a Handler Options.
 func ToOptions(annotations map[string]string, flags *pflag.FlagSet) (session.Options, error) {
-	strategy := telepresenceStrategy
+	var strategy string
 	strategyArgs := map[string]string{}
 
 	n, err := flags.GetString("namespace")
@@ -84,16 +143,45 @@ func ToOptions(annotations map[string]string, flags *pflag.FlagSet) (session.Opt
 	}
 
 	i, _ := flags.GetString("image") // ignore error, not a required argument
-	if i != "" {
+	annotatedStrategy, annotated := annotations[AnnotationStrategy]
+	switch {
+	case annotated:
+		strategy = annotatedStrategy
+	case i != "":
 		strategy = "prepared-image"
 		strategyArgs["image"] = i
+	default:
+		backend, e := Proxy(flags)
+		if e != nil {
+			return session.Options{}, e
+		}
+		if strategy, strategyArgs, e = backend.Strategy(); e != nil {
+			return session.Options{}, errors.WrapIf(e, "failed obtaining strategy of the proxy")
+		}
+		if backend.Name() == proxy.SyncBackendName {
+			strategyArgs["command"], _ = flags.GetString("run") // ignore error, not a required argument
+		}
 	}
 
-	if strategy == telepresenceStrategy {
-		if strategyArgs["version"], err = telepresence.GetVersion(); err != nil {
-			return session.Options{}, errors.Wrap(err, "failed obtaining telepresence version")
+	if c, _ := flags.GetString("container"); c != "" { // ignore error, not a required argument
+		strategyArgs["container"] = c
+	}
+
+	vars, _ := flags.GetStringArray("var") // ignore error, not a required argument
+	for _, v := range vars {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return session.Options{}, errors.Errorf("expected strategy variable in format name=value, got %s", v)
 		}
+		strategyArgs[parts[0]] = parts[1]
 	}
+
+	subsetLabel, _ := flags.GetString("subset-label") // ignore error, not a required argument
+
+	if mergePatches, _ := flags.GetStringSlice("merge-patch"); len(mergePatches) > 0 { // ignore error, not a required argument
+		strategy = strings.Join(append([]string{strategy}, mergePatches...), ",")
+	}
+
 	revert := false
 	if val, found := annotations[AnnotationRevert]; found && val == "true" {
 		revert = true
@@ -107,9 +195,24 @@ func ToOptions(annotations map[string]string, flags *pflag.FlagSet) (session.Opt
 		RouteExp:       r,
 		Strategy:       strategy,
 		StrategyArgs:   strategyArgs,
+		SubsetLabel:    subsetLabel,
 	}, nil
 }
 
+// Proxy creates the proxy backend defined by the proxy flag. Defaults to proxy.DefaultBackend if the flag is not defined.
+// The sync flag is a shorthand for the sync backend.
+func Proxy(flags *pflag.FlagSet) (proxy.Backend, error) {
+	if sync, _ := flags.GetBool("sync"); sync { // ignore error, not a required argument
+		return proxy.Lookup(proxy.SyncBackendName)
+	}
+	name, _ := flags.GetString("proxy") // ignore error, not a required argument
+	if name == "" {
+		name = proxy.DefaultBackend
+	}
+
+	return proxy.Lookup(name)
+}
+
 // ToRemoveOptions converts between FlagSet to a Handler Options.
 func ToRemoveOptions(flags *pflag.FlagSet) (session.Options, error) {
 	n, err := flags.GetString("namespace")
@@ -127,9 +230,12 @@ func ToRemoveOptions(flags *pflag.FlagSet) (session.Options, error) {
 		return session.Options{}, errors.Wrap(err, "failed obtaining session flag")
 	}
 
+	keepSession, _ := flags.GetBool("keep-session") // ignore error, not a required argument
+
 	return session.Options{
 		NamespaceName:  n,
 		DeploymentName: d,
 		SessionName:    s,
+		KeepSession:    keepSession,
 	}, nil
 }
@@ -95,6 +95,75 @@ var _ = Describe("Usage of session func", func() {
 			Expect(opts.RouteExp).To(Equal("header:name=value"))
 		})
 
+		It("should convert container to strategy argument if set", func() {
+			Expect(command.Flags().Set("container", "TEST")).ToNot(HaveOccurred())
+			opts, err := internal.ToOptions(command.Annotations, command.Flags())
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(opts.StrategyArgs).To(HaveKeyWithValue("container", "TEST"))
+		})
+
+		It("should convert variables to strategy arguments if set", func() {
+			Expect(command.Flags().Set("var", "env=DEBUG=true,LOG_LEVEL=trace")).ToNot(HaveOccurred())
+			opts, err := internal.ToOptions(command.Annotations, command.Flags())
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(opts.StrategyArgs).To(HaveKeyWithValue("env", "DEBUG=true,LOG_LEVEL=trace"))
+		})
+
+		It("should fail on malformed variable", func() {
+			Expect(command.Flags().Set("var", "env")).ToNot(HaveOccurred())
+			_, err := internal.ToOptions(command.Annotations, command.Flags())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("expected strategy variable in format name=value"))
+		})
+
+		It("should compose strategy with merge patches if set", func() {
+			Expect(command.Flags().Set("merge-patch", "extra-env,with-debug")).ToNot(HaveOccurred())
+			opts, err := internal.ToOptions(command.Annotations, command.Flags())
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(opts.Strategy).To(Equal("telepresence,extra-env,with-debug"))
+			Expect(opts.StrategyArgs).To(HaveKey("version"))
+		})
+
+		It("should use telepresence2 strategy when Telepresence 2 is available", func() {
+			restoreVersion := test.TemporaryEnvVars("TELEPRESENCE_VERSION", "Client: v2.4.5 (api v3)")
+			defer restoreVersion()
+
+			opts, err := internal.ToOptions(command.Annotations, command.Flags())
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(opts.Strategy).To(Equal("telepresence2"))
+		})
+
+		It("should use sync strategy running the command in the clone when sync is enabled", func() {
+			Expect(command.Flags().Set("sync", "true")).ToNot(HaveOccurred())
+			Expect(command.Flags().Set("run", "node server.js")).ToNot(HaveOccurred())
+			opts, err := internal.ToOptions(command.Annotations, command.Flags())
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(opts.Strategy).To(Equal("sync"))
+			Expect(opts.StrategyArgs).To(HaveKeyWithValue("command", "node server.js"))
+		})
+
+		It("should use strategy defined by command annotation", func() {
+			command.Annotations[internal.AnnotationStrategy] = "debug"
+			opts, err := internal.ToOptions(command.Annotations, command.Flags())
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(opts.Strategy).To(Equal("debug"))
+			Expect(opts.StrategyArgs).ToNot(HaveKey("version"))
+		})
+
+		It("should convert subset label if set", func() {
+			Expect(command.Flags().Set("subset-label", "app.kubernetes.io/version")).ToNot(HaveOccurred())
+			opts, err := internal.ToOptions(command.Annotations, command.Flags())
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(opts.SubsetLabel).To(Equal("app.kubernetes.io/version"))
+		})
+
 		It("should set Revert if command is develop", func() {
 			opts, err := internal.ToOptions(command.Annotations, command.Flags())
 			Expect(err).ToNot(HaveOccurred())
@@ -0,0 +1,66 @@
+package join
+
+import (
+	"fmt"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+
+	"github.com/maistra/istio-workspace/pkg/cmd/config"
+	internal "github.com/maistra/istio-workspace/pkg/cmd/internal/session"
+	"github.com/maistra/istio-workspace/pkg/log"
+)
+
+var logger = func() logr.Logger {
+	return log.Log.WithValues("type", "join")
+}
+
+// NewCmd creates instance of "join" Cobra Command with flags and execution logic defined.
+func NewCmd() *cobra.Command {
+	joinCmd := &cobra.Command{
+		Use:   "join",
+		Short: "Joins an existing Session with a ref",
+		Long: "Adds the deployment as a new ref of an existing session. Fails when the session does not exist " +
+			"or the deployment is already part of it.\n\n" +
+			"The ref stays in the session until it is removed using 'ike leave'.",
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return errors.Wrap(config.SyncFullyQualifiedFlags(cmd), "failed syncing flags")
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			state, options, _, err := internal.JoinSessions(cmd)
+			if err != nil {
+				return errors.WrapIf(err, "failed joining session")
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s joined session %s as %s\n", options.DeploymentName, options.SessionName, state.DeploymentName)
+
+			return nil
+		},
+	}
+
+	joinCmd.Flags().StringP("deployment", "d", "", "name of the deployment or deployment config")
+	joinCmd.Flags().StringP("session", "s", "", "name of the existing session to join")
+	joinCmd.Flags().StringP("image", "i", "", "join with the given image "+
+		"or comma-separated list of container=image pairs to replace images of multiple containers (uses the proxy strategy otherwise)")
+	joinCmd.Flags().StringP("route", "", "", "specifies traffic route options in the format of type:name=value. "+
+		"Defaults to X-Workspace-Route header with current session name value")
+	joinCmd.Flags().StringP("namespace", "n", "", "target namespace to develop against "+
+		"(defaults to default for the current context)")
+	joinCmd.Flags().String("container", "", "name of the container to target in multi-container pods "+
+		"(defaults to the one annotated with kubectl.kubernetes.io/default-container, named after the deployment or the first non istio-proxy one)")
+	joinCmd.Flags().StringSlice("merge-patch", []string{}, "strategic merge patch strategies applied to the cloned deployment "+
+		"on top of the strategy in the given order, e.g. extra-env (see ike strategy list)")
+	joinCmd.Flags().StringArray("var", []string{}, "strategy variable in the form of name=value, can be repeated")
+	joinCmd.Flags().Bool("offline", false, "avoid calling external sources")
+	if err := joinCmd.Flags().MarkHidden("offline"); err != nil {
+		logger().Error(err, "failed while trying to hide a flag")
+	}
+
+	joinCmd.Flags().VisitAll(config.BindFullyQualifiedFlag(joinCmd))
+
+	_ = joinCmd.MarkFlagRequired("deployment")
+	_ = joinCmd.MarkFlagRequired("session")
+
+	return joinCmd
+}
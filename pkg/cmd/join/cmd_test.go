@@ -0,0 +1,49 @@
+package join_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	. "github.com/maistra/istio-workspace/pkg/cmd"
+	"github.com/maistra/istio-workspace/pkg/cmd/join"
+	. "github.com/maistra/istio-workspace/test"
+)
+
+var _ = Describe("Usage of ike join command", func() {
+
+	var joinCmd *cobra.Command
+
+	BeforeEach(func() {
+		joinCmd = join.NewCmd()
+		joinCmd.SilenceUsage = true
+		joinCmd.SilenceErrors = true
+		NewCmd().AddCommand(joinCmd)
+	})
+
+	Describe("input validation", func() {
+
+		It("should fail when deployment is not specified", func() {
+			_, err := ValidateArgumentsOf(joinCmd).Passing("-s", "x")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(And(ContainSubstring("required flag(s)"), ContainSubstring("deployment")))
+		})
+
+		It("should fail when session is not specified", func() {
+			_, err := ValidateArgumentsOf(joinCmd).Passing("-d", "x")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(And(ContainSubstring("required flag(s)"), ContainSubstring("session")))
+		})
+
+	})
+
+	It("should join with the given image", func() {
+		output, err := Run(joinCmd).Passing("-d", "ratings-v1", "-s", "feature-x", "-i", "quay.io/ratings:dev", "--offline")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(ContainSubstring("ratings-v1 joined session feature-x as ratings-v1"))
+	})
+
+})
@@ -0,0 +1,47 @@
+package leave
+
+import (
+	"fmt"
+
+	"emperror.dev/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/maistra/istio-workspace/pkg/cmd/config"
+	internal "github.com/maistra/istio-workspace/pkg/cmd/internal/session"
+)
+
+// NewCmd creates instance of "leave" Cobra Command with flags and execution logic defined.
+func NewCmd() *cobra.Command {
+	leaveCmd := &cobra.Command{
+		Use:   "leave",
+		Short: "Removes a ref from an existing Session",
+		Long: "Removes the deployment from an existing session. Fails when the session does not exist " +
+			"or the deployment is not part of it.\n\n" +
+			"The session is deleted when its last ref leaves, unless --keep-session is used.",
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return errors.Wrap(config.SyncFullyQualifiedFlags(cmd), "failed syncing flags")
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := internal.LeaveSessions(cmd); err != nil {
+				return errors.WrapIf(err, "failed leaving session")
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s left session %s\n", cmd.Flag("deployment").Value.String(), cmd.Flag("session").Value.String())
+
+			return nil
+		},
+	}
+
+	leaveCmd.Flags().StringP("deployment", "d", "", "name of the deployment or deployment config")
+	leaveCmd.Flags().StringP("session", "s", "", "name of the session to leave")
+	leaveCmd.Flags().StringP("namespace", "n", "", "target namespace to develop against "+
+		"(defaults to default for the current context)")
+	leaveCmd.Flags().Bool("keep-session", false, "keep the session even if no refs are left in it")
+
+	leaveCmd.Flags().VisitAll(config.BindFullyQualifiedFlag(leaveCmd))
+
+	_ = leaveCmd.MarkFlagRequired("deployment")
+	_ = leaveCmd.MarkFlagRequired("session")
+
+	return leaveCmd
+}
@@ -0,0 +1,42 @@
+package leave_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	. "github.com/maistra/istio-workspace/pkg/cmd"
+	"github.com/maistra/istio-workspace/pkg/cmd/leave"
+	. "github.com/maistra/istio-workspace/test"
+)
+
+var _ = Describe("Usage of ike leave command", func() {
+
+	var leaveCmd *cobra.Command
+
+	BeforeEach(func() {
+		leaveCmd = leave.NewCmd()
+		leaveCmd.SilenceUsage = true
+		leaveCmd.SilenceErrors = true
+		NewCmd().AddCommand(leaveCmd)
+	})
+
+	Describe("input validation", func() {
+
+		It("should fail when deployment is not specified", func() {
+			_, err := ValidateArgumentsOf(leaveCmd).Passing("-s", "x", "--keep-session")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(And(ContainSubstring("required flag(s)"), ContainSubstring("deployment")))
+		})
+
+		It("should fail when session is not specified", func() {
+			_, err := ValidateArgumentsOf(leaveCmd).Passing("-d", "x")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(And(ContainSubstring("required flag(s)"), ContainSubstring("session")))
+		})
+
+	})
+
+})
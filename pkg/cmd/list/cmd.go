@@ -0,0 +1,71 @@
+package list
+
+import (
+	"sort"
+
+	"emperror.dev/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/maistra/istio-workspace/pkg/cmd/config"
+	"github.com/maistra/istio-workspace/pkg/cmd/output"
+	"github.com/maistra/istio-workspace/pkg/internal/session"
+)
+
+// SessionClient creates the client used to list the sessions of the given namespace.
+var SessionClient = session.DefaultClient
+
+// NewCmd creates instance of "list" Cobra Command with flags and execution logic defined.
+func NewCmd() *cobra.Command {
+	listCmd := &cobra.Command{
+		Use:          "list",
+		Aliases:      []string{"ls"},
+		Short:        "Lists Sessions",
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := config.SyncFullyQualifiedFlags(cmd); err != nil {
+				return errors.Wrap(err, "failed syncing flags")
+			}
+			_, err := lookupPrinter(cmd.Flag("output").Value.String())
+
+			return err
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			allNamespaces, _ := cmd.Flags().GetBool("all-namespaces") // ignore error, should only occur if flag does not exist
+			c, err := SessionClient(cmd.Flag("namespace").Value.String())
+			if err != nil {
+				return errors.WrapIf(err, "failed to get default client")
+			}
+			sessions, err := c.List(allNamespaces)
+			if err != nil {
+				return err
+			}
+			sort.Slice(sessions.Items, func(i, j int) bool {
+				if sessions.Items[i].Namespace != sessions.Items[j].Namespace {
+					return sessions.Items[i].Namespace < sessions.Items[j].Namespace
+				}
+
+				return sessions.Items[i].Name < sessions.Items[j].Name
+			})
+
+			printSessions, err := lookupPrinter(cmd.Flag("output").Value.String())
+			if err != nil {
+				return err
+			}
+
+			return printSessions(cmd.OutOrStdout(), sessions, printOptions{
+				allNamespaces: allNamespaces,
+				template:      cmd.Flag("template").Value.String(),
+			})
+		},
+	}
+
+	listCmd.Flags().StringP("namespace", "n", "", "namespace to list sessions of (defaults to default for the current context)")
+	listCmd.Flags().BoolP("all-namespaces", "A", false, "list sessions of all namespaces")
+	output.AddFlag(listCmd, tableOutput, tableOutput, wideOutput, templateOutput)
+	listCmd.Flags().String("template", "", "Go template applied to the list of sessions when using -o template, "+
+		"e.g. '{{ range .items }}{{ .metadata.name }} {{ end }}' (same as -o go-template=TEMPLATE)")
+
+	listCmd.Flags().VisitAll(config.BindFullyQualifiedFlag(listCmd))
+
+	return listCmd
+}
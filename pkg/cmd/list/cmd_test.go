@@ -0,0 +1,143 @@
+package list_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	istiov1alpha1 "github.com/maistra/istio-workspace/api/maistra/v1alpha1"
+	"github.com/maistra/istio-workspace/pkg/client/clientset/versioned/fake"
+	. "github.com/maistra/istio-workspace/pkg/cmd"
+	"github.com/maistra/istio-workspace/pkg/cmd/list"
+	"github.com/maistra/istio-workspace/pkg/internal/session"
+	. "github.com/maistra/istio-workspace/test"
+)
+
+var _ = Describe("Usage of ike list command", func() {
+
+	var (
+		listCmd        *cobra.Command
+		originalClient = list.SessionClient
+	)
+
+	BeforeEach(func() {
+		listCmd = list.NewCmd()
+		listCmd.SilenceUsage = true
+		listCmd.SilenceErrors = true
+		NewCmd().AddCommand(listCmd)
+
+		c := fake.NewSimpleClientset(
+			testSession("test", "feature-x", "alice", "ratings-v1", "header:x-workspace-route=feature-x"),
+			testSession("test", "bugfix-y", "bob", "reviews-v2", "header:x-workspace-route=bugfix-y"),
+			testSession("other", "feature-z", "", "details-v1", ""),
+		)
+		list.SessionClient = func(namespace string) (*session.Client, error) {
+			if namespace == "" {
+				namespace = "test"
+			}
+
+			return session.NewClient(c, namespace)
+		}
+	})
+
+	AfterEach(func() {
+		list.SessionClient = originalClient
+	})
+
+	It("should list sessions of the current namespace sorted by name", func() {
+		output, err := Run(listCmd).Passing()
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(MatchRegexp(`NAME\s+REFS\s+STRATEGIES\s+ROUTE\s+HOSTS\s+OWNER\s+AGE`))
+		Expect(output).To(MatchRegexp(`bugfix-y\s+reviews-v2\s+telepresence\s+header:x-workspace-route=bugfix-y\s+<none>\s+bob\s+5h`))
+		Expect(output).To(MatchRegexp(`(?s)bugfix-y.*feature-x`))
+		Expect(output).ToNot(ContainSubstring("feature-z"))
+	})
+
+	It("should list sessions of all namespaces using alias", func() {
+		output, err := Run(listCmd).Passing("-A")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(MatchRegexp(`NAMESPACE\s+NAME`))
+		Expect(output).To(MatchRegexp(`other\s+feature-z\s+details-v1\s+telepresence\s+<none>\s+<none>\s+<none>`))
+	})
+
+	It("should show state and subset label in wide output", func() {
+		output, err := Run(listCmd).Passing("-o", "wide")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(MatchRegexp(`AGE\s+STATE\s+SUBSET LABEL`))
+		Expect(output).To(MatchRegexp(`feature-x.*Success\s+version`))
+	})
+
+	It("should print sessions as yaml", func() {
+		output, err := Run(listCmd).Passing("-o", "yaml", "-n", "other")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(ContainSubstring("kind: SessionList"))
+		Expect(output).To(ContainSubstring("name: feature-z"))
+	})
+
+	It("should print session names using deprecated template format", func() {
+		output, err := Run(listCmd).Passing("-o", "template", "--template", "{{ range .items }}{{ .metadata.name }} {{ end }}")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(Equal("bugfix-y feature-x "))
+	})
+
+	It("should print kind qualified session names", func() {
+		output, err := Run(listCmd).Passing("-o", "name")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(Equal("session.maistra.io/bugfix-y\nsession.maistra.io/feature-x\n"))
+	})
+
+	It("should print sessions using jsonpath", func() {
+		output, err := Run(listCmd).Passing("-o", "jsonpath={.items[*].status._refNames[0]}")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(Equal("reviews-v2 ratings-v1"))
+	})
+
+	It("should print sessions using go-template", func() {
+		output, err := Run(listCmd).Passing("-o", "go-template={{ range .items }}{{ .metadata.namespace }}/{{ .metadata.name }} {{ end }}")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(Equal("test/bugfix-y test/feature-x "))
+	})
+
+	It("should fail on unknown output format", func() {
+		_, err := Run(listCmd).Passing("-o", "xml")
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("unknown output format xml, expected one of [go-template=... json jsonpath=... name table template wide yaml]"))
+	})
+})
+
+func testSession(namespace, name, owner, ref, route string) *istiov1alpha1.Session {
+	state := "Success"
+	s := &istiov1alpha1.Session{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-5 * time.Hour)),
+		},
+		Spec: istiov1alpha1.SessionSpec{
+			SubsetLabel: "version",
+		},
+		Status: istiov1alpha1.SessionStatus{
+			State:           &state,
+			RouteExpression: route,
+			RefNames:        []string{ref},
+			Strategies:      []string{"telepresence"},
+		},
+	}
+	if owner != "" {
+		s.Annotations = map[string]string{session.OwnerAnnotation: owner}
+	}
+
+	return s
+}
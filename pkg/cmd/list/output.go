@@ -0,0 +1,146 @@
+package list
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"emperror.dev/errors"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	istiov1alpha1 "github.com/maistra/istio-workspace/api/maistra/v1alpha1"
+	"github.com/maistra/istio-workspace/pkg/cmd/output"
+	"github.com/maistra/istio-workspace/pkg/internal/session"
+)
+
+const (
+	tableOutput    = "table"
+	wideOutput     = "wide"
+	templateOutput = "template"
+
+	none = "<none>"
+)
+
+type printOptions struct {
+	allNamespaces bool
+	template      string
+}
+
+type printer func(out io.Writer, sessions *istiov1alpha1.SessionList, opts printOptions) error
+
+// printers holds the human readable formats specific to sessions, the structured ones are handled by output package.
+var printers = map[string]printer{
+	tableOutput:    printTable(false),
+	wideOutput:     printTable(true),
+	templateOutput: printTemplate,
+}
+
+// lookupPrinter returns the printer of the format, either the one specific to sessions or the structured one.
+func lookupPrinter(format string) (printer, error) {
+	if p, found := printers[format]; found {
+		return p, nil
+	}
+	p, err := output.NewPrinter(format)
+	if err != nil {
+		return nil, errors.Errorf("unknown output format %s, expected one of %v", format, outputFormats())
+	}
+
+	return func(out io.Writer, sessions *istiov1alpha1.SessionList, _ printOptions) error {
+		return p(out, output.SessionList(sessions))
+	}, nil
+}
+
+func outputFormats() []string {
+	formats := output.Formats()
+	for format := range printers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+
+	return formats
+}
+
+func printTable(wide bool) printer {
+	return func(out io.Writer, sessions *istiov1alpha1.SessionList, opts printOptions) error {
+		if len(sessions.Items) == 0 {
+			_, err := fmt.Fprintln(out, "No sessions found.")
+
+			return errors.Wrap(err, "failed printing sessions")
+		}
+
+		columns := []string{"NAME", "REFS", "STRATEGIES", "ROUTE", "HOSTS", "OWNER", "AGE"}
+		if opts.allNamespaces {
+			columns = append([]string{"NAMESPACE"}, columns...)
+		}
+		if wide {
+			columns = append(columns, "STATE", "SUBSET LABEL")
+		}
+
+		w := tabwriter.NewWriter(out, 0, 8, 3, ' ', 0)
+		_, _ = fmt.Fprintln(w, strings.Join(columns, "\t"))
+		for i := range sessions.Items {
+			s := &sessions.Items[i]
+			row := []string{
+				s.Name,
+				join(s.Status.RefNames),
+				join(s.Status.Strategies),
+				orNone(s.Status.RouteExpression),
+				join(s.Status.Hosts),
+				orNone(s.Annotations[session.OwnerAnnotation]),
+				age(s.CreationTimestamp.Time),
+			}
+			if opts.allNamespaces {
+				row = append([]string{s.Namespace}, row...)
+			}
+			if wide {
+				state := none
+				if s.Status.State != nil {
+					state = *s.Status.State
+				}
+				row = append(row, state, orNone(s.Spec.SubsetLabel))
+			}
+			_, _ = fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+
+		return errors.Wrap(w.Flush(), "failed printing sessions")
+	}
+}
+
+// printTemplate executes the template on the generic representation of the list, so fields are referred to
+// by their json names, e.g. .metadata.name, the same way as kubectl does.
+//
+// Deprecated form of -o go-template=TEMPLATE kept for compatibility.
+func printTemplate(out io.Writer, sessions *istiov1alpha1.SessionList, opts printOptions) error {
+	if opts.template == "" {
+		return errors.New("template has to be defined when using template output")
+	}
+
+	return output.Print(out, output.GoTemplate+"="+opts.template, output.SessionList(sessions))
+}
+
+func join(values []string) string {
+	if len(values) == 0 {
+		return none
+	}
+
+	return strings.Join(values, ",")
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return none
+	}
+
+	return value
+}
+
+func age(created time.Time) string {
+	if created.IsZero() {
+		return "<unknown>"
+	}
+
+	return duration.HumanDuration(time.Since(created))
+}
@@ -0,0 +1,213 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"emperror.dev/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+
+	istiov1alpha1 "github.com/maistra/istio-workspace/api/maistra/v1alpha1"
+)
+
+// Structured output formats shared by the commands, following the ones of kubectl.
+const (
+	JSON       = "json"
+	YAML       = "yaml"
+	Name       = "name"
+	JSONPath   = "jsonpath"
+	GoTemplate = "go-template"
+)
+
+// Printer writes the object in the chosen format.
+type Printer func(out io.Writer, obj runtime.Object) error
+
+var printers = map[string]Printer{
+	JSON: printJSON,
+	YAML: printYAML,
+	Name: printName,
+}
+
+var templatePrinters = map[string]func(template string) (Printer, error){
+	JSONPath:   jsonPathPrinter,
+	GoTemplate: goTemplatePrinter,
+}
+
+// Formats returns all the structured output formats, the template based ones in the form of format=TEMPLATE.
+func Formats() []string {
+	formats := make([]string, 0, len(printers)+len(templatePrinters))
+	for format := range printers {
+		formats = append(formats, format)
+	}
+	for format := range templatePrinters {
+		formats = append(formats, format+"=...")
+	}
+	sort.Strings(formats)
+
+	return formats
+}
+
+// NewPrinter creates the printer for the given format, e.g. json or jsonpath={.status.hosts}.
+func NewPrinter(format string) (Printer, error) {
+	if p, found := printers[format]; found {
+		return p, nil
+	}
+	if i := strings.Index(format, "="); i > 0 {
+		if create, found := templatePrinters[format[:i]]; found {
+			return create(format[i+1:])
+		}
+	}
+
+	return nil, errors.Errorf("unknown output format %s, expected one of %v", format, Formats())
+}
+
+// AddFlag registers -o/--output flag with the structured formats and the additional ones of the command.
+// Logs are limited to errors when the flag is used, so the output can be consumed by other tools.
+func AddFlag(cmd *cobra.Command, defaultFormat string, additionalFormats ...string) {
+	formats := append(append([]string{}, additionalFormats...), Formats()...)
+	sort.Strings(formats)
+	cmd.Flags().StringP("output", "o", defaultFormat, fmt.Sprintf("output format, one of %v", formats))
+	outputFlag := cmd.Flag("output")
+	if outputFlag.Annotations == nil {
+		outputFlag.Annotations = map[string][]string{}
+	}
+	outputFlag.Annotations["silent"] = []string{"true"}
+}
+
+// Print writes the object using the format, which has to be one of the structured ones.
+func Print(out io.Writer, format string, obj runtime.Object) error {
+	p, err := NewPrinter(format)
+	if err != nil {
+		return err
+	}
+
+	return p(out, obj)
+}
+
+func printJSON(out io.Writer, obj runtime.Object) error {
+	b, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed marshaling object")
+	}
+	_, err = fmt.Fprintln(out, string(b))
+
+	return errors.Wrap(err, "failed printing object")
+}
+
+func printYAML(out io.Writer, obj runtime.Object) error {
+	b, err := yaml.Marshal(obj)
+	if err != nil {
+		return errors.Wrap(err, "failed marshaling object")
+	}
+	_, err = out.Write(b)
+
+	return errors.Wrap(err, "failed printing object")
+}
+
+// printName prints kind qualified names the same way as kubectl does, e.g. session.maistra.io/feature-x.
+func printName(out io.Writer, obj runtime.Object) error {
+	objects := []runtime.Object{obj}
+	if meta.IsListType(obj) {
+		items, err := meta.ExtractList(obj)
+		if err != nil {
+			return errors.Wrap(err, "failed extracting list items")
+		}
+		objects = items
+	}
+	for _, o := range objects {
+		accessor, err := meta.Accessor(o)
+		if err != nil {
+			return errors.Wrap(err, "failed accessing object metadata")
+		}
+		gvk := o.GetObjectKind().GroupVersionKind()
+		resource := strings.ToLower(gvk.Kind)
+		if gvk.Group != "" {
+			resource += "." + gvk.Group
+		}
+		if _, err := fmt.Fprintf(out, "%s/%s\n", resource, accessor.GetName()); err != nil {
+			return errors.Wrap(err, "failed printing object")
+		}
+	}
+
+	return nil
+}
+
+func jsonPathPrinter(tmpl string) (Printer, error) {
+	if !strings.HasPrefix(tmpl, "{") {
+		tmpl = "{" + tmpl + "}"
+	}
+	jp := jsonpath.New("output").AllowMissingKeys(true)
+	if err := jp.Parse(tmpl); err != nil {
+		return nil, errors.Wrap(err, "failed parsing jsonpath")
+	}
+
+	return func(out io.Writer, obj runtime.Object) error {
+		data, err := generic(obj)
+		if err != nil {
+			return err
+		}
+
+		return errors.Wrap(jp.Execute(out, data), "failed executing jsonpath")
+	}, nil
+}
+
+// goTemplatePrinter executes the template on the generic representation of the object, so fields are referred to
+// by their json names, e.g. .metadata.name, the same way as kubectl does.
+func goTemplatePrinter(tmpl string) (Printer, error) {
+	t, err := template.New("output").Parse(tmpl)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed parsing template")
+	}
+
+	return func(out io.Writer, obj runtime.Object) error {
+		data, err := generic(obj)
+		if err != nil {
+			return err
+		}
+
+		return errors.Wrap(t.Execute(out, data), "failed executing template")
+	}, nil
+}
+
+func generic(obj runtime.Object) (map[string]interface{}, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshaling object")
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling object")
+	}
+
+	return data, nil
+}
+
+// Session fills the type information which is not returned by the API server.
+func Session(s *istiov1alpha1.Session) *istiov1alpha1.Session {
+	c := s.DeepCopy()
+	c.APIVersion = istiov1alpha1.SchemeGroupVersion.String()
+	c.Kind = "Session"
+
+	return c
+}
+
+// SessionList fills the type information which is not returned by the API server for the list and its items.
+func SessionList(sessions *istiov1alpha1.SessionList) *istiov1alpha1.SessionList {
+	list := sessions.DeepCopy()
+	list.APIVersion = istiov1alpha1.SchemeGroupVersion.String()
+	list.Kind = "SessionList"
+	for i := range list.Items {
+		list.Items[i].APIVersion = istiov1alpha1.SchemeGroupVersion.String()
+		list.Items[i].Kind = "Session"
+	}
+
+	return list
+}
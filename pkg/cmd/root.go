@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -12,10 +13,10 @@ import (
 	"github.com/spf13/viper"
 	"go.uber.org/zap/zapcore"
 
-	"github.com/maistra/istio-workspace/pkg/cmd/completion"
 	"github.com/maistra/istio-workspace/pkg/cmd/config"
 	"github.com/maistra/istio-workspace/pkg/cmd/format"
 	"github.com/maistra/istio-workspace/pkg/cmd/version"
+	"github.com/maistra/istio-workspace/pkg/kubeconfig"
 	"github.com/maistra/istio-workspace/pkg/log"
 	v "github.com/maistra/istio-workspace/version"
 )
@@ -34,7 +35,6 @@ func NewCmd() *cobra.Command {
 		Use:           "ike",
 		Short: "ike lets you safely develop and test on production without a sweat!\n\n" +
 			"For detailed documentation please visit https://istio-workspace-docs.netlify.com/\n\n",
-		BashCompletionFunction: completion.BashCompletionFunc,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			cmd.Flags().VisitAll(func(flag *pflag.Flag) {
 				if flag.Changed && strings.Join(flag.Annotations["silent"], "") == "true" {
@@ -54,7 +54,21 @@ func NewCmd() *cobra.Command {
 				}()
 			}
 
-			return errors.Wrap(config.SetupConfigSources(loadConfigFileName(cmd)), "failed setting config sources")
+			if err := config.SetupConfigSources(loadConfigFileName(cmd)); err != nil {
+				return errors.Wrap(err, "failed setting config sources")
+			}
+
+			if err := config.ActivateProfile(loadProfileName(cmd)); err != nil {
+				return errors.Wrap(err, "failed activating profile")
+			}
+
+			kubeconfig.Select(kubeconfig.Selection{
+				Kubeconfig: loadGlobalFlag(cmd, "kubeconfig"),
+				Context:    loadGlobalFlag(cmd, "context"),
+				Cluster:    loadGlobalFlag(cmd, "cluster"),
+			})
+
+			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			shouldPrintVersion, _ := cmd.Flags().GetBool("version")
@@ -87,6 +101,10 @@ func NewCmd() *cobra.Command {
 	rootCmd.PersistentFlags().
 		StringVarP(&configFile, "config", "c", ".ike.config.yaml",
 			fmt.Sprintf("config file (supported formats: %s)", strings.Join(config.SupportedExtensions(), ", ")))
+	rootCmd.PersistentFlags().String("profile", "", "profile of the config file to use on top of its top level settings")
+	rootCmd.PersistentFlags().String("kubeconfig", "", "path to the kube config file (defaults to KUBECONFIG env variable or ~/.kube/config)")
+	rootCmd.PersistentFlags().String("context", "", "name of the kube config context to use (defaults to the current one)")
+	rootCmd.PersistentFlags().String("cluster", "", "name of the kube config cluster to use (defaults to the one of the context)")
 	rootCmd.Flags().Bool("version", false, "prints the version number of ike cli")
 	rootCmd.PersistentFlags().String("help-format", "standard", "prints help in asciidoc table")
 	if err := rootCmd.PersistentFlags().MarkHidden("help-format"); err != nil {
@@ -114,3 +132,27 @@ func loadConfigFileName(cmd *cobra.Command) (configFileName string, defaultConfi
 
 	return
 }
+
+// loadProfileName returns the profile set through the flag, IKE_PROFILE env variable or the profile key of the config file.
+func loadProfileName(cmd *cobra.Command) string {
+	profileFlag := cmd.Flag("profile")
+	if profileFlag.Changed {
+		return profileFlag.Value.String()
+	}
+
+	return viper.GetString("profile")
+}
+
+// loadGlobalFlag returns the value of the global flag set on the command line, through IKE_ prefixed env variable
+// or the top level key of the config file.
+func loadGlobalFlag(cmd *cobra.Command, name string) string {
+	if flag := cmd.Root().PersistentFlags().Lookup(name); flag != nil && flag.Changed {
+		return flag.Value.String()
+	}
+	if value := os.Getenv("IKE_" + strings.ToUpper(name)); value != "" {
+		return value
+	}
+	value, _ := config.Lookup(name)
+
+	return value
+}
@@ -0,0 +1,147 @@
+package status
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	istiov1alpha1 "github.com/maistra/istio-workspace/api/maistra/v1alpha1"
+	"github.com/maistra/istio-workspace/pkg/cmd/completion"
+	"github.com/maistra/istio-workspace/pkg/cmd/config"
+	"github.com/maistra/istio-workspace/pkg/cmd/output"
+	"github.com/maistra/istio-workspace/pkg/internal/session"
+	"github.com/maistra/istio-workspace/pkg/log"
+)
+
+var logger = func() logr.Logger {
+	return log.Log.WithValues("type", "status")
+}
+
+// SessionClient creates the client used to fetch the session from the given namespace.
+var SessionClient = session.DefaultClient
+
+// NewCmd creates instance of "status" Cobra Command with flags and execution logic defined.
+func NewCmd() *cobra.Command {
+	statusCmd := &cobra.Command{
+		Use:          "status [SESSION]",
+		Aliases:      []string{"describe"},
+		Short:        "Shows the status of a Session",
+		Long:         "Shows the status of a Session: its refs, located targets, resources manipulated by each ref and exposed hosts.",
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			return completion.Sessions(cmd, args, toComplete)
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := config.SyncFullyQualifiedFlags(cmd); err != nil {
+				return errors.Wrap(err, "failed syncing flags")
+			}
+			if format := cmd.Flag("output").Value.String(); format != "" {
+				_, err := output.NewPrinter(format)
+
+				return err
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := cmd.Flag("session").Value.String()
+			if len(args) > 0 {
+				name = args[0]
+			}
+			if name == "" {
+				return errors.New("session has to be defined either using --session flag or as an argument")
+			}
+			c, err := SessionClient(cmd.Flag("namespace").Value.String())
+			if err != nil {
+				return errors.WrapIf(err, "failed to get default client")
+			}
+			format := cmd.Flag("output").Value.String()
+
+			if watch, _ := cmd.Flags().GetBool("watch"); !watch { // ignore error, should only occur if flag does not exist
+				s, err := c.Get(name)
+				if err != nil {
+					return err
+				}
+
+				return printSession(cmd.OutOrStdout(), format, s)
+			}
+
+			interval, _ := cmd.Flags().GetDuration("watch-interval") // ignore error, should only occur if flag does not exist
+			var last string
+			err = wait.PollImmediateInfinite(interval, func() (bool, error) {
+				s, err := c.Get(name)
+				if err != nil {
+					return false, err
+				}
+				var buf bytes.Buffer
+				if err := printSession(&buf, format, s); err != nil {
+					return false, err
+				}
+				if format != "" && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+					buf.WriteString("\n")
+				}
+				if current := buf.String(); current != last {
+					if last != "" {
+						_, _ = cmd.OutOrStdout().Write([]byte(separator(format)))
+					}
+					_, _ = cmd.OutOrStdout().Write(buf.Bytes())
+					last = current
+				}
+				if refs := Failed(s); len(refs) > 0 {
+					return false, errors.Errorf("refs %s have failed resources", strings.Join(refs, ", "))
+				}
+
+				return Ready(s), nil
+			})
+
+			return errors.WrapIf(err, "failed watching session")
+		},
+	}
+
+	statusCmd.Flags().StringP("session", "s", "", "name of the session")
+	statusCmd.Flags().StringP("namespace", "n", "", "namespace of the session (defaults to default for the current context)")
+	output.AddFlag(statusCmd, "")
+	statusCmd.Flags().BoolP("watch", "w", false, "refresh the status whenever it changes until all refs are ready or any of them fails")
+	statusCmd.Flags().Duration("watch-interval", 2*time.Second, "how often the session is checked for changes")
+	if err := statusCmd.Flags().MarkHidden("watch-interval"); err != nil {
+		logger().Error(err, "failed while trying to hide a flag")
+	}
+
+	statusCmd.Flags().VisitAll(config.BindFullyQualifiedFlag(statusCmd))
+
+	return statusCmd
+}
+
+// printSession renders the session in human readable form unless structured format is chosen.
+func printSession(out io.Writer, format string, s *istiov1alpha1.Session) error {
+	if format == "" {
+		Render(out, s)
+
+		return nil
+	}
+
+	return output.Print(out, format, output.Session(s))
+}
+
+// separator is written between subsequent states of the session when watching it.
+func separator(format string) string {
+	switch format {
+	case "":
+		return "\n---\n\n"
+	case output.YAML:
+		return "---\n"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,181 @@
+package status_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	istiov1alpha1 "github.com/maistra/istio-workspace/api/maistra/v1alpha1"
+	"github.com/maistra/istio-workspace/pkg/client/clientset/versioned/fake"
+	. "github.com/maistra/istio-workspace/pkg/cmd"
+	"github.com/maistra/istio-workspace/pkg/cmd/status"
+	"github.com/maistra/istio-workspace/pkg/internal/session"
+	. "github.com/maistra/istio-workspace/test"
+)
+
+var _ = Describe("Usage of ike status command", func() {
+
+	var (
+		statusCmd      *cobra.Command
+		client         *fake.Clientset
+		originalClient = status.SessionClient
+	)
+
+	BeforeEach(func() {
+		statusCmd = status.NewCmd()
+		statusCmd.SilenceUsage = true
+		statusCmd.SilenceErrors = true
+		NewCmd().AddCommand(statusCmd)
+
+		client = fake.NewSimpleClientset(
+			testSession("feature-x", resource("Deployment", "ratings-v1-feature-x", "created", "True", "")),
+			testSession("bugfix-y",
+				resource("Deployment", "ratings-v1-bugfix-y", "created", "True", ""),
+				resource("DestinationRule", "ratings", "created", "False", "subset version not found"),
+			),
+		)
+		status.SessionClient = func(namespace string) (*session.Client, error) {
+			return session.NewClient(client, "test")
+		}
+	})
+
+	AfterEach(func() {
+		status.SessionClient = originalClient
+	})
+
+	It("should fail when session is not defined", func() {
+		_, err := Run(statusCmd).Passing()
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("session has to be defined"))
+	})
+
+	It("should fail when session does not exist", func() {
+		_, err := Run(statusCmd).Passing("--session", "unknown")
+
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should render refs, targets, resources and hosts of the session", func() {
+		output, err := Run(statusCmd).Passing("--session", "feature-x")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(MatchRegexp(`Session:\s+feature-x`))
+		Expect(output).To(MatchRegexp(`Owner:\s+alice`))
+		Expect(output).To(MatchRegexp(`Route:\s+header:x-workspace-route=feature-x`))
+		Expect(output).To(MatchRegexp(`Hosts:\s+feature-x.ratings.example.com`))
+		Expect(output).To(MatchRegexp(`Ready:\s+1/1 refs`))
+		Expect(output).To(MatchRegexp(`Deployment/ratings-v1\s+located`))
+		Expect(output).To(MatchRegexp(`created\s+Deployment/ratings-v1-feature-x\s+True\s+5m ago`))
+		Expect(output).ToNot(ContainSubstring("Hint:"))
+	})
+
+	It("should highlight failed resources with hints", func() {
+		output, err := Run(statusCmd).Passing("bugfix-y")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(MatchRegexp(`Ready:\s+0/1 refs`))
+		Expect(output).To(MatchRegexp(`! created\s+DestinationRule/ratings\s+False\s+5m ago\s+subset version not found`))
+		Expect(output).To(ContainSubstring("Hint: DestinationRule/ratings: make sure the service is part of the mesh"))
+	})
+
+	It("should print the full session as yaml", func() {
+		output, err := Run(statusCmd).Passing("feature-x", "-o", "yaml")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(ContainSubstring("kind: Session\n"))
+		Expect(output).To(ContainSubstring("name: feature-x"))
+		Expect(output).To(ContainSubstring("- feature-x.ratings.example.com"))
+		Expect(output).ToNot(ContainSubstring("Ready:"))
+	})
+
+	It("should print selected fields using jsonpath", func() {
+		output, err := Run(statusCmd).Passing("feature-x", "-o", "jsonpath={.status.refs[0].resources[0].name}")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(Equal("ratings-v1-feature-x"))
+	})
+
+	It("should fail on unknown output format", func() {
+		_, err := Run(statusCmd).Passing("feature-x", "-o", "xml")
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("unknown output format xml"))
+	})
+
+	It("should watch the session until all refs are ready", func() {
+		pending := testSession("bugfix-y")
+		_, err := client.MaistraV1alpha1().Sessions("test").Update(context.Background(), pending, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+			time.Sleep(50 * time.Millisecond)
+			ready := testSession("bugfix-y", resource("Deployment", "ratings-v1-bugfix-y", "created", "True", ""))
+			_, err := client.MaistraV1alpha1().Sessions("test").Update(context.Background(), ready, metav1.UpdateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+		}()
+
+		output, err := Run(statusCmd).Passing("bugfix-y", "--watch", "--watch-interval", "10ms")
+		<-done
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(MatchRegexp(`(?s)Ready:\s+0/1 refs.*---.*Ready:\s+1/1 refs`))
+	})
+
+	It("should stop watching the session when its resources failed", func() {
+		output, err := Run(statusCmd).Passing("bugfix-y", "--watch", "--watch-interval", "10ms")
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("refs ratings-v1 have failed resources"))
+		Expect(output).To(ContainSubstring("Hint: DestinationRule/ratings: make sure the service is part of the mesh"))
+	})
+})
+
+func testSession(name string, resources ...*istiov1alpha1.RefResource) *istiov1alpha1.Session {
+	located := resource("Deployment", "ratings-v1", "located", "", "")
+
+	return &istiov1alpha1.Session{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "test",
+			Annotations: map[string]string{session.OwnerAnnotation: "alice"},
+		},
+		Status: istiov1alpha1.SessionStatus{
+			RouteExpression: "header:x-workspace-route=" + name,
+			Hosts:           []string{name + ".ratings.example.com"},
+			Refs: []*istiov1alpha1.RefStatus{
+				{
+					Ref:         istiov1alpha1.Ref{Name: "ratings-v1", Strategy: "telepresence"},
+					SubsetLabel: "version",
+					Targets:     []*istiov1alpha1.LabeledRefResource{{RefResource: *located}},
+					Resources:   resources,
+				},
+			},
+		},
+	}
+}
+
+func resource(kind, name, action, success, message string) *istiov1alpha1.RefResource {
+	transition := metav1.NewTime(time.Now().Add(-5 * time.Minute))
+	res := &istiov1alpha1.RefResource{
+		Kind:               &kind,
+		Name:               &name,
+		Action:             &action,
+		LastTransitionTime: &transition,
+	}
+	if success != "" {
+		res.Status = &success
+	}
+	if message != "" {
+		res.Message = &message
+	}
+
+	return res
+}
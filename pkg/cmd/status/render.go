@@ -0,0 +1,194 @@
+package status
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	istiov1alpha1 "github.com/maistra/istio-workspace/api/maistra/v1alpha1"
+	"github.com/maistra/istio-workspace/pkg/internal/session"
+	"github.com/maistra/istio-workspace/pkg/istio"
+	"github.com/maistra/istio-workspace/pkg/k8s"
+	"github.com/maistra/istio-workspace/pkg/model"
+	"github.com/maistra/istio-workspace/pkg/openshift"
+)
+
+const none = "<none>"
+
+// Ready returns true when every ref of the session has its clone created and all its resources succeeded.
+func Ready(s *istiov1alpha1.Session) bool {
+	if len(s.Status.Refs) == 0 {
+		return false
+	}
+	for _, ref := range s.Status.Refs {
+		if !refReady(ref) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Failed returns the names of the refs which have at least one failed resource.
+func Failed(s *istiov1alpha1.Session) []string {
+	var refs []string
+	for _, ref := range s.Status.Refs {
+		for _, res := range ref.Resources {
+			if failed(res) {
+				refs = append(refs, ref.Name)
+
+				break
+			}
+		}
+	}
+
+	return refs
+}
+
+func refReady(ref *istiov1alpha1.RefStatus) bool {
+	cloned := false
+	for _, res := range ref.Resources {
+		if failed(res) {
+			return false
+		}
+		if isClone(res) {
+			cloned = true
+		}
+	}
+
+	return cloned
+}
+
+// Render writes human readable status of the session highlighting failed resources together with remediation hints.
+func Render(out io.Writer, s *istiov1alpha1.Session) {
+	ready := 0
+	for _, ref := range s.Status.Refs {
+		if refReady(ref) {
+			ready++
+		}
+	}
+	route := s.Status.RouteExpression
+	if route == "" && s.Status.Route != nil {
+		route = s.Status.Route.String()
+	}
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "Session:\t%s\n", s.Name)
+	_, _ = fmt.Fprintf(w, "Namespace:\t%s\n", s.Namespace)
+	_, _ = fmt.Fprintf(w, "Owner:\t%s\n", orNone(s.Annotations[session.OwnerAnnotation]))
+	_, _ = fmt.Fprintf(w, "Route:\t%s\n", orNone(route))
+	_, _ = fmt.Fprintf(w, "Hosts:\t%s\n", orNone(strings.Join(s.Status.Hosts, ", ")))
+	_, _ = fmt.Fprintf(w, "Ready:\t%d/%d refs\n", ready, len(s.Status.Refs))
+	_ = w.Flush()
+
+	for _, ref := range s.Status.Refs {
+		renderRef(out, ref)
+	}
+}
+
+func renderRef(out io.Writer, ref *istiov1alpha1.RefStatus) {
+	_, _ = fmt.Fprintf(out, "\nRef %s\n", ref.Name)
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "  Strategy:\t%s\n", orNone(ref.Strategy))
+	_, _ = fmt.Fprintf(w, "  Subset label:\t%s\n", orNone(ref.SubsetLabel))
+	_, _ = fmt.Fprintf(w, "  Ready:\t%t\n", refReady(ref))
+	_ = w.Flush()
+
+	_, _ = fmt.Fprintln(out, "  Targets:")
+	if len(ref.Targets) == 0 {
+		_, _ = fmt.Fprintf(out, "    %s\n", none)
+	}
+	w = tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	for _, target := range ref.Targets {
+		_, _ = fmt.Fprintf(w, "    %s/%s\t%s\n", value(target.Kind), value(target.Name), value(target.Action))
+	}
+	_ = w.Flush()
+
+	_, _ = fmt.Fprintln(out, "  Resources:")
+	if len(ref.Resources) == 0 {
+		_, _ = fmt.Fprintf(out, "    %s\n", none)
+	} else {
+		w = tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "    ACTION\tRESOURCE\tSUCCESS\tLAST TRANSITION\tMESSAGE")
+		for _, res := range ref.Resources {
+			transition := "<unknown>"
+			if res.LastTransitionTime != nil && !res.LastTransitionTime.IsZero() {
+				transition = duration.HumanDuration(time.Since(res.LastTransitionTime.Time)) + " ago"
+			}
+			marker := "  "
+			if failed(res) {
+				marker = "! "
+			}
+			_, _ = fmt.Fprintf(w, "  %s%s\t%s/%s\t%s\t%s\t%s\n",
+				marker, value(res.Action), value(res.Kind), value(res.Name), value(res.Status), transition, value(res.Message))
+		}
+		_ = w.Flush()
+	}
+
+	for _, hint := range hints(ref) {
+		_, _ = fmt.Fprintf(out, "  Hint: %s\n", hint)
+	}
+}
+
+// hints suggests how to fix the failures of the ref.
+func hints(ref *istiov1alpha1.RefStatus) []string {
+	var hints []string
+	if len(ref.Targets) == 0 {
+		hints = append(hints, fmt.Sprintf("no Deployment, DeploymentConfig or Service has been found for %s, check the name and the namespace of the ref", ref.Name))
+	}
+	for _, res := range ref.Resources {
+		if !failed(res) {
+			continue
+		}
+		resource := value(res.Kind) + "/" + value(res.Name)
+		switch value(res.Kind) {
+		case k8s.DeploymentKind, openshift.DeploymentConfigKind:
+			hints = append(hints, fmt.Sprintf("%s: preview the clone produced by the strategy using "+
+				"'ike template render --strategy %s -d %s'", resource, ref.Strategy, ref.Name))
+		case istio.DestinationRuleKind, istio.VirtualServiceKind:
+			hints = append(hints, fmt.Sprintf("%s: make sure the service is part of the mesh and its DestinationRule defines subsets "+
+				"using the %s pod label (see --subset-label)", resource, orDefault(ref.SubsetLabel, "version")))
+		case istio.GatewayKind:
+			hints = append(hints, fmt.Sprintf("%s: make sure the Gateway is bound to a VirtualService routing to the service of the ref", resource))
+		default:
+			hints = append(hints, fmt.Sprintf("%s: check the logs of the istio-workspace controller for details", resource))
+		}
+	}
+
+	return hints
+}
+
+func failed(res *istiov1alpha1.RefResource) bool {
+	return res.Status != nil && strings.EqualFold(*res.Status, "false")
+}
+
+func isClone(res *istiov1alpha1.RefResource) bool {
+	kind := value(res.Kind)
+
+	return (kind == k8s.DeploymentKind || kind == openshift.DeploymentConfigKind) && value(res.Action) == string(model.ActionCreated)
+}
+
+func value(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+func orNone(s string) string {
+	return orDefault(s, none)
+}
+
+func orDefault(s, defaultValue string) string {
+	if s == "" {
+		return defaultValue
+	}
+
+	return s
+}
@@ -0,0 +1,132 @@
+package strategy
+
+import (
+	"context"
+	"sort"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/maistra/istio-workspace/pkg/kubeconfig"
+	"github.com/maistra/istio-workspace/pkg/template"
+)
+
+const builtInSource = "built-in"
+
+// Strategy describes a single strategy known to the patch engine.
+type Strategy struct {
+	Name      string
+	Source    string
+	Format    template.Format
+	Variables map[string]string
+	Required  []string
+}
+
+// VariableNames returns sorted names of all the variables, both declared and required.
+func (s Strategy) VariableNames() []string {
+	names := []string{}
+	for name := range s.Variables {
+		names = append(names, name)
+	}
+	for _, name := range s.Required {
+		if _, declared := s.Variables[name]; !declared {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// IsRequired checks if the variable has to be set when using the strategy.
+func (s Strategy) IsRequired(name string) bool {
+	for _, required := range s.Required {
+		if required == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Catalog builds the list of strategies from the local templates and the given ConfigMaps.
+// ConfigMaps with invalid templates or conflicting with built-in strategies are skipped.
+func Catalog(configMaps []corev1.ConfigMap) []Strategy {
+	source := builtInSource
+	if template.StrategiesPath() != template.DefaultStrategiesPath {
+		source = template.StrategiesPath()
+	}
+
+	sources := map[string]string{}
+	base := template.LoadPatches(template.StrategiesPath())
+	for _, p := range base {
+		sources[p.Name] = source
+	}
+
+	engine := template.NewReloadableEngine(base)
+	for i := range configMaps {
+		configMapSource := "configmap/" + configMaps[i].Namespace + "/" + configMaps[i].Name
+		patches := template.PatchesFromData(configMaps[i].Data)
+		if err := engine.Update(configMapSource, patches); err != nil {
+			logger().Error(err, "skipping strategies", "source", configMapSource)
+
+			continue
+		}
+		for _, p := range patches {
+			if _, exists := sources[p.Name]; !exists {
+				sources[p.Name] = configMapSource
+			}
+		}
+	}
+
+	patches := engine.Patches()
+	strategies := []Strategy{}
+	for _, p := range patches {
+		if p.IsPartial() {
+			continue
+		}
+		strategies = append(strategies, Strategy{
+			Name:      p.Name,
+			Source:    sources[p.Name],
+			Format:    p.Format,
+			Variables: p.Variables,
+			Required:  patches.RequiredVariables(p.Name),
+		})
+	}
+	sort.Slice(strategies, func(i, j int) bool {
+		return strategies[i].Name < strategies[j].Name
+	})
+
+	return strategies
+}
+
+// ClusterConfigMaps retrieves all ConfigMaps defining custom strategies from the given namespace.
+// If namespace is empty the one from the current context is used.
+var ClusterConfigMaps = func(namespace string) ([]corev1.ConfigMap, error) {
+	kubeCfg := kubeconfig.ClientConfig()
+	restCfg, err := kubeCfg.ClientConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get kube config")
+	}
+	if namespace == "" {
+		if namespace, _, err = kubeCfg.Namespace(); err != nil {
+			return nil, errors.Wrap(err, "failed to get current namespace")
+		}
+	}
+
+	c, err := kubernetes.NewForConfig(restCfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create client set")
+	}
+
+	configMaps, err := c.CoreV1().ConfigMaps(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: template.StrategyLabel + "=true",
+	})
+	if err != nil {
+		return nil, errors.WrapWithDetails(err, "failed listing strategies", "namespace", namespace)
+	}
+
+	return configMaps.Items, nil
+}
@@ -0,0 +1,144 @@
+package strategy
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/maistra/istio-workspace/pkg/log"
+)
+
+var logger = func() logr.Logger {
+	return log.Log.WithValues("type", "strategy")
+}
+
+// NewCmd creates instance of "strategy" Cobra Command with "list" and "describe" sub-commands.
+func NewCmd() *cobra.Command {
+	strategyCmd := &cobra.Command{
+		Use:          "strategy",
+		Short:        "Lists and describes strategies used to prepare cloned deployments",
+		SilenceUsage: true,
+	}
+
+	strategyCmd.PersistentFlags().Bool("from-cluster", false, "include custom strategies defined through ConfigMaps in the cluster")
+	strategyCmd.PersistentFlags().StringP("namespace", "n", "", "namespace to look up custom strategies in "+
+		"(defaults to default for the current context)")
+
+	strategyCmd.AddCommand(newListCmd(), newDescribeCmd())
+
+	return strategyCmd
+}
+
+func newListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "list",
+		Aliases:      []string{"ls"},
+		Short:        "Lists all known strategies",
+		Long:         "Lists all known strategies. Variables which have to be set when using the strategy are marked with *.",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			strategies, err := loadStrategies(cmd)
+			if err != nil {
+				return err
+			}
+
+			return printList(cmd.OutOrStdout(), strategies)
+		},
+	}
+}
+
+func newDescribeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "describe [strategy]",
+		Short:        "Shows details of the strategy such as its variables and their defaults",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			strategies, err := loadStrategies(cmd)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveError
+			}
+			var names []string
+			for _, strategy := range strategies {
+				if strings.HasPrefix(strategy.Name, toComplete) {
+					names = append(names, strategy.Name)
+				}
+			}
+
+			return names, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			strategies, err := loadStrategies(cmd)
+			if err != nil {
+				return err
+			}
+			for _, strategy := range strategies {
+				if strategy.Name == args[0] {
+					return printDescription(cmd.OutOrStdout(), strategy)
+				}
+			}
+
+			return errors.Errorf("unknown strategy %s", args[0])
+		},
+	}
+}
+
+func loadStrategies(cmd *cobra.Command) ([]Strategy, error) {
+	var configMaps []corev1.ConfigMap
+	if fromCluster, _ := cmd.Flags().GetBool("from-cluster"); fromCluster {
+		namespace, _ := cmd.Flags().GetString("namespace")
+		cms, err := ClusterConfigMaps(namespace)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed loading strategies from the cluster")
+		}
+		configMaps = cms
+	}
+
+	return Catalog(configMaps), nil
+}
+
+func printList(out io.Writer, strategies []Strategy) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tSOURCE\tVARIABLES")
+	for _, strategy := range strategies {
+		variables := []string{}
+		for _, name := range strategy.VariableNames() {
+			if strategy.IsRequired(name) {
+				name += "*"
+			}
+			variables = append(variables, name)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", strategy.Name, strategy.Source, strings.Join(variables, ","))
+	}
+
+	return errors.Wrap(w.Flush(), "failed printing strategies")
+}
+
+func printDescription(out io.Writer, strategy Strategy) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintf(w, "Name:\t%s\n", strategy.Name)
+	_, _ = fmt.Fprintf(w, "Source:\t%s\n", strategy.Source)
+	_, _ = fmt.Fprintf(w, "Format:\t%s\n", strategy.Format)
+	_, _ = fmt.Fprintln(w, "Variables:")
+	if err := w.Flush(); err != nil {
+		return errors.Wrap(err, "failed printing strategy")
+	}
+
+	w = tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "  NAME\tDEFAULT\tREQUIRED")
+	for _, name := range strategy.VariableNames() {
+		_, _ = fmt.Fprintf(w, "  %s\t%s\t%t\n", name, strategy.Variables[name], strategy.IsRequired(name))
+	}
+
+	return errors.Wrap(w.Flush(), "failed printing strategy")
+}
@@ -0,0 +1,141 @@
+package strategy_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/maistra/istio-workspace/pkg/cmd"
+	"github.com/maistra/istio-workspace/pkg/cmd/strategy"
+	"github.com/maistra/istio-workspace/pkg/kubeconfig"
+	. "github.com/maistra/istio-workspace/test"
+)
+
+var _ = Describe("Usage of ike strategy command", func() {
+
+	var (
+		strategyCmd *cobra.Command
+		listCmd     *cobra.Command
+		describeCmd *cobra.Command
+	)
+
+	BeforeEach(func() {
+		strategyCmd = strategy.NewCmd()
+		strategyCmd.SilenceErrors = true
+		NewCmd().AddCommand(strategyCmd)
+		for _, c := range strategyCmd.Commands() {
+			switch c.Name() {
+			case "list":
+				listCmd = c
+			case "describe":
+				describeCmd = c
+			}
+		}
+	})
+
+	Context("listing strategies", func() {
+
+		It("should list built-in strategies", func() {
+			output, err := Run(listCmd).Passing()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output).To(ContainSubstring("prepared-image"))
+			Expect(output).To(ContainSubstring("telepresence"))
+		})
+
+		It("should not list partial templates", func() {
+			output, err := Run(listCmd).Passing()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output).ToNot(ContainSubstring("_basic-version"))
+		})
+
+		It("should mark required variables", func() {
+			output, err := Run(listCmd).Passing()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output).To(ContainSubstring("version*"))
+		})
+
+		Context("from the cluster", func() {
+
+			var clusterConfigMaps func(namespace string) ([]corev1.ConfigMap, error)
+
+			BeforeEach(func() {
+				clusterConfigMaps = strategy.ClusterConfigMaps
+				strategy.ClusterConfigMaps = func(namespace string) ([]corev1.ConfigMap, error) {
+					return []corev1.ConfigMap{
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "team-strategies", Namespace: "test"},
+							Data: map[string]string{
+								"jvm-remote-debug.tpl": `{{ failIfVariableDoesNotExist .Vars "port" }}[{{ template "_basic-version" . }}{{ template "_basic-remove" . }}]`,
+								"jvm-remote-debug.var": "port=\nsuspend=n",
+							},
+						},
+					}, nil
+				}
+			})
+
+			AfterEach(func() {
+				strategy.ClusterConfigMaps = clusterConfigMaps
+				kubeconfig.Select(kubeconfig.Selection{})
+			})
+
+			It("should list strategies defined in ConfigMaps", func() {
+				output, err := Run(listCmd).Passing("--from-cluster")
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(output).To(ContainSubstring("jvm-remote-debug"))
+				Expect(output).To(ContainSubstring("configmap/test/team-strategies"))
+			})
+
+			It("should read strategies from the cluster selected by global flag", func() {
+				output, err := Run(listCmd).Passing("--from-cluster", "--cluster", "prod")
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(output).To(ContainSubstring("jvm-remote-debug"))
+				Expect(kubeconfig.Selected().Cluster).To(Equal("prod"))
+			})
+
+			It("should not list strategies defined in ConfigMaps by default", func() {
+				output, err := Run(listCmd).Passing()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(output).ToNot(ContainSubstring("jvm-remote-debug"))
+			})
+
+			It("should describe variables of strategy defined in ConfigMap", func() {
+				output, err := Run(describeCmd).Passing("jvm-remote-debug", "--from-cluster")
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(output).To(MatchRegexp(`port\s+true`))
+				Expect(output).To(MatchRegexp(`suspend\s+n\s+false`))
+			})
+		})
+	})
+
+	Context("describing strategy", func() {
+
+		It("should show variables with defaults", func() {
+			output, err := Run(describeCmd).Passing("telepresence")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output).To(ContainSubstring("Name:"))
+			Expect(output).To(MatchRegexp(`subsetLabel\s+version\s+false`))
+			Expect(output).To(MatchRegexp(`version\s+true`))
+		})
+
+		It("should fail on unknown strategy", func() {
+			_, err := Run(describeCmd).Passing("unknown")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unknown strategy unknown"))
+		})
+
+		It("should require strategy name", func() {
+			_, err := Run(describeCmd).Passing()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
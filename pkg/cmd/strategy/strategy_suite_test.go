@@ -0,0 +1,15 @@
+package strategy_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/maistra/istio-workspace/test"
+)
+
+func TestStrategyCmd(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecWithJUnitReporter(t, "Strategy Command Suite")
+}
@@ -0,0 +1,343 @@
+package template
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+
+	"github.com/maistra/istio-workspace/pkg/cmd/strategy"
+	"github.com/maistra/istio-workspace/pkg/kubeconfig"
+	"github.com/maistra/istio-workspace/pkg/log"
+	"github.com/maistra/istio-workspace/pkg/model"
+	tpl "github.com/maistra/istio-workspace/pkg/template"
+)
+
+var logger = func() logr.Logger {
+	return log.Log.WithValues("type", "template")
+}
+
+const (
+	showAll   = "all"
+	showPatch = "patch"
+	showClone = "clone"
+)
+
+var deploymentResources = []struct {
+	kind     string
+	resource schema.GroupVersionResource
+}{
+	{kind: "Deployment", resource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}},
+	{kind: "DeploymentConfig", resource: schema.GroupVersionResource{Group: "apps.openshift.io", Version: "v1", Resource: "deploymentconfigs"}},
+}
+
+// NewCmd creates instance of "template" Cobra Command with "render" sub-command.
+func NewCmd() *cobra.Command {
+	templateCmd := &cobra.Command{
+		Use:          "template",
+		Short:        "Helps authoring strategies used to prepare cloned deployments",
+		SilenceUsage: true,
+	}
+
+	templateCmd.AddCommand(newRenderCmd())
+
+	return templateCmd
+}
+
+func newRenderCmd() *cobra.Command {
+	renderCmd := &cobra.Command{
+		Use:   "render",
+		Short: "Renders the strategy for a given deployment and prints the JSON Patch and the resulting clone",
+		Long: "Renders the strategy for a given deployment and prints the JSON Patch and the resulting clone.\n\n" +
+			"The deployment can be read from a local manifest (-f) or fetched from the cluster (-d). " +
+			"Use --template-path to try out strategies you are working on, they take precedence over the built-in ones.",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE:         render,
+	}
+
+	renderCmd.Flags().String("strategy", "", "name of the strategy to render, multiple strategies separated by comma are applied in the given order")
+	renderCmd.Flags().StringArray("var", []string{}, "strategy variable in the form of name=value, can be repeated")
+	renderCmd.Flags().StringP("filename", "f", "", "Deployment or DeploymentConfig manifest to render the strategy for (use - for stdin)")
+	renderCmd.Flags().StringP("deployment", "d", "", "name of the deployment or deployment config to fetch from the cluster")
+	renderCmd.Flags().StringP("namespace", "n", "", "namespace to fetch the deployment and custom strategies from "+
+		"(defaults to default for the current context)")
+	renderCmd.Flags().String("template-path", "", "directory with .tpl and .var files of strategies to render")
+	renderCmd.Flags().Bool("from-cluster", false, "include custom strategies defined through ConfigMaps in the cluster")
+	renderCmd.Flags().StringP("session", "s", "preview", "name of the session used to calculate the version of the clone")
+	renderCmd.Flags().String("subset-label", model.DefaultSubsetLabel, "pod label used to identify version subsets")
+	renderCmd.Flags().String("show", showAll, fmt.Sprintf("what to print, one of %s, %s or %s", showAll, showPatch, showClone))
+
+	_ = renderCmd.MarkFlagRequired("strategy")
+
+	return renderCmd
+}
+
+func render(cmd *cobra.Command, args []string) error {
+	strategyName, _ := cmd.Flags().GetString("strategy")
+	show, _ := cmd.Flags().GetString("show")
+	if show != showAll && show != showPatch && show != showClone {
+		return errors.Errorf("unknown value %s of show flag, expected one of %s, %s or %s", show, showAll, showPatch, showClone)
+	}
+
+	variables, err := parseVariables(cmd)
+	if err != nil {
+		return err
+	}
+
+	resource, err := loadResource(cmd)
+	if err != nil {
+		return err
+	}
+
+	engine, err := loadEngine(cmd)
+	if err != nil {
+		return err
+	}
+
+	newVersion, err := calculateVersion(cmd, resource, variables[tpl.SubsetLabelVariable])
+	if err != nil {
+		return err
+	}
+
+	rendered, err := engine.Render(strategyName, resource, newVersion, variables)
+	if err != nil {
+		return errors.WrapIf(err, "failed rendering strategy")
+	}
+	clone, err := engine.Run(strategyName, resource, newVersion, variables)
+	if err != nil {
+		return errors.WrapIf(err, "failed applying strategy")
+	}
+
+	return printResult(cmd.OutOrStdout(), show, rendered, clone)
+}
+
+func parseVariables(cmd *cobra.Command) (map[string]string, error) {
+	subsetLabel, _ := cmd.Flags().GetString("subset-label")
+	variables := map[string]string{
+		tpl.SubsetLabelVariable: subsetLabel,
+	}
+
+	vars, _ := cmd.Flags().GetStringArray("var")
+	for _, v := range vars {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("expected variable in format name=value, got %s", v)
+		}
+		variables[parts[0]] = parts[1]
+	}
+
+	return variables, nil
+}
+
+func loadResource(cmd *cobra.Command) ([]byte, error) {
+	filename, _ := cmd.Flags().GetString("filename")
+	deployment, _ := cmd.Flags().GetString("deployment")
+	if (filename == "") == (deployment == "") {
+		return nil, errors.New("either filename or deployment has to be specified")
+	}
+
+	if deployment != "" {
+		namespace, _ := cmd.Flags().GetString("namespace")
+
+		return ClusterResource(namespace, deployment)
+	}
+
+	var raw []byte
+	var err error
+	if filename == "-" {
+		raw, err = ioutil.ReadAll(cmd.InOrStdin())
+	} else {
+		raw, err = ioutil.ReadFile(filename)
+	}
+	if err != nil {
+		return nil, errors.WrapWithDetails(err, "failed reading manifest", "filename", filename)
+	}
+
+	resource, err := yaml.YAMLToJSON(raw)
+	if err != nil {
+		return nil, errors.WrapWithDetails(err, "failed parsing manifest", "filename", filename)
+	}
+
+	return normalizeManifest(resource)
+}
+
+// normalizeManifest sets the fields the API server always sets, but which local manifests usually lack,
+// so that the strategies see the same resource as the one read from the cluster.
+func normalizeManifest(resource []byte) ([]byte, error) {
+	var manifest map[string]interface{}
+	if err := json.Unmarshal(resource, &manifest); err != nil {
+		return nil, errors.Wrap(err, "failed parsing manifest")
+	}
+	metadata, ok := manifest["metadata"].(map[string]interface{})
+	if !ok {
+		metadata = map[string]interface{}{}
+		manifest["metadata"] = metadata
+	}
+	if metadata["creationTimestamp"] == nil {
+		metadata["creationTimestamp"] = metav1.Now().UTC().Format(time.RFC3339)
+	}
+
+	normalized, err := json.Marshal(manifest)
+
+	return normalized, errors.Wrap(err, "failed normalizing manifest")
+}
+
+func loadEngine(cmd *cobra.Command) (*tpl.ReloadableEngine, error) {
+	patches := tpl.LoadPatches(tpl.StrategiesPath())
+
+	if templatePath, _ := cmd.Flags().GetString("template-path"); templatePath != "" {
+		if _, err := os.Stat(templatePath); err != nil {
+			return nil, errors.WrapWithDetails(err, "failed loading strategies", "path", templatePath)
+		}
+		local := tpl.LoadPatches(templatePath)
+		for _, p := range patches {
+			if !containsPatch(local, p.Name) {
+				local = append(local, p)
+			}
+		}
+		patches = local
+	}
+
+	engine := tpl.NewReloadableEngine(patches)
+
+	if fromCluster, _ := cmd.Flags().GetBool("from-cluster"); fromCluster {
+		namespace, _ := cmd.Flags().GetString("namespace")
+		configMaps, err := strategy.ClusterConfigMaps(namespace)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed loading strategies from the cluster")
+		}
+		for i := range configMaps {
+			source := configMaps[i].Namespace + "/" + configMaps[i].Name
+			if err := engine.Update(source, tpl.PatchesFromData(configMaps[i].Data)); err != nil {
+				logger().Error(err, "skipping strategies", "source", source)
+			}
+		}
+	}
+
+	return engine, nil
+}
+
+func containsPatch(patches tpl.Patches, name string) bool {
+	for _, p := range patches {
+		if p.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// calculateVersion determines the version of the clone the same way the operator does for the given session.
+func calculateVersion(cmd *cobra.Command, resource []byte, subsetLabel string) (string, error) {
+	sessionName, _ := cmd.Flags().GetString("session")
+
+	data, err := tpl.NewJSON(resource)
+	if err != nil {
+		return "", err
+	}
+	kind, _ := data.Value("/kind")
+	name, _ := data.Value("/metadata/name")
+
+	labels := map[string]string{}
+	if rawLabels, err := data.Value("/spec/template/metadata/labels"); err == nil {
+		if l, ok := rawLabels.(map[string]interface{}); ok {
+			for k, v := range l {
+				labels[k] = fmt.Sprint(v)
+			}
+		}
+	}
+
+	ref := model.Ref{
+		SubsetLabel: subsetLabel,
+		Targets: []model.LocatedResourceStatus{
+			model.NewLocatedResource(fmt.Sprint(kind), fmt.Sprint(name), labels),
+		},
+	}
+
+	return ref.GetNewVersion(sessionName), nil
+}
+
+func printResult(out io.Writer, show string, rendered []tpl.RenderedPatch, clone []byte) error {
+	if show == showAll || show == showPatch {
+		for _, p := range rendered {
+			patch := new(bytes.Buffer)
+			if err := json.Indent(patch, bytes.TrimSpace(p.Patch), "", "  "); err != nil {
+				// print as is, so the invalid patch can be inspected
+				_, _ = fmt.Fprintln(out, string(p.Patch))
+
+				return errors.WrapWithDetails(err, "failed formatting patch", "patch", p.Name)
+			}
+			if show == showAll || len(rendered) > 1 {
+				_, _ = fmt.Fprintf(out, "# %s (%s)\n", p.Name, p.Format)
+			}
+			_, _ = fmt.Fprintln(out, patch.String())
+		}
+	}
+
+	if show == showAll || show == showClone {
+		cloneYaml, err := yaml.JSONToYAML(clone)
+		if err != nil {
+			return errors.Wrap(err, "failed converting clone to yaml")
+		}
+		if show == showAll {
+			_, _ = fmt.Fprintln(out, "---")
+		}
+		_, _ = fmt.Fprint(out, string(cloneYaml))
+	}
+
+	return nil
+}
+
+// ClusterResource retrieves the Deployment or DeploymentConfig as JSON from the given namespace.
+// If namespace is empty the one from the current context is used.
+var ClusterResource = func(namespace, deployment string) ([]byte, error) {
+	kubeCfg := kubeconfig.ClientConfig()
+	restCfg, err := kubeCfg.ClientConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get kube config")
+	}
+	if namespace == "" {
+		if namespace, _, err = kubeCfg.Namespace(); err != nil {
+			return nil, errors.Wrap(err, "failed to get current namespace")
+		}
+	}
+
+	c, err := dynamic.NewForConfig(restCfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create client")
+	}
+
+	kindName := model.ParseRefKindName(deployment)
+	lastErr := errors.Errorf("unsupported kind %s", kindName.Kind)
+	for _, r := range deploymentResources {
+		if !kindName.SupportsKind(r.kind) {
+			continue
+		}
+		object, err := c.Resource(r.resource).Namespace(namespace).Get(context.Background(), kindName.Name, metav1.GetOptions{})
+		if err != nil {
+			lastErr = err
+
+			continue
+		}
+
+		resource, err := object.MarshalJSON()
+
+		return resource, errors.Wrap(err, "failed reading deployment json")
+	}
+
+	return nil, errors.WrapWithDetails(lastErr, "failed finding deployment", "name", kindName.Name, "namespace", namespace)
+}
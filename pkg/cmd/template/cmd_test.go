@@ -0,0 +1,134 @@
+package template_test
+
+import (
+	"path"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	. "github.com/maistra/istio-workspace/pkg/cmd"
+	"github.com/maistra/istio-workspace/pkg/cmd/template"
+	. "github.com/maistra/istio-workspace/test"
+)
+
+var _ = Describe("Usage of ike template render command", func() {
+
+	var (
+		renderCmd *cobra.Command
+		manifest  string
+	)
+
+	BeforeEach(func() {
+		templateCmd := template.NewCmd()
+		templateCmd.SilenceErrors = true
+		NewCmd().AddCommand(templateCmd)
+		renderCmd, _, _ = templateCmd.Find([]string{"render"})
+
+		manifest = TmpFile(GinkgoT(), "deployment.yaml", reviewsDeployment).Name()
+	})
+
+	Context("input validation", func() {
+
+		It("should fail when strategy is not specified", func() {
+			_, err := ValidateArgumentsOf(renderCmd).Passing("-f", manifest)
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(And(ContainSubstring("required flag(s)"), ContainSubstring("strategy")))
+		})
+
+		It("should fail when neither filename nor deployment is specified", func() {
+			_, err := Run(renderCmd).Passing("--strategy", "prepared-image")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("either filename or deployment has to be specified"))
+		})
+
+		It("should fail on malformed variable", func() {
+			_, err := Run(renderCmd).Passing("--strategy", "prepared-image", "-f", manifest, "--var", "image")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("expected variable in format name=value"))
+		})
+	})
+
+	Context("rendering", func() {
+
+		It("should print patch and clone", func() {
+			output, err := Run(renderCmd).Passing("--strategy", "prepared-image", "-f", manifest, "--var", "image=quay.io/reviews:v2")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output).To(ContainSubstring(`"path": "/spec/template/spec/containers/0/image"`))
+			Expect(output).To(ContainSubstring("image: quay.io/reviews:v2"))
+			Expect(output).To(MatchRegexp(`name: reviews-v1-\w+-preview`))
+		})
+
+		It("should print only the clone", func() {
+			output, err := Run(renderCmd).Passing("--strategy", "prepared-image", "-f", manifest, "--var", "image=quay.io/reviews:v2",
+				"--show", "clone", "--session", "test")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output).ToNot(ContainSubstring(`"op"`))
+			Expect(output).To(MatchRegexp(`name: reviews-v1-\w+-test`))
+		})
+
+		It("should render manifest without creation timestamp the same way as the one from the cluster", func() {
+			output, err := Run(renderCmd).Passing("--strategy", "prepared-image", "-f", manifest, "--var", "image=quay.io/reviews:v2")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output).To(ContainSubstring(`"path": "/metadata/creationTimestamp"`))
+			Expect(output).ToNot(ContainSubstring("creationTimestamp:"))
+		})
+
+		It("should print patches of composed strategies", func() {
+			output, err := Run(renderCmd).Passing("--strategy", "prepared-image,extra-env", "-f", manifest,
+				"--var", "image=quay.io/reviews:v2", "--var", "env=DEBUG=true", "--show", "patch")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output).To(ContainSubstring("# prepared-image (json-patch)"))
+			Expect(output).To(ContainSubstring("# extra-env (strategic-merge-patch)"))
+		})
+
+		It("should fail on missing required variable", func() {
+			_, err := Run(renderCmd).Passing("--strategy", "telepresence", "-f", manifest)
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("expected version variable to be set"))
+		})
+
+		It("should render strategy from template path", func() {
+			strategies := TmpDir(GinkgoT(), "strategies")
+			TmpFile(GinkgoT(), path.Join(strategies, "with-debug-env.tpl"),
+				`[{{ template "_basic-version" . }}{"op": "add", "path": "/metadata/labels/debug", "value": "{{ .Vars.level }}"},{{ template "_basic-remove" . }}]`)
+			TmpFile(GinkgoT(), path.Join(strategies, "with-debug-env.var"), "level=info")
+
+			output, err := Run(renderCmd).Passing("--strategy", "with-debug-env", "-f", manifest,
+				"--template-path", strategies, "--var", "level=trace", "--show", "clone")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output).To(ContainSubstring("debug: trace"))
+		})
+	})
+})
+
+const reviewsDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: reviews-v1
+  labels:
+    app: reviews
+spec:
+  selector:
+    matchLabels:
+      app: reviews
+      version: v1
+  template:
+    metadata:
+      labels:
+        app: reviews
+        version: v1
+    spec:
+      containers:
+      - name: reviews
+        image: quay.io/reviews:v1
+`
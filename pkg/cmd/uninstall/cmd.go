@@ -0,0 +1,118 @@
+package uninstall
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/maistra/istio-workspace/pkg/cmd/config"
+	"github.com/maistra/istio-workspace/pkg/cmd/install"
+	"github.com/maistra/istio-workspace/pkg/internal/session"
+	"github.com/maistra/istio-workspace/pkg/log"
+)
+
+var logger = func() logr.Logger {
+	return log.Log.WithValues("type", "uninstall")
+}
+
+// SessionClient creates the client used to revert the sessions before the operator is removed.
+var SessionClient = session.DefaultClient
+
+// NewCmd creates instance of "uninstall" Cobra Command with flags and execution logic defined.
+func NewCmd() *cobra.Command {
+	uninstallCmd := &cobra.Command{
+		Use:   "uninstall",
+		Short: "Removes istio-workspace operator from the cluster",
+		Long: "Reverts all the sessions of the cluster first, so no cloned deployments or routing rules are left behind, " +
+			"and then removes the operator together with its RBAC rules and the Session CRD.",
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return errors.Wrap(config.SyncFullyQualifiedFlags(cmd), "failed syncing flags")
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			timeout, err := cmd.Flags().GetDuration("timeout")
+			if err != nil {
+				return errors.Wrap(err, "failed reading timeout")
+			}
+			if err := revertSessions(timeout); err != nil {
+				return err
+			}
+
+			c, mapper, err := install.Clients()
+			if err != nil {
+				return err
+			}
+			namespace := cmd.Flag("namespace").Value.String()
+			opts, err := install.Installed(c, namespace)
+			if err != nil {
+				return err
+			}
+			objects, err := install.Render(opts)
+			if err != nil {
+				return err
+			}
+			if err := install.Delete(c, mapper, objects); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "istio-workspace operator uninstalled from namespace %s\n", namespace)
+
+			return nil
+		},
+	}
+
+	uninstallCmd.Flags().StringP("namespace", "n", install.DefaultNamespace, "namespace the operator is installed to")
+	uninstallCmd.Flags().Duration("timeout", 2*time.Minute, "how long to wait for the sessions to be reverted")
+
+	uninstallCmd.Flags().VisitAll(config.BindFullyQualifiedFlag(uninstallCmd))
+
+	return uninstallCmd
+}
+
+// revertSessions deletes the sessions of all namespaces and waits until the operator reverts them.
+func revertSessions(timeout time.Duration) error {
+	c, err := SessionClient("")
+	if err != nil {
+		return errors.WrapIf(err, "failed to get default client")
+	}
+	sessions, err := c.List(true)
+	if crdMissing(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for i := range sessions.Items {
+		s := &sessions.Items[i]
+		err := c.MaistraV1alpha1().Sessions(s.Namespace).Delete(context.Background(), s.Name, metav1.DeleteOptions{})
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return errors.WrapWithDetails(err, "failed deleting session", "name", s.Name, "namespace", s.Namespace)
+		}
+		logger().Info("reverting session", "name", s.Name, "namespace", s.Namespace)
+	}
+	if len(sessions.Items) == 0 {
+		return nil
+	}
+
+	err = wait.PollImmediate(time.Second, timeout, func() (bool, error) {
+		remaining, err := c.List(true)
+		if err != nil {
+			return false, err
+		}
+
+		return len(remaining.Items) == 0, nil
+	})
+
+	return errors.Wrap(err, "failed waiting for sessions to be reverted, make sure the operator is running")
+}
+
+func crdMissing(err error) bool {
+	return err != nil && (k8sErrors.IsNotFound(err) || meta.IsNoMatchError(err))
+}
@@ -0,0 +1,130 @@
+package uninstall_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+
+	istiov1alpha1 "github.com/maistra/istio-workspace/api/maistra/v1alpha1"
+	"github.com/maistra/istio-workspace/pkg/client/clientset/versioned/fake"
+	. "github.com/maistra/istio-workspace/pkg/cmd"
+	"github.com/maistra/istio-workspace/pkg/cmd/install"
+	"github.com/maistra/istio-workspace/pkg/cmd/uninstall"
+	"github.com/maistra/istio-workspace/pkg/internal/session"
+	. "github.com/maistra/istio-workspace/test"
+	"github.com/maistra/istio-workspace/test/testclient"
+)
+
+var _ = Describe("Usage of ike uninstall command", func() {
+
+	var (
+		uninstallCmd          *cobra.Command
+		client                *fakedynamic.FakeDynamicClient
+		sessions              *fake.Clientset
+		originalClient        = install.Clients
+		originalSessionClient = uninstall.SessionClient
+	)
+
+	BeforeEach(func() {
+		uninstallCmd = uninstall.NewCmd()
+		uninstallCmd.SilenceUsage = true
+		uninstallCmd.SilenceErrors = true
+		NewCmd().AddCommand(uninstallCmd)
+
+		client = fakedynamic.NewSimpleDynamicClient(runtime.NewScheme())
+		install.Clients = func() (dynamic.Interface, meta.RESTMapper, error) {
+			return client, testclient.RESTMapper(), nil
+		}
+		sessions = fake.NewSimpleClientset(testSession("team-a", "feature-x"), testSession("team-b", "bugfix-y"))
+		uninstall.SessionClient = func(namespace string) (*session.Client, error) {
+			return session.NewClient(sessions, namespace)
+		}
+	})
+
+	AfterEach(func() {
+		install.Clients = originalClient
+		uninstall.SessionClient = originalSessionClient
+	})
+
+	installOperator := func(args ...string) {
+		objects, err := install.Render(install.Options{Namespace: install.DefaultNamespace, WatchNamespaces: args, Image: "ike"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(install.Apply(client, testclient.RESTMapper(), objects)).To(Succeed())
+	}
+
+	It("should revert all sessions before removing the operator", func() {
+		installOperator()
+
+		output, err := Run(uninstallCmd).Passing()
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(ContainSubstring("uninstalled from namespace " + install.DefaultNamespace))
+		remaining, err := sessions.MaistraV1alpha1().Sessions(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(remaining.Items).To(BeEmpty())
+		Expect(exists(client, "apps", "v1", "deployments", install.DefaultNamespace, "istio-workspace-operator-controller-manager")).To(BeFalse())
+		Expect(exists(client, "rbac.authorization.k8s.io", "v1", "clusterrolebindings", "", "istio-workspace-operator-manager-rolebinding")).To(BeFalse())
+		Expect(exists(client, "apiextensions.k8s.io", "v1", "customresourcedefinitions", "", "sessions.maistra.io")).To(BeFalse())
+		Expect(exists(client, "", "v1", "namespaces", "", install.DefaultNamespace)).To(BeFalse())
+	})
+
+	It("should remove bindings of watched namespaces", func() {
+		installOperator("team-a", "team-b")
+		Expect(exists(client, "rbac.authorization.k8s.io", "v1", "rolebindings", "team-b", "istio-workspace-operator-manager-rolebinding")).To(BeTrue())
+
+		_, err := Run(uninstallCmd).Passing()
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists(client, "rbac.authorization.k8s.io", "v1", "rolebindings", "team-a", "istio-workspace-operator-manager-rolebinding")).To(BeFalse())
+		Expect(exists(client, "rbac.authorization.k8s.io", "v1", "rolebindings", "team-b", "istio-workspace-operator-manager-rolebinding")).To(BeFalse())
+	})
+
+	It("should keep namespace not created by ike", func() {
+		existing := &unstructured.Unstructured{}
+		existing.SetAPIVersion("v1")
+		existing.SetKind("Namespace")
+		existing.SetName(install.DefaultNamespace)
+		client = fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), existing)
+		installOperator()
+
+		_, err := Run(uninstallCmd).Passing()
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists(client, "apps", "v1", "deployments", install.DefaultNamespace, "istio-workspace-operator-controller-manager")).To(BeFalse())
+		Expect(exists(client, "", "v1", "namespaces", "", install.DefaultNamespace)).To(BeTrue())
+	})
+
+	It("should succeed when nothing is installed", func() {
+		_, err := Run(uninstallCmd).Passing()
+
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
+
+func exists(c dynamic.Interface, group, version, resource, namespace, name string) bool {
+	gvr := schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
+	_, err := c.Resource(gvr).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if k8sErrors.IsNotFound(err) {
+		return false
+	}
+	Expect(err).ToNot(HaveOccurred())
+
+	return true
+}
+
+func testSession(namespace, name string) *istiov1alpha1.Session {
+	return &istiov1alpha1.Session{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       istiov1alpha1.SessionSpec{Refs: []istiov1alpha1.Ref{{Name: "ratings-v1"}}},
+	}
+}
@@ -0,0 +1,450 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/maistra/istio-workspace/pkg/kubeconfig"
+	"github.com/maistra/istio-workspace/pkg/log"
+	"github.com/maistra/istio-workspace/pkg/model"
+	"github.com/maistra/istio-workspace/pkg/template"
+)
+
+var logger = func() logr.Logger {
+	return log.Log.WithValues("type", "environment")
+}
+
+// MountRootEnv is the name of the environment variable pointing the local process to the directory
+// the volumes of the target container are written to.
+const MountRootEnv = "IKE_MOUNT_ROOT"
+
+// workloads are the kinds of resources the environment can be imported from, in the order they are looked up.
+var workloads = []struct {
+	kind     string
+	resource schema.GroupVersionResource
+}{
+	{"Deployment", schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}},
+	{"DeploymentConfig", schema.GroupVersionResource{Group: "apps.openshift.io", Version: "v1", Resource: "deploymentconfigs"}},
+	{"StatefulSet", schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}},
+}
+
+// Clients creates the clients used to look up the target and its ConfigMaps and Secrets together with
+// the namespace of the current context.
+var Clients = func() (dynamic.Interface, kubernetes.Interface, string, error) {
+	restCfg, namespace, err := kubeconfig.Load()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	d, err := dynamic.NewForConfig(restCfg)
+	if err != nil {
+		return nil, nil, "", errors.Wrap(err, "failed creating dynamic client")
+	}
+	c, err := kubernetes.NewForConfig(restCfg)
+	if err != nil {
+		return nil, nil, "", errors.Wrap(err, "failed creating client set")
+	}
+
+	return d, c, namespace, nil
+}
+
+// Var is a single environment variable.
+type Var struct {
+	Name  string
+	Value string
+}
+
+// Environment holds what the target container sees when running in the cluster.
+type Environment struct {
+	Vars  []Var             // variables in the order they are defined, later ones take precedence
+	Files map[string][]byte // content of the files of mounted ConfigMaps and Secrets keyed by their path in the container
+}
+
+// Env returns the variables in the form of name=value, as expected by exec.Cmd.
+func (e *Environment) Env() []string {
+	env := make([]string, 0, len(e.Vars))
+	for _, v := range e.Vars {
+		env = append(env, v.Name+"="+v.Value)
+	}
+
+	return env
+}
+
+// WriteEnvFile exports the variables in KEY=VALUE format understood by dotenv tools and IDEs.
+// Values containing whitespace, quotes or other special characters are double quoted.
+func (e *Environment) WriteEnvFile(path string) error {
+	var b strings.Builder
+	for _, v := range e.Vars {
+		value := v.Value
+		if strings.ContainsAny(value, " \t\r\n\"'\\#$`") {
+			value = strconv.Quote(value)
+		}
+		b.WriteString(v.Name + "=" + value + "\n")
+	}
+
+	return errors.WrapWithDetails(ioutil.WriteFile(path, []byte(b.String()), 0600), "failed writing env file", "path", path)
+}
+
+// WriteFiles writes the files of the mounted volumes under the root directory, keeping their paths in the container,
+// e.g. /etc/config/app.properties is written to ROOT/etc/config/app.properties.
+func (e *Environment) WriteFiles(root string) error {
+	for path, content := range e.Files {
+		target := filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+			return errors.WrapWithDetails(err, "failed creating directory", "path", filepath.Dir(target))
+		}
+		if err := ioutil.WriteFile(target, content, 0600); err != nil {
+			return errors.WrapWithDetails(err, "failed writing file", "path", target)
+		}
+	}
+
+	return nil
+}
+
+// Resolve looks up the target, e.g. ratings-v1 or deploymentconfig/ratings-v1, and resolves env, envFrom and
+// ConfigMap and Secret volumes of its container against the cluster. If the container is not specified
+// it is resolved the same way as by the strategies.
+func Resolve(namespace, target, container string) (*Environment, error) {
+	d, c, currentNamespace, err := Clients()
+	if err != nil {
+		return nil, err
+	}
+	if namespace == "" {
+		namespace = currentNamespace
+	}
+
+	workload, err := lookup(d, namespace, model.ParseRefKindName(target))
+	if err != nil {
+		return nil, err
+	}
+	spec, found, err := unstructured.NestedMap(workload.Object, "spec", "template", "spec")
+	if err != nil || !found {
+		return nil, errors.Errorf("unable to find pod template of %s", target)
+	}
+	var pod corev1.PodSpec
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(spec, &pod); err != nil {
+		return nil, errors.Wrapf(err, "failed reading pod template of %s", target)
+	}
+	data, err := workload.MarshalJSON()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading %s", target)
+	}
+	resource, err := template.NewJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	containerName, err := resource.ContainerName(container)
+	if err != nil {
+		return nil, err
+	}
+
+	r := resolver{client: c, namespace: namespace, configMaps: map[string]*corev1.ConfigMap{}, secrets: map[string]*corev1.Secret{}}
+	for i := range pod.Containers {
+		if pod.Containers[i].Name == containerName {
+			return r.resolve(&pod, &pod.Containers[i])
+		}
+	}
+
+	return nil, errors.Errorf("unable to find container %s in %s", containerName, target)
+}
+
+func lookup(d dynamic.Interface, namespace string, ref model.RefKindName) (*unstructured.Unstructured, error) {
+	for _, workload := range workloads {
+		if !ref.SupportsKind(workload.kind) {
+			continue
+		}
+		found, err := d.Resource(workload.resource).Namespace(namespace).Get(context.Background(), ref.Name, metav1.GetOptions{})
+		if err == nil {
+			return found, nil
+		}
+		logger().V(1).Info("target not found", "kind", workload.kind, "name", ref.Name, "reason", err.Error())
+	}
+
+	return nil, errors.Errorf("unable to find %s in namespace %s", ref.String(), namespace)
+}
+
+type resolver struct {
+	client     kubernetes.Interface
+	namespace  string
+	configMaps map[string]*corev1.ConfigMap
+	secrets    map[string]*corev1.Secret
+}
+
+func (r *resolver) resolve(pod *corev1.PodSpec, container *corev1.Container) (*Environment, error) {
+	env := &Environment{Files: map[string][]byte{}}
+	defined := map[string]string{}
+	add := func(name, value string) {
+		env.Vars = append(env.Vars, Var{Name: name, Value: value})
+		defined[name] = value
+	}
+
+	for _, from := range container.EnvFrom {
+		data, err := r.envFrom(from)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range sortedKeys(data) {
+			add(from.Prefix+key, data[key])
+		}
+	}
+	for _, v := range container.Env {
+		if v.ValueFrom == nil {
+			add(v.Name, expand(v.Value, defined))
+
+			continue
+		}
+		value, found, err := r.valueFrom(v.ValueFrom)
+		if err != nil {
+			return nil, errors.WrapWithDetails(err, "failed resolving environment variable", "name", v.Name)
+		}
+		if found {
+			add(v.Name, value)
+		}
+	}
+
+	for _, mount := range container.VolumeMounts {
+		for i := range pod.Volumes {
+			if pod.Volumes[i].Name != mount.Name {
+				continue
+			}
+			files, err := r.volumeFiles(pod.Volumes[i].VolumeSource)
+			if err != nil {
+				return nil, errors.WrapWithDetails(err, "failed resolving volume", "name", mount.Name)
+			}
+			for path, content := range files {
+				switch {
+				case mount.SubPath == "":
+					env.Files[filepath.ToSlash(filepath.Join(mount.MountPath, path))] = content
+				case mount.SubPath == path:
+					env.Files[mount.MountPath] = content
+				}
+			}
+		}
+	}
+
+	return env, nil
+}
+
+func (r *resolver) envFrom(from corev1.EnvFromSource) (map[string]string, error) {
+	switch {
+	case from.ConfigMapRef != nil:
+		cm, err := r.configMap(from.ConfigMapRef.Name, isOptional(from.ConfigMapRef.Optional))
+		if err != nil || cm == nil {
+			return nil, err
+		}
+
+		return cm.Data, nil
+	case from.SecretRef != nil:
+		secret, err := r.secret(from.SecretRef.Name, isOptional(from.SecretRef.Optional))
+		if err != nil || secret == nil {
+			return nil, err
+		}
+		data := make(map[string]string, len(secret.Data))
+		for k, v := range secret.Data {
+			data[k] = string(v)
+		}
+
+		return data, nil
+	}
+
+	return nil, nil
+}
+
+func (r *resolver) valueFrom(from *corev1.EnvVarSource) (value string, found bool, err error) {
+	switch {
+	case from.ConfigMapKeyRef != nil:
+		ref := from.ConfigMapKeyRef
+		cm, err := r.configMap(ref.Name, isOptional(ref.Optional))
+		if err != nil || cm == nil {
+			return "", false, err
+		}
+		value, found := cm.Data[ref.Key]
+		if !found && !isOptional(ref.Optional) {
+			return "", false, errors.Errorf("key %s not found in ConfigMap %s", ref.Key, ref.Name)
+		}
+
+		return value, found, nil
+	case from.SecretKeyRef != nil:
+		ref := from.SecretKeyRef
+		secret, err := r.secret(ref.Name, isOptional(ref.Optional))
+		if err != nil || secret == nil {
+			return "", false, err
+		}
+		value, found := secret.Data[ref.Key]
+		if !found && !isOptional(ref.Optional) {
+			return "", false, errors.Errorf("key %s not found in Secret %s", ref.Key, ref.Name)
+		}
+
+		return string(value), found, nil
+	case from.FieldRef != nil && from.FieldRef.FieldPath == "metadata.namespace":
+		return r.namespace, true, nil
+	}
+	// fields of the pod itself, e.g. status.podIP, and resources have no counterpart outside of the cluster
+	logger().V(1).Info("skipping environment variable not available outside of the pod")
+
+	return "", false, nil
+}
+
+func (r *resolver) volumeFiles(volume corev1.VolumeSource) (map[string][]byte, error) {
+	switch {
+	case volume.ConfigMap != nil:
+		return r.configMapFiles(volume.ConfigMap.Name, volume.ConfigMap.Items, isOptional(volume.ConfigMap.Optional))
+	case volume.Secret != nil:
+		return r.secretFiles(volume.Secret.SecretName, volume.Secret.Items, isOptional(volume.Secret.Optional))
+	case volume.Projected != nil:
+		files := map[string][]byte{}
+		for _, source := range volume.Projected.Sources {
+			var projected map[string][]byte
+			var err error
+			switch {
+			case source.ConfigMap != nil:
+				projected, err = r.configMapFiles(source.ConfigMap.Name, source.ConfigMap.Items, isOptional(source.ConfigMap.Optional))
+			case source.Secret != nil:
+				projected, err = r.secretFiles(source.Secret.Name, source.Secret.Items, isOptional(source.Secret.Optional))
+			}
+			if err != nil {
+				return nil, err
+			}
+			for path, content := range projected {
+				files[path] = content
+			}
+		}
+
+		return files, nil
+	}
+
+	return nil, nil
+}
+
+func (r *resolver) configMapFiles(name string, items []corev1.KeyToPath, optional bool) (map[string][]byte, error) {
+	cm, err := r.configMap(name, optional)
+	if err != nil || cm == nil {
+		return nil, err
+	}
+	data := make(map[string][]byte, len(cm.Data)+len(cm.BinaryData))
+	for k, v := range cm.Data {
+		data[k] = []byte(v)
+	}
+	for k, v := range cm.BinaryData {
+		data[k] = v
+	}
+
+	return project(data, items, optional, "ConfigMap "+name)
+}
+
+func (r *resolver) secretFiles(name string, items []corev1.KeyToPath, optional bool) (map[string][]byte, error) {
+	secret, err := r.secret(name, optional)
+	if err != nil || secret == nil {
+		return nil, err
+	}
+
+	return project(secret.Data, items, optional, "Secret "+name)
+}
+
+// project maps the keys to the file paths, either all the keys to the files of the same name or only the selected items.
+func project(data map[string][]byte, items []corev1.KeyToPath, optional bool, source string) (map[string][]byte, error) {
+	if len(items) == 0 {
+		return data, nil
+	}
+	files := make(map[string][]byte, len(items))
+	for _, item := range items {
+		content, found := data[item.Key]
+		if !found {
+			if optional {
+				continue
+			}
+
+			return nil, errors.Errorf("key %s not found in %s", item.Key, source)
+		}
+		files[item.Path] = content
+	}
+
+	return files, nil
+}
+
+// configMap retrieves the ConfigMap once, returning nil if it does not exist but is optional.
+func (r *resolver) configMap(name string, optional bool) (*corev1.ConfigMap, error) {
+	if cm, found := r.configMaps[name]; found {
+		return cm, nil
+	}
+	cm, err := r.client.CoreV1().ConfigMaps(r.namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) && optional {
+		cm, err = nil, nil
+	}
+	if err != nil {
+		return nil, errors.WrapWithDetails(err, "failed retrieving ConfigMap", "name", name, "namespace", r.namespace)
+	}
+	r.configMaps[name] = cm
+
+	return cm, nil
+}
+
+// secret retrieves the Secret once, returning nil if it does not exist but is optional.
+func (r *resolver) secret(name string, optional bool) (*corev1.Secret, error) {
+	if secret, found := r.secrets[name]; found {
+		return secret, nil
+	}
+	secret, err := r.client.CoreV1().Secrets(r.namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) && optional {
+		secret, err = nil, nil
+	}
+	if err != nil {
+		return nil, errors.WrapWithDetails(err, "failed retrieving Secret", "name", name, "namespace", r.namespace)
+	}
+	r.secrets[name] = secret
+
+	return secret, nil
+}
+
+var reference = regexp.MustCompile(`\$\$|\$\(([A-Za-z_][A-Za-z0-9_.-]*)\)`)
+
+// expand replaces references to the variables defined before, e.g. $(HOST), the same way as kubelet does.
+// References to undefined variables are left as they are and $$ escapes the reference.
+func expand(value string, defined map[string]string) string {
+	return reference.ReplaceAllStringFunc(value, func(match string) string {
+		if match == "$$" {
+			return "$"
+		}
+		if v, found := defined[match[2:len(match)-1]]; found {
+			return v
+		}
+
+		return match
+	})
+}
+
+func isOptional(optional *bool) bool {
+	return optional != nil && *optional
+}
+
+func sortedKeys(data map[string]string) []string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// String describes the environment, e.g. for logging, without revealing the values.
+func (e *Environment) String() string {
+	return fmt.Sprintf("%d variables, %d files", len(e.Vars), len(e.Files))
+}
@@ -0,0 +1,200 @@
+package environment_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/maistra/istio-workspace/pkg/environment"
+)
+
+var _ = Describe("Importing environment of the target container", func() {
+
+	var (
+		objects         []runtime.Object
+		deployment      *appsv1.Deployment
+		originalClients = environment.Clients
+	)
+
+	BeforeEach(func() {
+		optional := true
+		deployment = &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Name: "ratings-v1", Namespace: "test"},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{Name: "istio-proxy", Env: []corev1.EnvVar{{Name: "PROXY", Value: "true"}}},
+							{
+								Name: "ratings",
+								EnvFrom: []corev1.EnvFromSource{
+									{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "settings"}}},
+									{Prefix: "DB_", SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}}},
+									{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}, Optional: &optional}},
+								},
+								Env: []corev1.EnvVar{
+									{Name: "LOG_LEVEL", Value: "debug"},
+									{Name: "URL", Value: "http://$(HOST):9080/$$(HOST)"},
+									{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{
+										SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}, Key: "password"},
+									}},
+									{Name: "NAMESPACE", ValueFrom: &corev1.EnvVarSource{
+										FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"},
+									}},
+									{Name: "POD_IP", ValueFrom: &corev1.EnvVarSource{
+										FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.podIP"},
+									}},
+								},
+								VolumeMounts: []corev1.VolumeMount{
+									{Name: "config", MountPath: "/etc/ratings"},
+									{Name: "certs", MountPath: "/etc/certs/tls.crt", SubPath: "tls.crt"},
+									{Name: "data", MountPath: "/data"},
+								},
+							},
+						},
+						Volumes: []corev1.Volume{
+							{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "settings"},
+								Items:                []corev1.KeyToPath{{Key: "HOST", Path: "conf/host"}},
+							}}},
+							{Name: "certs", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "tls"}}},
+							{Name: "data", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+						},
+					},
+				},
+			},
+		}
+		objects = []runtime.Object{
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "test"},
+				Data:       map[string]string{"HOST": "details", "LOG_LEVEL": "info"},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "test"},
+				Data:       map[string][]byte{"password": []byte("s3cr3t")},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "test"},
+				Data:       map[string][]byte{"tls.crt": []byte("CERT"), "tls.key": []byte("KEY")},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(deployment)
+		Expect(err).ToNot(HaveOccurred())
+		d := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), &unstructured.Unstructured{Object: raw})
+		c := fake.NewSimpleClientset(objects...)
+		environment.Clients = func() (dynamic.Interface, kubernetes.Interface, string, error) {
+			return d, c, "test", nil
+		}
+	})
+
+	AfterEach(func() {
+		environment.Clients = originalClients
+	})
+
+	It("should resolve env and envFrom of the default container in the order kubelet does", func() {
+		env, err := environment.Resolve("", "ratings-v1", "")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env.Env()).To(Equal([]string{
+			"HOST=details",
+			"LOG_LEVEL=info",
+			"DB_password=s3cr3t",
+			"LOG_LEVEL=debug",
+			"URL=http://details:9080/$(HOST)",
+			"TOKEN=s3cr3t",
+			"NAMESPACE=test",
+		}))
+	})
+
+	It("should resolve the selected container of the target with its kind", func() {
+		env, err := environment.Resolve("test", "deployment/ratings-v1", "istio-proxy")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env.Env()).To(Equal([]string{"PROXY=true"}))
+		Expect(env.Files).To(BeEmpty())
+	})
+
+	It("should resolve files of ConfigMap and Secret volumes", func() {
+		env, err := environment.Resolve("", "ratings-v1", "")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env.Files).To(Equal(map[string][]byte{
+			"/etc/ratings/conf/host": []byte("details"),
+			"/etc/certs/tls.crt":     []byte("CERT"),
+		}))
+	})
+
+	Context("with missing Secret", func() {
+
+		BeforeEach(func() {
+			objects = objects[:1]
+		})
+
+		It("should fail when the Secret is required", func() {
+			_, err := environment.Resolve("", "ratings-v1", "")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed retrieving Secret"))
+		})
+	})
+
+	It("should fail when the target does not exist", func() {
+		_, err := environment.Resolve("", "reviews-v1", "")
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("unable to find reviews-v1 in namespace test"))
+	})
+
+	Context("writing", func() {
+
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "ike-env-")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("should write files under their mount paths", func() {
+			env, err := environment.Resolve("", "ratings-v1", "")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(env.WriteFiles(dir)).To(Succeed())
+
+			Expect(ioutil.ReadFile(filepath.Join(dir, "etc", "ratings", "conf", "host"))).To(Equal([]byte("details")))
+			Expect(ioutil.ReadFile(filepath.Join(dir, "etc", "certs", "tls.crt"))).To(Equal([]byte("CERT")))
+		})
+
+		It("should export variables quoting special characters", func() {
+			env := &environment.Environment{Vars: []environment.Var{
+				{Name: "HOST", Value: "details"},
+				{Name: "GREETING", Value: "hello \"world\"\nbye"},
+			}}
+			file := filepath.Join(dir, ".env")
+
+			Expect(env.WriteEnvFile(file)).To(Succeed())
+
+			Expect(ioutil.ReadFile(file)).To(Equal([]byte("HOST=details\nGREETING=\"hello \\\"world\\\"\\nbye\"\n")))
+		})
+	})
+})
@@ -8,6 +8,7 @@ import (
 
 	"emperror.dev/errors"
 	"github.com/go-logr/logr"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -17,6 +18,9 @@ import (
 	"github.com/maistra/istio-workspace/pkg/naming"
 )
 
+// OwnerAnnotation holds the name of the user who created the Session.
+const OwnerAnnotation = "maistra.io/istio-workspace-owner"
+
 var (
 	logger = func() logr.Logger {
 		return log.Log.WithValues("type", "session")
@@ -32,7 +36,9 @@ type Options struct {
 	RouteExp       string                                // expression of how to route the traffic to the target resource
 	Strategy       string                                // name of the strategy to use for the target resource
 	StrategyArgs   map[string]string                     // additional arguments for the strategy
+	SubsetLabel    string                                // name of the pod label used to identify version subsets, detected by the operator if empty
 	Revert         bool                                  // Revert back to previous known value if join/leave a existing session with a known ref
+	KeepSession    bool                                  // KeepSession keeps the session in the cluster when the last ref leaves it
 	Duration       *time.Duration                        // Duration defines the interval used to check for changes to the session object
 	WaitCondition  func(*istiov1alpha1.RefResource) bool // WaitCondition should return true when session is in a state to move on
 }
@@ -52,9 +58,11 @@ func defaultWaitCondition(res *istiov1alpha1.RefResource) bool {
 
 // State holds the new variables as presented by the creation of the session.
 type State struct {
+	SessionName    string                  // name of the session created or joined.
 	DeploymentName string                  // name of the resource to target within the cloned route.
 	RefStatus      istiov1alpha1.RefStatus // the current ref status object
 	Route          istiov1alpha1.Route     // the current route configuration
+	Session        *istiov1alpha1.Session  // the whole session including the status of all its refs
 }
 
 // Handler is a function to setup a server session before attempting to connect. Returns a 'cleanup' function.
@@ -62,7 +70,14 @@ type Handler func(opts Options, client *Client) (State, func(), error)
 
 // Offline is a empty Handler doing nothing. Used for testing.
 func Offline(opts Options, client *Client) (State, func(), error) {
-	return State{DeploymentName: opts.DeploymentName}, func() {}, nil
+	session := &istiov1alpha1.Session{
+		ObjectMeta: metav1.ObjectMeta{Name: opts.SessionName, Namespace: opts.NamespaceName},
+		Spec: istiov1alpha1.SessionSpec{
+			Refs: []istiov1alpha1.Ref{{Name: opts.DeploymentName, Strategy: opts.Strategy, Args: opts.StrategyArgs}},
+		},
+	}
+
+	return State{SessionName: opts.SessionName, DeploymentName: opts.DeploymentName, Session: session}, func() {}, nil
 }
 
 // handler wraps the session client and required metadata used to manipulate the resources.
@@ -113,14 +128,67 @@ func CreateOrJoinHandler(opts Options, client *Client) (State, func(), error) {
 	}
 
 	return State{
+			SessionName:    sessionName,
 			DeploymentName: serviceName,
 			RefStatus:      getCurrentRef(opts.DeploymentName, *session),
 			Route:          *route,
+			Session:        session,
 		}, func() {
 			h.removeOrLeaveSession()
 		}, nil
 }
 
+// JoinHandler adds the ref to an existing session. Unlike CreateOrJoinHandler it fails when the session does not exist
+// or the ref is already part of it.
+// Rely on the following flags:
+//  * namespace - the name of the target namespace where deployment is defined
+//  * deployment - the name of the target deployment and will update the flag with the new deployment name
+//  * session - the name of the session.
+func JoinHandler(opts Options, client *Client) (State, func(), error) {
+	session, err := client.Get(opts.SessionName)
+	if err != nil {
+		return State{}, func() {}, sessionNotFound(err, opts.SessionName)
+	}
+	if hasRef(session, opts.DeploymentName) {
+		return State{}, func() {}, RefAlreadyJoinedError{name: opts.DeploymentName, session: opts.SessionName}
+	}
+
+	return CreateOrJoinHandler(opts, client)
+}
+
+// Leave removes the ref from an existing session failing when the session does not exist or the ref is not part of it.
+// The session is deleted when the last ref leaves it, unless KeepSession is set.
+func Leave(opts Options, client *Client) error {
+	session, err := client.Get(opts.SessionName)
+	if err != nil {
+		return sessionNotFound(err, opts.SessionName)
+	}
+	if !hasRef(session, opts.DeploymentName) {
+		return RefNotFoundError{name: opts.DeploymentName, session: opts.SessionName}
+	}
+	h := &handler{c: client, opts: opts}
+
+	return h.leaveSession()
+}
+
+func sessionNotFound(err error, name string) error {
+	if k8sErrors.IsNotFound(err) {
+		return SessionNotFoundError{name: name}
+	}
+
+	return err
+}
+
+func hasRef(session *istiov1alpha1.Session, name string) bool {
+	for _, ref := range session.Spec.Refs {
+		if ref.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
 func getCurrentRef(deploymentName string, session istiov1alpha1.Session) istiov1alpha1.RefStatus {
 	for _, ref := range session.Status.Refs {
 		if ref.Name == deploymentName {
@@ -194,12 +262,16 @@ func (h *handler) createSession() (*istiov1alpha1.Session, error) {
 			Refs: []istiov1alpha1.Ref{
 				{Name: h.opts.DeploymentName, Strategy: h.opts.Strategy, Args: h.opts.StrategyArgs},
 			},
+			SubsetLabel: h.opts.SubsetLabel,
 		},
 	}
 
 	if r != nil {
 		session.Spec.Route = *r
 	}
+	if u, err := user.Current(); err == nil {
+		session.Annotations = map[string]string{OwnerAnnotation: u.Username}
+	}
 
 	return &session, h.c.Create(&session)
 }
@@ -240,11 +312,15 @@ func (h *handler) waitForRefToComplete() (*istiov1alpha1.Session, string, error)
 }
 
 func (h *handler) removeOrLeaveSession() {
-	session, err := h.c.Get(h.opts.SessionName)
-	if err != nil {
+	if err := h.leaveSession(); err != nil {
 		logger().Error(err, "failed removing or leaving session")
+	}
+}
 
-		return // assume missing, nothing to clean?
+func (h *handler) leaveSession() error {
+	session, err := h.c.Get(h.opts.SessionName)
+	if err != nil {
+		return err // assume missing, nothing to clean?
 	}
 	// more than one participant, update session
 	for i, r := range session.Spec.Refs {
@@ -256,11 +332,11 @@ func (h *handler) removeOrLeaveSession() {
 			}
 		}
 	}
-	if len(session.Spec.Refs) == 0 {
-		_ = h.c.Delete(session)
-	} else {
-		_ = h.c.Update(session)
+	if len(session.Spec.Refs) == 0 && !h.opts.KeepSession {
+		return h.c.Delete(session)
 	}
+
+	return h.c.Update(session)
 }
 
 var nonAlphaNumeric = regexp.MustCompile("[^A-Za-z0-9]+")
@@ -2,13 +2,14 @@ package session
 
 import (
 	"context"
+	"sync"
 
 	"emperror.dev/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/tools/clientcmd"
 
 	istiov1alpha1 "github.com/maistra/istio-workspace/api/maistra/v1alpha1"
 	"github.com/maistra/istio-workspace/pkg/client/clientset/versioned"
+	"github.com/maistra/istio-workspace/pkg/kubeconfig"
 )
 
 // Client interacts with the k8s api server.
@@ -22,29 +23,35 @@ func NewClient(c versioned.Interface, namespace string) (*Client, error) {
 	return &Client{namespace: namespace, Interface: c}, nil
 }
 
-var defaultClient *Client
+var (
+	defaultClientsMu sync.Mutex
+	defaultClients   = map[string]*Client{}
+)
 
 // DefaultClient creates a client based on existing kube config.
-// The instance is created lazily only once and shared among all the callers
-// While resolving configuration we look for .kube/config file unless KUBECONFIG env variable is set
+// Instances are created lazily and shared among all the callers asking for the same context and namespace.
+// While resolving configuration we look for .kube/config file unless KUBECONFIG env variable is set, or the kube config,
+// context and cluster are selected explicitly (see kubeconfig.Select).
 // If namespace parameter is empty default one from the current context is used.
 func DefaultClient(namespace string) (*Client, error) {
-	if defaultClient == nil {
-		c2, err := createDefaultClient(namespace)
-		if err != nil {
-			return c2, errors.WrapIf(err, "failed to create default client")
-		}
+	defaultClientsMu.Lock()
+	defer defaultClientsMu.Unlock()
+
+	key := kubeconfig.Selected().Key() + "|" + namespace
+	if c, found := defaultClients[key]; found {
+		return c, nil
+	}
+	c, err := createDefaultClient(namespace)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create default client")
 	}
+	defaultClients[key] = c
 
-	return defaultClient, nil
+	return c, nil
 }
 
 func createDefaultClient(namespace string) (*Client, error) {
-	kubeCfg := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-		clientcmd.NewDefaultClientConfigLoadingRules(),
-		&clientcmd.ConfigOverrides{},
-	)
-	var err error
+	kubeCfg := kubeconfig.ClientConfig()
 	restCfg, err := kubeCfg.ClientConfig()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get kube config")
@@ -61,12 +68,8 @@ func createDefaultClient(namespace string) (*Client, error) {
 			return nil, errors.Wrap(err, "failed to get current namespace")
 		}
 	}
-	defaultClient, err = NewClient(c, namespace)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create client")
-	}
 
-	return defaultClient, nil
+	return NewClient(c, namespace)
 }
 
 // Create creates a session instance in a cluster.
@@ -100,3 +103,14 @@ func (c *Client) Get(sessionName string) (*istiov1alpha1.Session, error) {
 
 	return session, errors.WrapWithDetails(err, "failed retrieving session", "kind", "session", "name", sessionName, "namespace", c.namespace)
 }
+
+// List retrieves Sessions of the client namespace, or of all namespaces if allNamespaces is set.
+func (c *Client) List(allNamespaces bool) (*istiov1alpha1.SessionList, error) {
+	namespace := c.namespace
+	if allNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+	sessions, err := c.MaistraV1alpha1().Sessions(namespace).List(context.Background(), metav1.ListOptions{})
+
+	return sessions, errors.WrapWithDetails(err, "failed listing sessions", "kind", "session", "namespace", namespace)
+}
@@ -10,6 +10,8 @@ import (
 	istiov1alpha1 "github.com/maistra/istio-workspace/api/maistra/v1alpha1"
 	testclient "github.com/maistra/istio-workspace/pkg/client/clientset/versioned/fake"
 	"github.com/maistra/istio-workspace/pkg/internal/session"
+	"github.com/maistra/istio-workspace/pkg/kubeconfig"
+	. "github.com/maistra/istio-workspace/test"
 )
 
 var _ = Describe("Session Client operations", func() {
@@ -70,4 +72,67 @@ var _ = Describe("Session Client operations", func() {
 		})
 
 	})
+
+	Context("default client", func() {
+
+		BeforeEach(func() {
+			kubeconfigPath := TmpFile(GinkgoT(), "kubeconfig.yaml", `apiVersion: v1
+kind: Config
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com:6443
+- name: prod
+  cluster:
+    server: https://prod.example.com:6443
+contexts:
+- name: dev
+  context:
+    cluster: dev
+- name: prod
+  context:
+    cluster: prod
+current-context: dev
+`).Name()
+			kubeconfig.Select(kubeconfig.Selection{Kubeconfig: kubeconfigPath})
+		})
+
+		AfterEach(func() {
+			kubeconfig.Select(kubeconfig.Selection{})
+			CleanUpTmpFiles(GinkgoT())
+		})
+
+		It("should reuse client of the same namespace", func() {
+			first, err := session.DefaultClient("team-a")
+			Expect(err).ToNot(HaveOccurred())
+
+			second, err := session.DefaultClient("team-a")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(second).To(BeIdenticalTo(first))
+		})
+
+		It("should create separate client for each namespace", func() {
+			first, err := session.DefaultClient("team-a")
+			Expect(err).ToNot(HaveOccurred())
+
+			second, err := session.DefaultClient("team-b")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(second).ToNot(BeIdenticalTo(first))
+		})
+
+		It("should create separate client for each context", func() {
+			first, err := session.DefaultClient("team-a")
+			Expect(err).ToNot(HaveOccurred())
+
+			selection := kubeconfig.Selected()
+			selection.Context = "prod"
+			kubeconfig.Select(selection)
+			second, err := session.DefaultClient("team-a")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(second).ToNot(BeIdenticalTo(first))
+		})
+	})
 })
@@ -149,6 +149,82 @@ var _ = Describe("Session operations", func() {
 				Expect(sess.Spec.Refs[0].Strategy).To(Equal(preparedImage))
 			})
 		})
+		Context("explicit join and leave", func() {
+			BeforeEach(func() {
+				objects = []runtime.Object{
+					&istiov1alpha1.Session{
+						TypeMeta: metav1.TypeMeta{
+							APIVersion: "maistra.io/v1alpha1",
+							Kind:       "Session",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      opts.SessionName,
+							Namespace: opts.NamespaceName,
+						},
+						Spec: istiov1alpha1.SessionSpec{
+							Refs: []istiov1alpha1.Ref{
+								{Name: opts.DeploymentName + "-1", Strategy: opts.Strategy, Args: opts.StrategyArgs},
+							},
+						},
+					}}
+			})
+
+			It("should fail joining a session which does not exist", func() {
+				opts.SessionName = "unknown-session"
+
+				_, _, err := session.JoinHandler(opts, client)
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("session 'unknown-session' does not exist"))
+				_, err = client.Get(opts.SessionName)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should fail joining a session the ref is already part of", func() {
+				opts.DeploymentName = opts.DeploymentName + "-1"
+
+				_, _, err := session.JoinHandler(opts, client)
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("ref 'test-deployment-1' is already part of session 'test-session'"))
+			})
+
+			It("should join an existing session", func() {
+				_, _, err := session.JoinHandler(opts, client)
+				Expect(err).ToNot(HaveOccurred())
+
+				sess, err := client.Get(opts.SessionName)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sess.Spec.Refs).To(HaveLen(2))
+			})
+
+			It("should fail leaving a session the ref is not part of", func() {
+				err := session.Leave(opts, client)
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("ref 'test-deployment' is not part of session 'test-session'"))
+			})
+
+			It("should delete the session when the last ref leaves", func() {
+				opts.DeploymentName = opts.DeploymentName + "-1"
+
+				Expect(session.Leave(opts, client)).To(Succeed())
+
+				_, err := client.Get(opts.SessionName)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should keep the empty session when asked to", func() {
+				opts.DeploymentName = opts.DeploymentName + "-1"
+				opts.KeepSession = true
+
+				Expect(session.Leave(opts, client)).To(Succeed())
+
+				sess, err := client.Get(opts.SessionName)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sess.Spec.Refs).To(BeEmpty())
+			})
+		})
 		Context("remove", func() {
 			BeforeEach(func() {
 				objects = []runtime.Object{
@@ -11,3 +11,35 @@ type DeploymentNotFoundError struct {
 func (dnfe DeploymentNotFoundError) Error() string {
 	return fmt.Sprintf("no Deployment or DeploymentConfig found for target '%s'", dnfe.name)
 }
+
+// SessionNotFoundError denotes failing to find the session to join or leave.
+type SessionNotFoundError struct {
+	name string
+}
+
+// Error returns the formatted session error.
+func (snfe SessionNotFoundError) Error() string {
+	return fmt.Sprintf("session '%s' does not exist", snfe.name)
+}
+
+// RefNotFoundError denotes the ref not being part of the session it should leave.
+type RefNotFoundError struct {
+	name    string
+	session string
+}
+
+// Error returns the formatted ref error.
+func (rnfe RefNotFoundError) Error() string {
+	return fmt.Sprintf("ref '%s' is not part of session '%s'", rnfe.name, rnfe.session)
+}
+
+// RefAlreadyJoinedError denotes the ref being already part of the session it should join.
+type RefAlreadyJoinedError struct {
+	name    string
+	session string
+}
+
+// Error returns the formatted ref error.
+func (raje RefAlreadyJoinedError) Error() string {
+	return fmt.Sprintf("ref '%s' is already part of session '%s', leave it first", raje.name, raje.session)
+}
@@ -1,6 +1,8 @@
 package istio
 
 import (
+	"sort"
+
 	"emperror.dev/errors"
 	istionetworkv1alpha3 "istio.io/api/networking/v1alpha3"
 	istionetwork "istio.io/client-go/pkg/apis/networking/v1alpha3"
@@ -18,6 +20,7 @@ const (
 	DestinationRuleKind = "DestinationRule"
 )
 
+var _ model.Locator = SubsetLabelLocator
 var _ model.Mutator = DestinationRuleMutator
 var _ model.Revertor = DestinationRuleRevertor
 var _ model.Manipulator = destinationRuleManipulator{}
@@ -47,7 +50,7 @@ func DestinationRuleMutator(ctx model.SessionContext, ref *model.Ref) error {
 	for _, hostName := range ref.GetTargetHostNames() {
 		newVersion := ref.GetNewVersion(ctx.Name)
 
-		subset, err := getTargetSubset(ctx, ctx.Namespace, hostName, ref.GetVersion())
+		subset, err := getTargetSubset(ctx, ctx.Namespace, hostName, ref.GetSubsetLabel(), ref.GetVersion())
 		if err != nil {
 			errs = append(errs, errors.WrapIfWithDetails(err, "failed to find Subset", "version", ref.GetVersion(), "host", hostName.String()))
 
@@ -65,7 +68,7 @@ func DestinationRuleMutator(ctx model.SessionContext, ref *model.Ref) error {
 					{
 						Name: newVersion,
 						Labels: map[string]string{
-							"version": newVersion,
+							ref.GetSubsetLabel(): newVersion,
 						},
 						TrafficPolicy: subset.TrafficPolicy,
 					},
@@ -125,7 +128,64 @@ func DestinationRuleRevertor(ctx model.SessionContext, ref *model.Ref) error {
 		"failed to revert destination rules for session", "session", ctx.Name, "namespace", ctx.Namespace, "ref", ref.KindName.Name)
 }
 
-func getTargetSubset(ctx model.SessionContext, namespace string, hostName model.HostName, targetVersion string) (*istionetworkv1alpha3.Subset, error) {
+// SubsetLabelLocator attempts to detect the pod label used to identify version subsets based on the existing DestinationRules
+// when the Ref has no explicitly configured one.
+//
+// A subset is considered matching when all of its labels are present on the located Deployment or DeploymentConfig.
+// If the matching subset defines more than one label, model.DefaultSubsetLabel is preferred, otherwise the first label in alphabetical order is used.
+func SubsetLabelLocator(ctx model.SessionContext, ref *model.Ref) bool {
+	if ref.SubsetLabel != "" {
+		return false
+	}
+	targets := ref.GetTargets(model.AnyKind("Deployment", "DeploymentConfig"))
+	if len(targets) != 1 {
+		return false
+	}
+	destinationRules := istionetwork.DestinationRuleList{}
+	if err := ctx.Client.List(ctx, &destinationRules, client.InNamespace(ctx.Namespace)); err != nil {
+		ctx.Log.Error(err, "failed to get destinationrules in namespace", "namespace", ctx.Namespace)
+
+		return false
+	}
+	for _, hostName := range ref.GetTargetHostNames() {
+		for _, dr := range destinationRules.Items { //nolint:gocritic //reason for readability
+			if !hostName.Match(dr.Spec.Host) {
+				continue
+			}
+			for _, subset := range dr.Spec.Subsets {
+				if label := findSubsetLabel(subset.Labels, targets[0].Labels); label != "" {
+					ctx.Log.Info("Detected subset label", "label", label, "destinationrule", dr.Name)
+					ref.SubsetLabel = label
+
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func findSubsetLabel(subsetLabels, podLabels map[string]string) string {
+	if len(subsetLabels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(subsetLabels))
+	for key, value := range subsetLabels {
+		if podLabels[key] != value {
+			return ""
+		}
+		keys = append(keys, key)
+	}
+	if _, found := subsetLabels[model.DefaultSubsetLabel]; found {
+		return model.DefaultSubsetLabel
+	}
+	sort.Strings(keys)
+
+	return keys[0]
+}
+
+func getTargetSubset(ctx model.SessionContext, namespace string, hostName model.HostName, subsetLabel, targetVersion string) (*istionetworkv1alpha3.Subset, error) {
 	destinationRules := istionetwork.DestinationRuleList{}
 	err := ctx.Client.List(ctx, &destinationRules, client.InNamespace(namespace))
 	if err != nil {
@@ -134,12 +194,12 @@ func getTargetSubset(ctx model.SessionContext, namespace string, hostName model.
 	for _, dr := range destinationRules.Items { //nolint:gocritic //reason for readability
 		if hostName.Match(dr.Spec.Host) {
 			for _, subset := range dr.Spec.Subsets {
-				if subset.Labels["version"] == targetVersion {
+				if subset.Labels[subsetLabel] == targetVersion {
 					return subset, nil
 				}
 			}
 		}
 	}
 
-	return nil, errors.NewWithDetails("failed finding subset with given host and version", "host", hostName.String(), "version", targetVersion, "namespace", namespace)
+	return nil, errors.NewWithDetails("failed finding subset with given host and version", "host", hostName.String(), "label", subsetLabel, "version", targetVersion, "namespace", namespace)
 }
@@ -164,6 +164,83 @@ var _ = Describe("Operations for istio DestinationRule kind", func() {
 			})
 		})
 
+		Context("custom subset label", func() {
+
+			BeforeEach(func() {
+				objects = append(objects, &istionetwork.DestinationRule{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "customer-track",
+						Namespace: "test",
+					},
+					Spec: istionetworkv1alpha3.DestinationRule{
+						Host: "customer-track",
+						Subsets: []*istionetworkv1alpha3.Subset{
+							{
+								Name: "stable",
+								Labels: map[string]string{
+									"track": "stable",
+								},
+							},
+						},
+					},
+				})
+			})
+
+			It("should create subset using configured label", func() {
+				ref := &model.Ref{
+					KindName:    model.ParseRefKindName("customer-stable"),
+					SubsetLabel: "track",
+					Targets: []model.LocatedResourceStatus{
+						model.NewLocatedResource("Deployment", "customer-stable", map[string]string{"track": "stable"}),
+						model.NewLocatedResource("Service", "customer-track", nil),
+					},
+				}
+				err := istio.DestinationRuleMutator(ctx, ref)
+				Expect(err).ToNot(HaveOccurred())
+
+				dr := get.DestinationRules("test", testclient.HasRefPredicate)
+				Expect(dr.Items).To(HaveLen(1))
+				Expect(dr.Items[0].Spec.Subsets[0].Labels).To(HaveKeyWithValue("track", ref.GetNewVersion(ctx.Name)))
+			})
+
+			It("should detect label from existing subsets", func() {
+				ref := &model.Ref{
+					KindName: model.ParseRefKindName("customer-stable"),
+					Targets: []model.LocatedResourceStatus{
+						model.NewLocatedResource("Deployment", "customer-stable", map[string]string{"app": "customer", "track": "stable"}),
+						model.NewLocatedResource("Service", "customer-track", nil),
+					},
+				}
+				Expect(istio.SubsetLabelLocator(ctx, ref)).To(BeTrue())
+				Expect(ref.SubsetLabel).To(Equal("track"))
+			})
+
+			It("should not override configured label", func() {
+				ref := &model.Ref{
+					KindName:    model.ParseRefKindName("customer-stable"),
+					SubsetLabel: "app.kubernetes.io/version",
+					Targets: []model.LocatedResourceStatus{
+						model.NewLocatedResource("Deployment", "customer-stable", map[string]string{"track": "stable"}),
+						model.NewLocatedResource("Service", "customer-track", nil),
+					},
+				}
+				Expect(istio.SubsetLabelLocator(ctx, ref)).To(BeFalse())
+				Expect(ref.SubsetLabel).To(Equal("app.kubernetes.io/version"))
+			})
+
+			It("should not detect label when no subset matches", func() {
+				ref := &model.Ref{
+					KindName: model.ParseRefKindName("customer-canary"),
+					Targets: []model.LocatedResourceStatus{
+						model.NewLocatedResource("Deployment", "customer-canary", map[string]string{"track": "canary"}),
+						model.NewLocatedResource("Service", "customer-track", nil),
+					},
+				}
+				Expect(istio.SubsetLabelLocator(ctx, ref)).To(BeFalse())
+				Expect(ref.GetSubsetLabel()).To(Equal(model.DefaultSubsetLabel))
+			})
+		})
+
 		Context("missing rule", func() {
 
 			It("should fail when no rules found", func() {
@@ -0,0 +1,92 @@
+package kubeconfig
+
+import (
+	"sync"
+
+	"emperror.dev/errors"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Selection defines which kube config, context and cluster are used to talk to the cluster.
+// Empty values fall back to the defaults of kubectl, i.e. KUBECONFIG env variable or ~/.kube/config and its current context.
+type Selection struct {
+	Kubeconfig string // path to the kube config file
+	Context    string // name of the context to use
+	Cluster    string // name of the cluster to use
+}
+
+var (
+	mu       sync.RWMutex
+	selected Selection
+)
+
+// Select sets the kube config, context and cluster used by all the clients created afterwards.
+func Select(selection Selection) {
+	mu.Lock()
+	defer mu.Unlock()
+	selected = selection
+}
+
+// Selected returns the current selection.
+func Selected() Selection {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return selected
+}
+
+// Key identifies the selection, so clients created for different ones are not mixed up.
+func (s Selection) Key() string {
+	return s.Kubeconfig + "|" + s.Context + "|" + s.Cluster
+}
+
+// ClientConfig creates the loader of the kube config honoring the current selection.
+func ClientConfig() clientcmd.ClientConfig {
+	s := Selected()
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = s.Kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: s.Context}
+	overrides.Context.Cluster = s.Cluster
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+}
+
+// Load returns the rest config for the current selection together with the namespace of its context.
+func Load() (*rest.Config, string, error) {
+	kubeCfg := ClientConfig()
+	restCfg, err := kubeCfg.ClientConfig()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to get kube config")
+	}
+	namespace, _, err := kubeCfg.Namespace()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to get current namespace")
+	}
+
+	return restCfg, namespace, nil
+}
+
+// Env returns environment variables pointing the tools started by ike, such as telepresence, to the selected kube config.
+func Env() []string {
+	if s := Selected(); s.Kubeconfig != "" {
+		return []string{"KUBECONFIG=" + s.Kubeconfig}
+	}
+
+	return nil
+}
+
+// Flags returns kubectl style flags of the selected context and cluster for the tools started by ike
+// which support them.
+func Flags(withCluster bool) []string {
+	s := Selected()
+	var flags []string
+	if s.Context != "" {
+		flags = append(flags, "--context", s.Context)
+	}
+	if withCluster && s.Cluster != "" {
+		flags = append(flags, "--cluster", s.Cluster)
+	}
+
+	return flags
+}
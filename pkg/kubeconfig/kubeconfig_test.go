@@ -0,0 +1,96 @@
+package kubeconfig_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/maistra/istio-workspace/pkg/kubeconfig"
+	. "github.com/maistra/istio-workspace/test"
+)
+
+const twoClusters = `apiVersion: v1
+kind: Config
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com:6443
+- name: prod
+  cluster:
+    server: https://prod.example.com:6443
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    namespace: team-dev
+- name: prod
+  context:
+    cluster: prod
+    namespace: team-prod
+current-context: dev
+`
+
+var _ = Describe("Selection of kube config", func() {
+
+	var kubeconfigPath string
+
+	BeforeEach(func() {
+		kubeconfigPath = TmpFile(GinkgoT(), "kubeconfig.yaml", twoClusters).Name()
+	})
+
+	AfterEach(func() {
+		kubeconfig.Select(kubeconfig.Selection{})
+		CleanUpTmpFiles(GinkgoT())
+	})
+
+	It("should use current context of the selected kube config", func() {
+		kubeconfig.Select(kubeconfig.Selection{Kubeconfig: kubeconfigPath})
+
+		restCfg, namespace, err := kubeconfig.Load()
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(restCfg.Host).To(Equal("https://dev.example.com:6443"))
+		Expect(namespace).To(Equal("team-dev"))
+	})
+
+	It("should use selected context", func() {
+		kubeconfig.Select(kubeconfig.Selection{Kubeconfig: kubeconfigPath, Context: "prod"})
+
+		restCfg, namespace, err := kubeconfig.Load()
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(restCfg.Host).To(Equal("https://prod.example.com:6443"))
+		Expect(namespace).To(Equal("team-prod"))
+	})
+
+	It("should use selected cluster within the context", func() {
+		kubeconfig.Select(kubeconfig.Selection{Kubeconfig: kubeconfigPath, Context: "dev", Cluster: "prod"})
+
+		restCfg, namespace, err := kubeconfig.Load()
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(restCfg.Host).To(Equal("https://prod.example.com:6443"))
+		Expect(namespace).To(Equal("team-dev"))
+	})
+
+	It("should fail on unknown context", func() {
+		kubeconfig.Select(kubeconfig.Selection{Kubeconfig: kubeconfigPath, Context: "staging"})
+
+		_, _, err := kubeconfig.Load()
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`context "staging" does not exist`))
+	})
+
+	It("should pass selection to external tools", func() {
+		kubeconfig.Select(kubeconfig.Selection{Kubeconfig: kubeconfigPath, Context: "prod", Cluster: "prod"})
+
+		Expect(kubeconfig.Env()).To(ConsistOf("KUBECONFIG=" + kubeconfigPath))
+		Expect(kubeconfig.Flags(false)).To(Equal([]string{"--context", "prod"}))
+		Expect(kubeconfig.Flags(true)).To(Equal([]string{"--context", "prod", "--cluster", "prod"}))
+	})
+
+	It("should not pass anything to external tools by default", func() {
+		Expect(kubeconfig.Env()).To(BeEmpty())
+		Expect(kubeconfig.Flags(true)).To(BeEmpty())
+	})
+})
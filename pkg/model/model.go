@@ -15,6 +15,9 @@ import (
 const (
 	// StrategyExisting holds the name of the existing strategy.
 	StrategyExisting = "existing"
+
+	// DefaultSubsetLabel holds the name of the pod label used to identify version subsets if nothing else is configured or detected.
+	DefaultSubsetLabel = "version"
 )
 
 // SessionContext holds the context for a single session object, giving access to key things like REST Client and target Namespace.
@@ -49,6 +52,7 @@ type Ref struct {
 	Namespace        string
 	Strategy         string
 	Args             map[string]string
+	SubsetLabel      string
 	Targets          []LocatedResourceStatus
 	ResourceStatuses []ResourceStatus
 }
@@ -186,11 +190,20 @@ func (r *Ref) GetTargetHostNames() []HostName {
 	return hosts
 }
 
+// GetSubsetLabel returns the name of the pod label used to identify version subsets. Defaults to DefaultSubsetLabel.
+func (r *Ref) GetSubsetLabel() string {
+	if r.SubsetLabel == "" {
+		return DefaultSubsetLabel
+	}
+
+	return r.SubsetLabel
+}
+
 // GetVersion returns the existing version name.
 func (r *Ref) GetVersion() string {
 	target := r.GetTargets(AnyKind("Deployment", "DeploymentConfig"))
 	if len(target) == 1 {
-		if val, ok := target[0].Labels["version"]; ok {
+		if val, ok := target[0].Labels[r.GetSubsetLabel()]; ok {
 			return val
 		}
 	}
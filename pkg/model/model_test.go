@@ -57,4 +57,38 @@ var _ = Describe("Operations for model object", func() {
 		})
 
 	})
+
+	Context("subset label", func() {
+
+		It("should default to version label", func() {
+			ref := model.Ref{
+				Targets: []model.LocatedResourceStatus{
+					model.NewLocatedResource("Deployment", "x", map[string]string{"version": "v1"}),
+				},
+			}
+			Expect(ref.GetSubsetLabel()).To(Equal(model.DefaultSubsetLabel))
+			Expect(ref.GetVersion()).To(Equal("v1"))
+		})
+
+		It("should use configured label to find version", func() {
+			ref := model.Ref{
+				SubsetLabel: "app.kubernetes.io/version",
+				Targets: []model.LocatedResourceStatus{
+					model.NewLocatedResource("Deployment", "x", map[string]string{"version": "v1", "app.kubernetes.io/version": "v2"}),
+				},
+			}
+			Expect(ref.GetVersion()).To(Equal("v2"))
+		})
+
+		It("should not fall back to version label when configured label is missing", func() {
+			ref := model.Ref{
+				SubsetLabel: "track",
+				Targets: []model.LocatedResourceStatus{
+					model.NewLocatedResource("Deployment", "x", map[string]string{"version": "v1"}),
+				},
+			}
+			Expect(ref.GetVersion()).To(Equal("unknown"))
+		})
+
+	})
 })
@@ -148,7 +148,13 @@ func cloneDeployment(engine template.Engine, deployment *appsv1.DeploymentConfig
 		return nil, errors.Wrap(err, "failed reading DeploymentConfig json")
 	}
 
-	modifiedDeployment, err := engine.Run(ref.Strategy, originalDeployment, version, ref.Args)
+	variables := map[string]string{}
+	for k, v := range ref.Args {
+		variables[k] = v
+	}
+	variables[template.SubsetLabelVariable] = ref.GetSubsetLabel()
+
+	modifiedDeployment, err := engine.Run(ref.Strategy, originalDeployment, version, variables)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to modify DeploymentConfig")
 	}
@@ -0,0 +1,76 @@
+package proxy
+
+import (
+	"emperror.dev/errors"
+	gocmd "github.com/go-cmd/cmd"
+
+	"github.com/maistra/istio-workspace/pkg/shell"
+)
+
+const (
+	mirrordBackendName = "mirrord"
+	mirrordStrategy    = "mirrord"
+	// MirrordBinaryName is a name of mirrord binary we assume be available on the $PATH.
+	MirrordBinaryName  = "mirrord"
+	mirrordInstallHint = "Head over to https://mirrord.dev/docs/overview/quick-start/ for installation instructions.\n"
+)
+
+var errorMirrordNotAvailable = errors.Errorf("unable to find %s on your $PATH", MirrordBinaryName)
+
+// Mirrord proxies the traffic using mirrord. The local process steals the incoming traffic of the clone,
+// which keeps running the original container.
+type Mirrord struct {
+	process
+}
+
+var _ Backend = &Mirrord{}
+
+// NewMirrord creates mirrord backend.
+func NewMirrord() Backend {
+	return &Mirrord{}
+}
+
+func (m *Mirrord) Name() string {
+	return mirrordBackendName
+}
+
+func (m *Mirrord) Available() error {
+	if !shell.BinaryExists(MirrordBinaryName, mirrordInstallHint) {
+		return errorMirrordNotAvailable
+	}
+
+	return nil
+}
+
+func (m *Mirrord) Strategy() (string, map[string]string, error) {
+	return mirrordStrategy, map[string]string{}, nil
+}
+
+// Start runs the local process through mirrord targeting the clone. As mirrord hooks into the process directly,
+// it is listening on the same ports as the original container and exposed ports are not used.
+func (m *Mirrord) Start(target Target, done chan gocmd.Status) error {
+	if len(target.Ports) > 0 {
+		logger().Info("mirrord steals the traffic of the ports the local process is listening on, exposed ports are ignored")
+	}
+	m.start(target, done, MirrordBinaryName, mirrordArgs(target)...)
+
+	return nil
+}
+
+func (m *Mirrord) Stop() error {
+	return m.stop()
+}
+
+func (m *Mirrord) Healthy() error {
+	return m.healthy()
+}
+
+func mirrordArgs(target Target) []string {
+	args := []string{"exec", "--target", "deployment/" + target.Deployment, "--steal"}
+	if target.Namespace != "" {
+		args = append(args, "--target-namespace", target.Namespace)
+	}
+	args = append(args, "--")
+
+	return append(args, target.Command...)
+}
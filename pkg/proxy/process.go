@@ -0,0 +1,73 @@
+package proxy
+
+import (
+	"strings"
+	"sync"
+
+	"emperror.dev/errors"
+	gocmd "github.com/go-cmd/cmd"
+
+	"github.com/maistra/istio-workspace/pkg/kubeconfig"
+	"github.com/maistra/istio-workspace/pkg/shell"
+)
+
+// process keeps track of the child process started by the backend.
+type process struct {
+	mu  sync.Mutex
+	cmd *gocmd.Cmd
+}
+
+// start runs the command in the background, redirecting its streams to the ones of the target.
+func (p *process) start(target Target, done chan gocmd.Status, name string, args ...string) {
+	cmd := gocmd.NewCmdOptions(shell.StreamOutput, name, args...)
+	cmd.Dir = target.Dir
+	cmd.Env = append(kubeconfig.Env(), target.Env...)
+	shell.RedirectStreams(cmd, target.Stdout, target.Stderr)
+	shell.ShutdownHookForChildCommand(cmd)
+
+	p.mu.Lock()
+	p.cmd = cmd
+	p.mu.Unlock()
+
+	go shell.Start(cmd, done)
+}
+
+func (p *process) stop() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cmd == nil {
+		return nil
+	}
+
+	return errors.Wrap(p.cmd.Stop(), "failed stopping process")
+}
+
+func (p *process) healthy() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cmd == nil {
+		return errors.New("process not started")
+	}
+	if status := p.cmd.Status(); status.StopTs > 0 || status.Error != nil {
+		return errors.Errorf("%s exited with code %d", p.cmd.Name, status.Exit)
+	}
+
+	return nil
+}
+
+// run executes the command and waits for it to finish.
+func run(target Target, name string, args ...string) error {
+	done := make(chan gocmd.Status, 1)
+	defer close(done)
+
+	p := &process{}
+	p.start(target, done, name, args...)
+	status := <-done
+	if status.Error == nil && status.Exit != 0 {
+		return errors.Errorf("%s %s exited with code %d", name, strings.Join(args, " "), status.Exit)
+	}
+
+	return errors.Wrapf(status.Error, "failed executing %s", name)
+}
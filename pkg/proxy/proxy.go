@@ -0,0 +1,82 @@
+package proxy
+
+import (
+	"io"
+	"sort"
+
+	"emperror.dev/errors"
+	gocmd "github.com/go-cmd/cmd"
+	"github.com/go-logr/logr"
+
+	istiov1alpha1 "github.com/maistra/istio-workspace/api/maistra/v1alpha1"
+	"github.com/maistra/istio-workspace/pkg/log"
+)
+
+var logger = func() logr.Logger {
+	return log.Log.WithValues("type", "proxy")
+}
+
+// DefaultBackend is the name of the Backend used when none is specified.
+const DefaultBackend = telepresenceBackendName
+
+// Backend connects the cloned deployment with the process running on the developer machine.
+type Backend interface {
+	// Name of the backend as used in --proxy flag.
+	Name() string
+	// Available verifies all the prerequisites of the backend, e.g. required binaries, are in place.
+	Available() error
+	// Strategy returns the strategy and its arguments used to prepare the cloned deployment.
+	Strategy() (string, map[string]string, error)
+	// Start starts proxying the traffic between the clone and the local process. Final status of the
+	// local process is sent to the done channel.
+	Start(target Target, done chan gocmd.Status) error
+	// Stop terminates the proxy together with the local process.
+	Stop() error
+	// Healthy returns an error if the proxy is not running.
+	Healthy() error
+}
+
+// Target describes the cloned deployment and the local process which should be connected.
+type Target struct {
+	Namespace         string               // namespace of the clone, empty for the one of the current context
+	Deployment        string               // name of the cloned deployment
+	Ports             []string             // ports to expose in format local[:remote]
+	Route             *istiov1alpha1.Route // route of the session leading to the clone
+	StrategyArgs      map[string]string    // arguments the clone has been prepared with
+	Method            string               // backend specific proxying method, e.g. inject-tcp for Telepresence 1
+	PersonalIntercept bool                 // only intercept requests matching the route, if supported
+	Command           []string             // command to run locally, starting with the executable
+	Env               []string             // additional environment variables of the local process in form of name=value
+	Dir               string               // working directory of the local process
+	Sync              SyncOptions          // synchronization of local files, used by sync backend only
+	Stdout            io.Writer
+	Stderr            io.Writer
+}
+
+var backends = map[string]func() Backend{
+	telepresenceBackendName: NewTelepresence,
+	mirrordBackendName:      NewMirrord,
+	tunnelBackendName:       NewTunnel,
+	SyncBackendName:         NewSync,
+}
+
+// Lookup creates a new instance of the Backend registered under the given name.
+func Lookup(name string) (Backend, error) {
+	create, found := backends[name]
+	if !found {
+		return nil, errors.Errorf("unknown proxy %s, expected one of %v", name, Names())
+	}
+
+	return create(), nil
+}
+
+// Names returns sorted names of all known backends.
+func Names() []string {
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
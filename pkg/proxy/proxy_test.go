@@ -0,0 +1,161 @@
+package proxy_test
+
+import (
+	"path"
+	"time"
+
+	gocmd "github.com/go-cmd/cmd"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	istiov1alpha1 "github.com/maistra/istio-workspace/api/maistra/v1alpha1"
+	"github.com/maistra/istio-workspace/pkg/proxy"
+	. "github.com/maistra/istio-workspace/test"
+	"github.com/maistra/istio-workspace/test/shell"
+)
+
+var _ = Describe("Local proxy backends", func() {
+
+	Context("lookup", func() {
+
+		It("should list all known backends", func() {
+			Expect(proxy.Names()).To(Equal([]string{"mirrord", "sync", "telepresence", "tunnel"}))
+		})
+
+		It("should fail for unknown backend", func() {
+			_, err := proxy.Lookup("kubefwd")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unknown proxy kubefwd"))
+		})
+
+		It("should default to telepresence", func() {
+			backend, err := proxy.Lookup(proxy.DefaultBackend)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(backend.Name()).To(Equal("telepresence"))
+		})
+	})
+
+	Context("telepresence", func() {
+
+		var restoreEnvVars func()
+		AfterEach(func() {
+			restoreEnvVars()
+		})
+
+		It("should use telepresence strategy for legacy version", func() {
+			restoreEnvVars = TemporaryEnvVars("TELEPRESENCE_VERSION", "0.109")
+
+			strategy, args, err := proxy.NewTelepresence().Strategy()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(strategy).To(Equal("telepresence"))
+			Expect(args).To(HaveKeyWithValue("version", "0.109"))
+		})
+
+		It("should use telepresence2 strategy for Telepresence 2", func() {
+			restoreEnvVars = TemporaryEnvVars("TELEPRESENCE_VERSION", "Client: v2.4.5 (api v3)")
+
+			strategy, _, err := proxy.NewTelepresence().Strategy()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(strategy).To(Equal("telepresence2"))
+		})
+
+		It("should leave the intercept and disconnect Telepresence 2 when stopped", func() {
+			tmpPath := NewTmpPath()
+			tmpPath.SetPath(path.Dir(shell.TpSleepBin))
+			defer tmpPath.Restore()
+			restoreEnvVars = TemporaryEnvVars("TELEPRESENCE_VERSION", "Client: v2.4.5 (api v3)")
+
+			out := gbytes.NewBuffer()
+			done := make(chan gocmd.Status, 1)
+			backend := proxy.NewTelepresence()
+			Expect(backend.Start(proxy.Target{
+				Namespace:    "my-project",
+				Deployment:   "ratings-v1-vcvck",
+				Command:      []string{"ike", "execute", "--run", "java -jar ratings.jar"},
+				StrategyArgs: map[string]string{"version": "2.4.5"},
+				Stdout:       out,
+				Stderr:       out,
+			}, done)).To(Succeed())
+			Eventually(out).Should(gbytes.Say("intercept ratings-v1-vcvck --namespace my-project --"))
+
+			Expect(backend.Stop()).To(Succeed())
+			Eventually(out).Should(gbytes.Say("leave ratings-v1-vcvck-my-project"))
+			Eventually(out).Should(gbytes.Say("quit"))
+		})
+	})
+
+	Context("mirrord", func() {
+
+		tmpPath := NewTmpPath()
+		BeforeEach(func() {
+			tmpPath.SetPath(path.Dir(shell.MirrordBin))
+		})
+		AfterEach(tmpPath.Restore)
+
+		It("should keep the original container in the clone", func() {
+			strategy, _, err := proxy.NewMirrord().Strategy()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(strategy).To(Equal("mirrord"))
+		})
+
+		It("should fail when mirrord binary is not on $PATH", func() {
+			tmpPath.SetPath()
+
+			err := proxy.NewMirrord().Available()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("unable to find mirrord on your $PATH"))
+		})
+
+		It("should steal the traffic of the clone while running the command", func() {
+			out := gbytes.NewBuffer()
+			done := make(chan gocmd.Status, 1)
+			backend := proxy.NewMirrord()
+			Expect(backend.Available()).To(Succeed())
+
+			Expect(backend.Start(proxy.Target{
+				Namespace:  "my-project",
+				Deployment: "ratings-v1-vcvck",
+				Route:      &istiov1alpha1.Route{Type: "header", Name: "x-test", Value: "vcvck"},
+				Command:    []string{"ike", "execute", "--run", "java -jar ratings.jar"},
+				Stdout:     out,
+				Stderr:     out,
+			}, done)).To(Succeed())
+			Eventually(backend.Healthy).Should(Succeed())
+
+			var status gocmd.Status
+			Eventually(done, 5*time.Second).Should(Receive(&status))
+			Expect(status.Exit).To(Equal(0))
+			Expect(backend.Healthy()).ToNot(Succeed())
+			Eventually(out).Should(gbytes.Say(
+				"exec --target deployment/ratings-v1-vcvck --steal --target-namespace my-project -- ike execute --run java -jar ratings.jar"))
+		})
+	})
+
+	Context("tunnel", func() {
+
+		It("should run agent from the image matching ike version", func() {
+			strategy, args, err := proxy.NewTunnel().Strategy()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(strategy).To(Equal("tunnel"))
+			Expect(args).To(HaveKeyWithValue("image", ContainSubstring("quay.io/maistra/istio-workspace:")))
+			Expect(args).To(HaveKeyWithValue("controlPort", "10101"))
+		})
+
+		It("should generate new token for every session", func() {
+			_, args, err := proxy.NewTunnel().Strategy()
+			Expect(err).ToNot(HaveOccurred())
+			_, otherArgs, err := proxy.NewTunnel().Strategy()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(args["token"]).To(HaveLen(32))
+			Expect(args["token"]).ToNot(Equal(otherArgs["token"]))
+		})
+
+		It("should require ports to be exposed", func() {
+			err := proxy.NewTunnel().Start(proxy.Target{Deployment: "ratings-v1-vcvck", Command: []string{"ike"}}, make(chan gocmd.Status, 1))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("at least one port"))
+		})
+	})
+})
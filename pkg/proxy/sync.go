@@ -0,0 +1,391 @@
+package proxy
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/fsnotify/fsnotify"
+	gocmd "github.com/go-cmd/cmd"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/maistra/istio-workspace/pkg/kubeconfig"
+	"github.com/maistra/istio-workspace/pkg/template"
+	"github.com/maistra/istio-workspace/pkg/watch"
+)
+
+const (
+	// SyncBackendName is the name of the backend copying local changes into the clone instead of proxying the traffic.
+	SyncBackendName = "sync"
+	syncStrategy    = "sync"
+	syncPidFile     = "/tmp/ike-sync.pid"
+	istioProxy      = "istio-proxy"
+)
+
+// SyncOptions defines how the local changes are synchronized into the clone.
+type SyncOptions struct {
+	RemoteDir string   // directory in the container the files are copied to, relative paths start in its working directory
+	Restart   string   // shell command restarting the application in the container once files are copied
+	Paths     []string // local paths to watch, relative to Target.Dir
+	Exclude   []string // patterns of the files which should not be synchronized
+	Interval  int64    // how often (in ms) the changes are synchronized
+	Poll      bool     // detect changes by scanning the paths instead of relying on file system notifications
+}
+
+// Exec runs the command in the container of the pod. Stdin is streamed to the command when not nil.
+var Exec = execInPod
+
+// FileSync keeps the clone running the original image and copies the local changes into its container,
+// restarting the application afterwards.
+type FileSync struct {
+	mu       sync.Mutex
+	watch    *watch.Watch
+	cancel   context.CancelFunc
+	signals  chan os.Signal
+	finished sync.Once
+	lastErr  error
+}
+
+var _ Backend = &FileSync{}
+
+// NewSync creates the backend synchronizing local files into the clone.
+func NewSync() Backend {
+	return &FileSync{}
+}
+
+func (s *FileSync) Name() string {
+	return SyncBackendName
+}
+
+// Available checks if the cluster can be reached using current kube config, no other tools are needed.
+func (s *FileSync) Available() error {
+	_, _, err := kubeconfig.Load()
+
+	return err
+}
+
+// Strategy returns sync strategy. The command run in the clone is defined by the command variable.
+func (s *FileSync) Strategy() (string, map[string]string, error) {
+	return syncStrategy, map[string]string{
+		"pidFile": syncPidFile,
+	}, nil
+}
+
+// Start waits for the clone, follows its logs and synchronizes the changes of the watched paths until stopped
+// or interrupted.
+func (s *FileSync) Start(target Target, done chan gocmd.Status) error {
+	c, restCfg, namespace, err := ClusterClient()
+	if err != nil {
+		return err
+	}
+	if target.Namespace != "" {
+		namespace = target.Namespace
+	}
+
+	podName, err := WaitForPod(c, namespace, target.Deployment)
+	if err != nil {
+		return err
+	}
+	pod, err := c.CoreV1().Pods(namespace).Get(context.Background(), podName, metav1.GetOptions{})
+	if err != nil {
+		return errors.WrapWithDetails(err, "failed getting pod", "pod", podName)
+	}
+	container, err := syncContainer(pod, target.StrategyArgs[template.ContainerVariable])
+	if err != nil {
+		return err
+	}
+
+	dir, err := filepath.Abs(target.Dir)
+	if err != nil {
+		return errors.WrapWithDetails(err, "failed resolving directory", "dir", target.Dir)
+	}
+	paths := make([]string, 0, len(target.Sync.Paths))
+	for _, path := range target.Sync.Paths {
+		paths = append(paths, filepath.Join(dir, path))
+	}
+	if len(paths) == 0 {
+		paths = append(paths, dir)
+	}
+
+	remoteDir := target.Sync.RemoteDir
+	if remoteDir == "" {
+		remoteDir = "."
+	}
+
+	execInContainer := func(command []string, stdin io.Reader) error {
+		return Exec(restCfg, c, namespace, podName, container, command, stdin, target.Stdout, target.Stderr)
+	}
+	handler := s.handler(dir, remoteDir, restartCommand(target), execInContainer)
+	builder := watch.CreateWatch(target.Sync.Interval)
+	if target.Sync.Poll {
+		builder = builder.Polling(watch.DefaultPollInterval)
+	}
+	w, err := builder.
+		WithHandlers(handler).
+		Excluding(target.Sync.Exclude...).
+		OnPaths(paths...)
+	if err != nil {
+		return errors.WrapIf(err, "failed watching for changes")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	s.mu.Lock()
+	s.watch = w
+	s.cancel = cancel
+	s.signals = signals
+	s.mu.Unlock()
+
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			s.finish(done, gocmd.Status{Complete: true, StopTs: time.Now().UnixNano()})
+		case <-ctx.Done():
+		}
+	}()
+	go followLogs(ctx, c, namespace, podName, container, target.Stdout)
+
+	w.Start()
+	logger().Info("synchronizing local changes", "pod", podName, "container", container, "dir", remoteDir)
+
+	return nil
+}
+
+func (s *FileSync) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.signals != nil {
+		signal.Stop(s.signals)
+		s.signals = nil
+	}
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+	if s.watch != nil {
+		s.watch.Close()
+		s.watch = nil
+	}
+
+	return nil
+}
+
+// Healthy returns an error if synchronization is not running or the last one failed.
+func (s *FileSync) Healthy() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.watch == nil {
+		return errors.New("synchronization not started")
+	}
+
+	return s.lastErr
+}
+
+func (s *FileSync) finish(done chan gocmd.Status, status gocmd.Status) {
+	s.finished.Do(func() {
+		done <- status
+	})
+}
+
+// handler copies changed files into the container, removes the deleted ones and restarts the application.
+func (s *FileSync) handler(dir, remoteDir, restart string, exec func(command []string, stdin io.Reader) error) watch.Handler {
+	return func(events []fsnotify.Event) error {
+		err := synchronize(dir, remoteDir, restart, events, exec)
+		s.mu.Lock()
+		s.lastErr = err
+		s.mu.Unlock()
+
+		return err
+	}
+}
+
+func synchronize(dir, remoteDir, restart string, events []fsnotify.Event, exec func(command []string, stdin io.Reader) error) error {
+	var changed, removed []string
+	for _, event := range events {
+		rel, err := filepath.Rel(dir, event.Name)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		if _, err := os.Lstat(event.Name); os.IsNotExist(err) {
+			removed = append(removed, filepath.ToSlash(rel))
+		} else {
+			changed = append(changed, rel)
+		}
+	}
+
+	var commands []string
+	var archive *bytes.Buffer
+	if len(changed) > 0 {
+		var err error
+		if archive, err = tarFiles(dir, changed); err != nil {
+			return err
+		}
+		commands = append(commands, "tar -xmf - -C "+shellQuote(remoteDir))
+	}
+	if len(removed) > 0 {
+		quoted := make([]string, 0, len(removed))
+		for _, path := range removed {
+			quoted = append(quoted, shellQuote(path))
+		}
+		commands = append(commands, "cd "+shellQuote(remoteDir)+" && rm -rf -- "+strings.Join(quoted, " "))
+	}
+	if len(commands) == 0 {
+		return nil
+	}
+
+	var stdin io.Reader
+	if archive != nil {
+		stdin = archive
+	}
+	if err := exec([]string{"sh", "-c", strings.Join(commands, " && ")}, stdin); err != nil {
+		return errors.WrapIf(err, "failed copying files")
+	}
+	logger().Info("synchronized changes", "changed", len(changed), "removed", len(removed))
+
+	if restart == "" {
+		return nil
+	}
+
+	return errors.WrapIfWithDetails(exec([]string{"sh", "-c", restart}, nil), "failed restarting application", "command", restart)
+}
+
+// tarFiles archives the files under the paths relative to dir. Directories are archived without their content.
+func tarFiles(dir string, paths []string) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, path := range paths {
+		if err := addToTar(tw, dir, path); err != nil {
+			return nil, err
+		}
+	}
+
+	return &buf, errors.Wrap(tw.Close(), "failed closing archive")
+}
+
+func addToTar(tw *tar.Writer, dir, path string) error {
+	fullPath := filepath.Join(dir, path)
+	info, err := os.Lstat(fullPath)
+	if err != nil {
+		return errors.WrapWithDetails(err, "failed reading file", "path", fullPath)
+	}
+	var link string
+	if info.Mode()&os.ModeSymlink != 0 {
+		if link, err = os.Readlink(fullPath); err != nil {
+			return errors.WrapWithDetails(err, "failed reading link", "path", fullPath)
+		}
+	}
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return errors.WrapWithDetails(err, "failed creating archive header", "path", fullPath)
+	}
+	header.Name = filepath.ToSlash(path)
+	if err := tw.WriteHeader(header); err != nil {
+		return errors.WrapWithDetails(err, "failed writing archive header", "path", fullPath)
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return errors.WrapWithDetails(err, "failed opening file", "path", fullPath)
+	}
+	defer file.Close()
+	_, err = io.Copy(tw, file)
+
+	return errors.WrapWithDetails(err, "failed archiving file", "path", fullPath)
+}
+
+// restartCommand returns the command restarting the application. By default the process started by the sync
+// strategy is killed, so the loop wrapping it starts it again.
+func restartCommand(target Target) string {
+	if target.Sync.Restart != "" {
+		return target.Sync.Restart
+	}
+	if target.StrategyArgs["command"] == "" {
+		return ""
+	}
+	pidFile := target.StrategyArgs["pidFile"]
+	if pidFile == "" {
+		pidFile = syncPidFile
+	}
+
+	return "kill $(cat " + shellQuote(pidFile) + ")"
+}
+
+// syncContainer returns the container files are copied to. Unless specified, it's the one annotated as default
+// by the sync strategy or the first one which is not istio-proxy sidecar.
+func syncContainer(pod *corev1.Pod, name string) (string, error) {
+	if name != "" {
+		return name, nil
+	}
+	if annotated := pod.Annotations[template.DefaultContainerAnnotation]; annotated != "" {
+		return annotated, nil
+	}
+	for _, container := range pod.Spec.Containers {
+		if container.Name != istioProxy {
+			return container.Name, nil
+		}
+	}
+
+	return "", errors.Errorf("unable to find container to synchronize in pod %s", pod.Name)
+}
+
+func followLogs(ctx context.Context, c kubernetes.Interface, namespace, pod, container string, out io.Writer) {
+	if out == nil {
+		return
+	}
+	stream, err := c.CoreV1().Pods(namespace).GetLogs(pod, &corev1.PodLogOptions{Container: container, Follow: true}).Stream(ctx)
+	if err != nil {
+		logger().Error(err, "failed following logs", "pod", pod, "container", container)
+
+		return
+	}
+	defer stream.Close()
+	_, _ = io.Copy(out, stream)
+}
+
+func execInPod(restCfg *rest.Config, c kubernetes.Interface, namespace, pod, container string, command []string,
+	stdin io.Reader, stdout, stderr io.Writer) error {
+	req := c.CoreV1().RESTClient().Post().Resource("pods").Namespace(namespace).Name(pod).SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     stdin != nil,
+			Stdout:    stdout != nil,
+			Stderr:    stderr != nil,
+		}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(restCfg, http.MethodPost, req.URL())
+	if err != nil {
+		return errors.Wrap(err, "failed creating executor")
+	}
+
+	return errors.WrapIfWithDetails(executor.Stream(remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: stderr,
+	}), "failed executing command", "pod", pod, "container", container)
+}
+
+// shellQuote quotes the value to be used as a single word of a shell command.
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,137 @@
+package proxy_test
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	gocmd "github.com/go-cmd/cmd"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	"github.com/maistra/istio-workspace/pkg/proxy"
+)
+
+type execution struct {
+	container string
+	command   string
+	files     map[string]string
+}
+
+var _ = Describe("File synchronization", func() {
+
+	var (
+		dir            string
+		mu             sync.Mutex
+		executions     []execution
+		backend        proxy.Backend
+		originalClient = proxy.ClusterClient
+		originalExec   = proxy.Exec
+	)
+
+	executed := func() []execution {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return append([]execution{}, executions...)
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "ike-sync")
+		Expect(err).ToNot(HaveOccurred())
+		executions = nil
+
+		pod := clonedPod("ratings-v1-vcvck-7d4b9c", corev1.PodRunning)
+		pod.Spec.Containers = []corev1.Container{{Name: "istio-proxy"}, {Name: "ratings"}}
+		proxy.ClusterClient = func() (kubernetes.Interface, *rest.Config, string, error) {
+			return fake.NewSimpleClientset(clonedDeployment(), pod), &rest.Config{}, "test", nil
+		}
+		proxy.Exec = func(_ *rest.Config, _ kubernetes.Interface, _, _, container string, command []string, stdin io.Reader, _, _ io.Writer) error {
+			e := execution{container: container, command: strings.Join(command, " "), files: map[string]string{}}
+			if stdin != nil {
+				archive := tar.NewReader(stdin)
+				for header, err := archive.Next(); err == nil; header, err = archive.Next() {
+					content, _ := ioutil.ReadAll(archive)
+					e.files[header.Name] = string(content)
+				}
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			executions = append(executions, e)
+
+			return nil
+		}
+		backend = proxy.NewSync()
+	})
+
+	AfterEach(func() {
+		_ = backend.Stop()
+		proxy.ClusterClient = originalClient
+		proxy.Exec = originalExec
+		_ = os.RemoveAll(dir)
+	})
+
+	start := func(restart string) {
+		Expect(backend.Start(proxy.Target{
+			Deployment:   "ratings-v1-vcvck",
+			StrategyArgs: map[string]string{"command": "node server.js", "pidFile": "/tmp/ike-sync.pid"},
+			Dir:          dir,
+			Sync: proxy.SyncOptions{
+				RemoteDir: "/opt/app",
+				Restart:   restart,
+				Paths:     []string{"."},
+				Exclude:   []string{"*.log"},
+				Interval:  50,
+			},
+			Stdout: gbytes.NewBuffer(),
+			Stderr: gbytes.NewBuffer(),
+		}, make(chan gocmd.Status, 1))).To(Succeed())
+	}
+
+	It("should use sync strategy", func() {
+		strategy, args, err := backend.Strategy()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strategy).To(Equal("sync"))
+		Expect(args).To(HaveKeyWithValue("pidFile", "/tmp/ike-sync.pid"))
+	})
+
+	It("should copy changed file into the container and restart the command", func() {
+		start("")
+		Expect(ioutil.WriteFile(filepath.Join(dir, "server.js"), []byte("console.log('v2')"), 0600)).To(Succeed())
+
+		Eventually(executed, 5*time.Second).Should(HaveLen(2))
+		Expect(executed()[0].container).To(Equal("ratings"))
+		Expect(executed()[0].command).To(Equal("sh -c tar -xmf - -C '/opt/app'"))
+		Expect(executed()[0].files).To(HaveKeyWithValue("server.js", "console.log('v2')"))
+		Expect(executed()[1].command).To(Equal("sh -c kill $(cat '/tmp/ike-sync.pid')"))
+		Expect(backend.Healthy()).To(Succeed())
+	})
+
+	It("should remove deleted file from the container using custom restart command", func() {
+		Expect(ioutil.WriteFile(filepath.Join(dir, "obsolete.js"), []byte(""), 0600)).To(Succeed())
+		start("kill -HUP 1")
+		Expect(os.Remove(filepath.Join(dir, "obsolete.js"))).To(Succeed())
+
+		Eventually(executed, 5*time.Second).Should(HaveLen(2))
+		Expect(executed()[0].command).To(Equal("sh -c cd '/opt/app' && rm -rf -- 'obsolete.js'"))
+		Expect(executed()[1].command).To(Equal("sh -c kill -HUP 1"))
+	})
+
+	It("should not synchronize excluded files", func() {
+		start("")
+		Expect(ioutil.WriteFile(filepath.Join(dir, "debug.log"), []byte("trace"), 0600)).To(Succeed())
+
+		Consistently(executed, 500*time.Millisecond).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,159 @@
+package proxy
+
+import (
+	"emperror.dev/errors"
+	gocmd "github.com/go-cmd/cmd"
+
+	"github.com/maistra/istio-workspace/pkg/kubeconfig"
+	"github.com/maistra/istio-workspace/pkg/telepresence"
+)
+
+const (
+	telepresenceBackendName = "telepresence"
+	telepresenceStrategy    = "telepresence"
+	telepresence2Strategy   = "telepresence2"
+)
+
+var errorTpNotAvailable = errors.Errorf("unable to find %s on your $PATH", telepresence.BinaryName)
+
+// Telepresence proxies the traffic using Telepresence. Both legacy version and Telepresence 2 are supported.
+type Telepresence struct {
+	process
+	intercepted *Target // set only for Telepresence 2, which keeps the intercept and the daemon running on its own
+}
+
+var _ Backend = &Telepresence{}
+
+// NewTelepresence creates Telepresence backend.
+func NewTelepresence() Backend {
+	return &Telepresence{}
+}
+
+func (t *Telepresence) Name() string {
+	return telepresenceBackendName
+}
+
+func (t *Telepresence) Available() error {
+	if !telepresence.BinaryAvailable() {
+		return errorTpNotAvailable
+	}
+
+	return nil
+}
+
+// Strategy returns telepresence strategy for the legacy version, replacing the container with the Telepresence one,
+// and telepresence2 strategy keeping the original container for Telepresence 2.
+func (t *Telepresence) Strategy() (string, map[string]string, error) {
+	version, err := telepresence.GetVersion()
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed obtaining telepresence version")
+	}
+
+	strategy := telepresenceStrategy
+	if telepresence.IsV2(version) {
+		strategy = telepresence2Strategy
+	}
+
+	return strategy, map[string]string{"version": version}, nil
+}
+
+// Start runs telepresence against the clone. For Telepresence 2 the daemon is connected to the cluster first.
+// Selected kube context is passed to both versions, the cluster only to Telepresence 2 as the legacy one does not support it.
+func (t *Telepresence) Start(target Target, done chan gocmd.Status) error {
+	if !telepresence.IsV2(target.StrategyArgs["version"]) {
+		t.start(target, done, telepresence.BinaryName, telepresenceArgs(target)...)
+
+		return nil
+	}
+
+	arguments, err := telepresence2Args(target)
+	if err != nil {
+		return err
+	}
+	if err := run(target, telepresence.BinaryName, append([]string{"connect"}, kubeconfig.Flags(true)...)...); err != nil {
+		return errors.WrapIf(err, "failed connecting to the cluster")
+	}
+	t.intercepted = &target
+	t.start(target, done, telepresence.BinaryName, arguments...)
+
+	return nil
+}
+
+// Stop terminates the local process. For Telepresence 2 the intercept is removed if the process was still running
+// and the daemon is disconnected from the cluster, so nothing is left behind once the session is over.
+func (t *Telepresence) Stop() error {
+	running := t.healthy() == nil
+	errs := []error{t.stop()}
+	if t.intercepted == nil {
+		return errs[0]
+	}
+
+	target := *t.intercepted
+	t.intercepted = nil
+	if running {
+		errs = append(errs, errors.WrapIf(run(target, telepresence.BinaryName, "leave", interceptName(target)), "failed leaving intercept"))
+	}
+	errs = append(errs, errors.WrapIf(run(target, telepresence.BinaryName, "quit"), "failed disconnecting from the cluster"))
+
+	return errors.Combine(errs...)
+}
+
+func (t *Telepresence) Healthy() error {
+	return t.healthy()
+}
+
+// telepresenceArgs creates arguments for legacy Telepresence swapping the cloned deployment.
+func telepresenceArgs(target Target) []string {
+	tpArgs := kubeconfig.Flags(false)
+	if target.Namespace != "" {
+		tpArgs = append(tpArgs, "--namespace", target.Namespace)
+	}
+	tpArgs = append(tpArgs,
+		"--deployment", target.Deployment,
+		"--method", target.Method,
+	)
+	for _, port := range target.Ports {
+		tpArgs = append(tpArgs, "--expose", port)
+	}
+
+	tpArgs = append(tpArgs, "--run")
+
+	return append(tpArgs, target.Command...)
+}
+
+// interceptName follows the way Telepresence 2 names the intercept when no explicit name is given.
+func interceptName(target Target) string {
+	if target.Namespace != "" {
+		return target.Deployment + "-" + target.Namespace
+	}
+
+	return target.Deployment
+}
+
+// telepresence2Args creates arguments for Telepresence 2 intercept of the cloned deployment.
+// By default a global intercept is used as only the traffic matching the session route reaches the clone.
+func telepresence2Args(target Target) ([]string, error) {
+	tpArgs := []string{"intercept", target.Deployment}
+
+	if target.Namespace != "" {
+		tpArgs = append(tpArgs, "--namespace", target.Namespace)
+	}
+
+	if len(target.Ports) > 1 {
+		logger().Info("Telepresence 2 intercepts a single port only, remaining ones are ignored", "port", target.Ports[0])
+	}
+	if len(target.Ports) > 0 {
+		tpArgs = append(tpArgs, "--port", target.Ports[0])
+	}
+
+	if target.PersonalIntercept {
+		if target.Route == nil || target.Route.Type != "header" {
+			return nil, errors.New("personal intercept requires header based route")
+		}
+		tpArgs = append(tpArgs, "--http-header", target.Route.Name+"="+target.Route.Value)
+	}
+
+	tpArgs = append(tpArgs, "--")
+
+	return append(tpArgs, target.Command...), nil
+}
@@ -0,0 +1,288 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"emperror.dev/errors"
+	gocmd "github.com/go-cmd/cmd"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+
+	"github.com/maistra/istio-workspace/pkg/kubeconfig"
+	"github.com/maistra/istio-workspace/pkg/tunnel"
+	"github.com/maistra/istio-workspace/version"
+)
+
+const (
+	tunnelBackendName = "tunnel"
+	tunnelStrategy    = "tunnel"
+	agentImageName    = "quay.io/maistra/istio-workspace"
+	podReadyTimeout   = 3 * time.Minute
+)
+
+// Tunnel proxies the traffic through the built-in reverse tunnel. The clone runs ike agent forwarding its inbound
+// traffic over the port-forward opened to the agent. The local process reaches the cluster through SOCKS proxy
+// tunneled over the same port-forward.
+type Tunnel struct {
+	process
+	client    *tunnel.Client
+	socksAddr string
+	stopCh    chan struct{}
+	cancel    context.CancelFunc
+}
+
+// ClusterClient creates the client for the current kube config together with the namespace of the current context.
+var ClusterClient = func() (kubernetes.Interface, *rest.Config, string, error) {
+	restCfg, namespace, err := kubeconfig.Load()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	c, err := kubernetes.NewForConfig(restCfg)
+	if err != nil {
+		return nil, nil, "", errors.Wrap(err, "failed to create client set")
+	}
+
+	return c, restCfg, namespace, nil
+}
+
+// PortForward forwards the local port, random one if 0, to the given port of the pod and returns the local address.
+// Forwarding stops when stopCh is closed.
+var PortForward = forwardPort
+
+var _ Backend = &Tunnel{}
+
+// NewTunnel creates built-in tunnel backend.
+func NewTunnel() Backend {
+	return &Tunnel{}
+}
+
+func (t *Tunnel) Name() string {
+	return tunnelBackendName
+}
+
+// Available checks if the cluster can be reached using current kube config, no other tools are needed.
+func (t *Tunnel) Available() error {
+	_, _, err := kubeconfig.Load()
+
+	return err
+}
+
+// Strategy returns tunnel strategy running the agent from the image matching the version of ike.
+// The agent accepts control connections authenticated by the token generated for the session only.
+func (t *Tunnel) Strategy() (string, map[string]string, error) {
+	token, err := tunnel.NewToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return tunnelStrategy, map[string]string{
+		"image":       agentImageName + ":" + version.Version,
+		"controlPort": strconv.Itoa(tunnel.DefaultControlPort),
+		"token":       token,
+	}, nil
+}
+
+// Start waits for the agent in the clone, opens the port-forward to it and runs the local process.
+func (t *Tunnel) Start(target Target, done chan gocmd.Status) error {
+	if len(target.Command) == 0 {
+		return errors.New("no command to run")
+	}
+	if len(target.Ports) == 0 {
+		return errors.New("tunnel requires at least one port to be exposed")
+	}
+	mappings := make([]tunnel.Mapping, 0, len(target.Ports))
+	for _, port := range target.Ports {
+		m, err := tunnel.ParseMapping(port)
+		if err != nil {
+			return err
+		}
+		mappings = append(mappings, m)
+	}
+	token := target.StrategyArgs["token"]
+	if token == "" {
+		return errors.New("no token of the tunnel agent, the clone has to be prepared using tunnel strategy")
+	}
+	controlPort := tunnel.DefaultControlPort
+	if p, found := target.StrategyArgs["controlPort"]; found {
+		var err error
+		if controlPort, err = strconv.Atoi(p); err != nil {
+			return errors.WrapWithDetails(err, "invalid control port", "controlPort", p)
+		}
+	}
+
+	c, restCfg, namespace, err := ClusterClient()
+	if err != nil {
+		return err
+	}
+	if target.Namespace != "" {
+		namespace = target.Namespace
+	}
+
+	pod, err := WaitForPod(c, namespace, target.Deployment)
+	if err != nil {
+		return err
+	}
+
+	t.stopCh = make(chan struct{})
+	controlAddr, err := PortForward(restCfg, c, namespace, pod, 0, controlPort, t.stopCh)
+	if err != nil {
+		return err
+	}
+
+	socks, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return errors.Wrap(err, "failed listening for SOCKS connections")
+	}
+	t.socksAddr = socks.Addr().String()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+	t.client = tunnel.NewClient(controlAddr, token, mappings)
+	go func() {
+		if err := t.client.Run(ctx); err != nil {
+			logger().Error(err, "tunnel failed")
+		}
+	}()
+	go func() {
+		if err := t.client.ServeSOCKS(ctx, socks); err != nil {
+			logger().Error(err, "SOCKS proxy failed")
+		}
+	}()
+	logger().Info("cluster is reachable through SOCKS proxy", "address", t.socksAddr)
+
+	target.Env = append(target.Env, socksEnv(t.socksAddr)...)
+	t.start(target, done, target.Command[0], target.Command[1:]...)
+
+	return nil
+}
+
+// SOCKSAddress returns the address of the SOCKS proxy reaching the cluster, empty if not started.
+func (t *Tunnel) SOCKSAddress() string {
+	return t.socksAddr
+}
+
+// socksEnv configures the local process to reach the cluster through the SOCKS proxy, resolving the names in the cluster.
+func socksEnv(address string) []string {
+	proxyURL := "socks5h://" + address
+
+	return []string{"ALL_PROXY=" + proxyURL, "all_proxy=" + proxyURL}
+}
+
+func (t *Tunnel) Stop() error {
+	if t.cancel != nil {
+		t.cancel()
+	}
+	if t.stopCh != nil {
+		close(t.stopCh)
+		t.stopCh = nil
+	}
+
+	return t.stop()
+}
+
+func (t *Tunnel) Healthy() error {
+	if err := t.healthy(); err != nil {
+		return err
+	}
+	if t.client == nil {
+		return errors.New("tunnel not started")
+	}
+
+	return t.client.Healthy()
+}
+
+// WaitForPod returns the name of the running pod of the given Deployment or DeploymentConfig.
+func WaitForPod(c kubernetes.Interface, namespace, deployment string) (string, error) {
+	selector := labels.SelectorFromSet(map[string]string{"deploymentconfig": deployment})
+	d, err := c.AppsV1().Deployments(namespace).Get(context.Background(), deployment, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		if selector, err = metav1.LabelSelectorAsSelector(d.Spec.Selector); err != nil {
+			return "", errors.WrapWithDetails(err, "invalid selector", "deployment", deployment)
+		}
+	case !k8sErrors.IsNotFound(err):
+		return "", errors.WrapWithDetails(err, "failed getting deployment", "deployment", deployment)
+	}
+
+	var pod string
+	err = wait.PollImmediate(2*time.Second, podReadyTimeout, func() (bool, error) {
+		pods, err := c.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return false, errors.Wrap(err, "failed listing pods")
+		}
+		for i := range pods.Items {
+			if isReady(&pods.Items[i]) {
+				pod = pods.Items[i].Name
+
+				return true, nil
+			}
+		}
+
+		return false, nil
+	})
+
+	return pod, errors.WrapWithDetails(err, "failed waiting for pod", "deployment", deployment, "namespace", namespace)
+}
+
+func isReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func forwardPort(restCfg *rest.Config, c kubernetes.Interface, namespace, pod string, localPort, port int, stopCh chan struct{}) (string, error) {
+	transport, upgrader, err := spdy.RoundTripperFor(restCfg)
+	if err != nil {
+		return "", errors.Wrap(err, "failed creating round tripper")
+	}
+	req := c.CoreV1().RESTClient().Post().Resource("pods").Namespace(namespace).Name(pod).SubResource("portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+
+	readyCh := make(chan struct{})
+	fw, err := portforward.NewOnAddresses(dialer, []string{"localhost"}, []string{fmt.Sprintf("%d:%d", localPort, port)},
+		stopCh, readyCh, ioutil.Discard, ioutil.Discard)
+	if err != nil {
+		return "", errors.Wrap(err, "failed creating port-forward")
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fw.ForwardPorts()
+	}()
+
+	select {
+	case err := <-errCh:
+		return "", errors.WrapWithDetails(err, "port-forward failed", "pod", pod)
+	case <-readyCh:
+	}
+
+	ports, err := fw.GetPorts()
+	if err != nil {
+		return "", errors.WrapWithDetails(err, "failed getting forwarded port", "pod", pod)
+	}
+	if len(ports) == 0 {
+		return "", errors.Errorf("no port forwarded to pod %s", pod)
+	}
+
+	return fmt.Sprintf("localhost:%d", ports[0].Local), nil
+}
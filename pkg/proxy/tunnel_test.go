@@ -0,0 +1,195 @@
+package proxy_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	gocmd "github.com/go-cmd/cmd"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	xproxy "golang.org/x/net/proxy"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	"github.com/maistra/istio-workspace/pkg/proxy"
+	"github.com/maistra/istio-workspace/pkg/tunnel"
+)
+
+var _ = Describe("Built-in tunnel", func() {
+
+	var (
+		ctx                 context.Context
+		cancel              context.CancelFunc
+		control, local, svc net.Listener
+		forwardedPod        string
+		out                 *gbytes.Buffer
+		done                chan gocmd.Status
+		backend             *proxy.Tunnel
+		remotePort          int
+		originalClient      = proxy.ClusterClient
+		originalPortForward = proxy.PortForward
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+
+		// agent running in-process instead of the cloned pod
+		control = listen()
+		go func() {
+			defer GinkgoRecover()
+			Expect(tunnel.NewAgent("localhost", "s3cr3t").Serve(ctx, control)).To(Succeed())
+		}()
+
+		// local process and service in the cluster
+		local = listen()
+		go serve(local, "local")
+		svc = listen()
+		go serve(svc, "ratings")
+
+		proxy.ClusterClient = func() (kubernetes.Interface, *rest.Config, string, error) {
+			return fake.NewSimpleClientset(clonedDeployment(), clonedPod("ratings-v1-vcvck-7d4b9c", corev1.PodRunning)), &rest.Config{}, "test", nil
+		}
+		proxy.PortForward = func(_ *rest.Config, _ kubernetes.Interface, namespace, pod string, _, port int, _ chan struct{}) (string, error) {
+			forwardedPod = namespace + "/" + pod + ":" + fmt.Sprint(port)
+
+			return control.Addr().String(), nil
+		}
+
+		remotePort = freePort()
+		out = gbytes.NewBuffer()
+		done = make(chan gocmd.Status, 1)
+		backend = proxy.NewTunnel().(*proxy.Tunnel)
+	})
+
+	AfterEach(func() {
+		_ = backend.Stop()
+		cancel()
+		_ = local.Close()
+		_ = svc.Close()
+		proxy.ClusterClient = originalClient
+		proxy.PortForward = originalPortForward
+	})
+
+	start := func() {
+		Expect(backend.Start(proxy.Target{
+			Deployment:   "ratings-v1-vcvck",
+			Ports:        []string{fmt.Sprintf("%d:%d", port(local), remotePort)},
+			StrategyArgs: map[string]string{"controlPort": "10101", "token": "s3cr3t"},
+			Command:      []string{"sh", "-c", "echo proxy=$ALL_PROXY; sleep 5"},
+			Stdout:       out,
+			Stderr:       out,
+		}, done)).To(Succeed())
+	}
+
+	It("should forward to the agent of the running clone", func() {
+		start()
+
+		Expect(forwardedPod).To(Equal("test/ratings-v1-vcvck-7d4b9c:10101"))
+		Eventually(backend.Healthy, 5*time.Second).Should(Succeed())
+	})
+
+	It("should forward inbound traffic of the clone to the local process", func() {
+		start()
+		Eventually(backend.Healthy, 5*time.Second).Should(Succeed())
+
+		var greeting string
+		Eventually(func() (err error) {
+			greeting, err = readGreeting(fmt.Sprintf("localhost:%d", remotePort))
+
+			return err
+		}, 5*time.Second).Should(Succeed())
+		Expect(greeting).To(Equal("local\n"))
+	})
+
+	It("should reach the cluster through SOCKS proxy", func() {
+		start()
+		Eventually(out, 5*time.Second).Should(gbytes.Say("proxy=socks5h://" + backend.SOCKSAddress()))
+
+		dialer, err := xproxy.SOCKS5("tcp", backend.SOCKSAddress(), nil, xproxy.Direct)
+		Expect(err).ToNot(HaveOccurred())
+		conn, err := dialer.Dial("tcp", svc.Addr().String())
+		Expect(err).ToNot(HaveOccurred())
+		defer conn.Close()
+
+		Expect(bufio.NewReader(conn).ReadString('\n')).To(Equal("ratings\n"))
+	})
+
+	It("should fail SOCKS connection when agent can't reach the address", func() {
+		start()
+
+		dialer, err := xproxy.SOCKS5("tcp", backend.SOCKSAddress(), nil, xproxy.Direct)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = dialer.Dial("tcp", fmt.Sprintf("localhost:%d", freePort()))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+func clonedDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "ratings-v1-vcvck", Namespace: "test"},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "ratings", "version": "v1-vcvck"}},
+		},
+	}
+}
+
+func clonedPod(name string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test",
+			Labels:    map[string]string{"app": "ratings", "version": "v1-vcvck"},
+		},
+		Status: corev1.PodStatus{
+			Phase:      phase,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func listen() net.Listener {
+	l, err := net.Listen("tcp", "localhost:0")
+	Expect(err).ToNot(HaveOccurred())
+
+	return l
+}
+
+func serve(l net.Listener, greeting string) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		_, _ = fmt.Fprintln(conn, greeting)
+		_ = conn.Close()
+	}
+}
+
+func readGreeting(address string) (string, error) {
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	return bufio.NewReader(conn).ReadString('\n')
+}
+
+func port(l net.Listener) int {
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func freePort() int {
+	l := listen()
+	defer l.Close()
+
+	return port(l)
+}
@@ -0,0 +1,17 @@
+package routing
+
+// DefaultRouteHeaderName holds the name of the Header used to route traffic if no Route is provided.
+const DefaultRouteHeaderName = "x-workspace-route"
+
+// PropagatedHeaders are the headers services are expected to pass along to the downstream calls.
+// Tracing headers are propagated by most of the instrumented applications.
+var PropagatedHeaders = []string{
+	"x-request-id",
+	"x-b3-traceid",
+	"x-b3-spanid",
+	"x-b3-parentspanid",
+	"x-b3-sampled",
+	"x-b3-flags",
+	"x-ot-span-context",
+	DefaultRouteHeaderName,
+}
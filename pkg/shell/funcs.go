@@ -7,7 +7,9 @@ import (
 	"os/exec"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"emperror.dev/errors"
 	gocmd "github.com/go-cmd/cmd"
 	"github.com/go-logr/logr"
 
@@ -34,9 +36,9 @@ var (
 )
 
 // Start starts new process (gocmd) and wait until it's done. Status struct is then propagated back to
-// done channel passed as argument.
+// done channel passed as argument. Env of the command is added to the environment of the current process.
 func Start(cmd *gocmd.Cmd, done chan gocmd.Status) {
-	cmd.Env = os.Environ()
+	cmd.Env = append(os.Environ(), cmd.Env...)
 	logger().V(1).Info("starting command",
 		"cmd", cmd.Name,
 		"args", fmt.Sprint(cmd.Args),
@@ -76,31 +78,98 @@ func ShutdownHookForChildCommand(cmd *gocmd.Cmd) {
 	}()
 }
 
+// stopPollInterval defines how often the process group of the stopped command is checked for remaining processes.
+const stopPollInterval = 50 * time.Millisecond
+
+// Stop terminates the whole process group of the cmd, so that children it spawned (such as a JVM started by a build tool)
+// are not left behind, even when the cmd itself has already exited. Processes still running once the timeout elapses
+// are killed. Returns true if that was the case.
+func Stop(cmd *gocmd.Cmd, stopTimeout time.Duration) bool {
+	pid := cmd.Status().PID
+	if pid <= 0 {
+		return false
+	}
+
+	select {
+	case <-cmd.Done():
+		signalGroup(pid, syscall.SIGTERM)
+	default:
+		if err := cmd.Stop(); err != nil {
+			logger().Error(err, fmt.Sprintf("failed stopping %s", cmd.Name))
+		}
+	}
+
+	timeout := time.NewTimer(stopTimeout)
+	defer timeout.Stop()
+	kill := func() bool {
+		logger().Info(fmt.Sprintf("%s did not stop in time, killing it", cmd.Name))
+		signalGroup(pid, syscall.SIGKILL)
+		<-cmd.Done()
+
+		return true
+	}
+
+	select {
+	case <-cmd.Done():
+	case <-timeout.C:
+		return kill()
+	}
+	// children can still be shutting down after the cmd itself exited
+	for groupRunning(pid) {
+		select {
+		case <-timeout.C:
+			return kill()
+		case <-time.After(stopPollInterval):
+		}
+	}
+
+	return false
+}
+
+// signalGroup sends the signal to all the processes of the group (gocmd.Cmd starts each command in its own group).
+func signalGroup(pgid int, sig syscall.Signal) {
+	if err := syscall.Kill(-pgid, sig); err != nil && !errors.Is(err, syscall.ESRCH) {
+		logger().Error(err, "failed signaling process group", "pgid", pgid, "signal", sig.String())
+	}
+}
+
+func groupRunning(pgid int) bool {
+	return syscall.Kill(-pgid, 0) == nil
+}
+
 // RedirectStreams redirects Stdout and Stderr of the gocmd.Cmd process to passed io.Writers.
-func RedirectStreams(src *gocmd.Cmd, stdoutDest, stderrDest io.Writer) {
+// The returned channel is closed once all the output of the process is written.
+func RedirectStreams(src *gocmd.Cmd, stdoutDest, stderrDest io.Writer) <-chan struct{} {
+	redirected := make(chan struct{})
 	go func() {
-	OutOfLoop:
-		for {
+		defer close(redirected)
+		// gocmd.Cmd closes both streams when the process is done
+		stdout, stderr := src.Stdout, src.Stderr
+		for stdout != nil || stderr != nil {
 			select {
-			case line, ok := <-src.Stdout:
+			case line, ok := <-stdout:
 				if !ok {
-					break OutOfLoop
+					stdout = nil
+
+					continue
 				}
 				if _, err := fmt.Fprintln(stdoutDest, line); err != nil {
 					logger().Error(err, fmt.Sprintf("%s failed executing", src.Name))
 				}
-			case line, ok := <-src.Stderr:
+			case line, ok := <-stderr:
 				if !ok {
-					break OutOfLoop
+					stderr = nil
+
+					continue
 				}
 				if _, err := fmt.Fprintln(stderrDest, line); err != nil {
 					logger().Error(err, fmt.Sprintf("%s failed executing", src.Name))
 				}
-			case <-src.Done():
-				break OutOfLoop
 			}
 		}
 	}()
+
+	return redirected
 }
 
 // CurrentDir returns current directory from where binary is executed.
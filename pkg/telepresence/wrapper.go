@@ -3,6 +3,8 @@ package telepresence
 import (
 	"fmt"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
 
 	gocmd "github.com/go-cmd/cmd"
@@ -16,6 +18,8 @@ const (
 	installHint = "Head over to https://www.telepresence.io/reference/install for installation instructions.\n"
 )
 
+var versionPattern = regexp.MustCompile(`v?(\d+)\.\d+`)
+
 var errorNoTelepresenceHint = fmt.Errorf("couldn't find '%s' installed in your system.\n%s\n"+
 	"you can specify the version using TELEPRESENCE_VERSION environment variable", BinaryName, installHint)
 
@@ -50,3 +54,21 @@ func GetVersion() (string, error) {
 func BinaryAvailable() bool {
 	return shell.BinaryExists(BinaryName, installHint)
 }
+
+// MajorVersion extracts the major version from the output of GetVersion.
+// Both legacy format (e.g. 0.109) and Telepresence 2 format (e.g. Client: v2.4.5 (api v3)) are supported.
+func MajorVersion(version string) (int, error) {
+	match := versionPattern.FindStringSubmatch(version)
+	if match == nil {
+		return 0, fmt.Errorf("unable to determine telepresence version from %q", version)
+	}
+
+	return strconv.Atoi(match[1])
+}
+
+// IsV2 checks if given version denotes Telepresence 2 or newer. Unknown versions are treated as legacy ones.
+func IsV2(version string) bool {
+	major, err := MajorVersion(version)
+
+	return err == nil && major >= 2
+}
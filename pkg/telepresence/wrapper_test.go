@@ -73,4 +73,26 @@ var _ = Describe("telepresence commands wrapper", func() {
 
 	})
 
+	Context("version detection", func() {
+
+		It("should determine major version of legacy telepresence", func() {
+			Expect(telepresence.MajorVersion("0.109")).To(Equal(0))
+		})
+
+		It("should determine major version of Telepresence 2", func() {
+			Expect(telepresence.MajorVersion("Client: v2.4.5 (api v3) Root Daemon: v2.4.5 (api v3)")).To(Equal(2))
+			Expect(telepresence.MajorVersion("2.3.1")).To(Equal(2))
+		})
+
+		It("should fail for version without numbers", func() {
+			_, err := telepresence.MajorVersion("--version")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should treat unknown version as legacy one", func() {
+			Expect(telepresence.IsV2("unknown")).To(BeFalse())
+			Expect(telepresence.IsV2("Client: v2.4.5 (api v3)")).To(BeTrue())
+		})
+	})
+
 })
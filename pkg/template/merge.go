@@ -0,0 +1,72 @@
+package template
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+	jsonpatch "github.com/evanphx/json-patch"
+	openshiftappsv1 "github.com/openshift/api/apps/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+// strategicMerge applies the patch using the merge strategies of the resource kind,
+// e.g. containers are merged by name instead of replacing the whole list.
+// Kinds without known schema are patched using RFC 7386 JSON Merge Patch.
+func strategicMerge(resource, patch []byte) ([]byte, error) {
+	data, err := NewJSON(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	var schema interface{}
+	switch {
+	case data.Equal("/kind", "Deployment"):
+		schema = appsv1.Deployment{}
+	case data.Equal("/kind", "DeploymentConfig"):
+		schema = openshiftappsv1.DeploymentConfig{}
+	default:
+		modified, err := jsonpatch.MergePatch(resource, patch)
+
+		return modified, errors.Wrap(err, "failed applying merge patch")
+	}
+
+	modified, err := strategicpatch.StrategicMergePatch(resource, patch, schema)
+
+	return modified, errors.Wrap(err, "failed applying strategic merge patch")
+}
+
+// ContainerName returns the name of the container in the pod template, resolved the same way as ContainerIndex.
+// Useful for strategic merge patches where containers are matched by their name.
+func (t JSON) ContainerName(name string) (string, error) {
+	i, err := t.ContainerIndex(name)
+	if err != nil {
+		return "", err
+	}
+
+	containerName, err := t.Value("/spec/template/spec/containers/" + strconv.Itoa(i) + "/name")
+	if err != nil || containerName == nil {
+		return "", errors.Errorf("unable to find name of container %d", i)
+	}
+
+	return fmt.Sprint(containerName), nil
+}
+
+// ParseKeyValues parses comma separated list of key=value pairs, e.g. DEBUG=true,LOG_LEVEL=trace.
+func ParseKeyValues(pairs string) (map[string]string, error) {
+	parsed := map[string]string{}
+	for _, pair := range strings.Split(pairs, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("expected key=value pair, got %s", pair)
+		}
+		parsed[parts[0]] = parts[1]
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,112 @@
+package template
+
+import (
+	"sort"
+	"sync"
+
+	"emperror.dev/errors"
+)
+
+// ReloadableEngine is an Engine which combines a fixed set of base patches with patches provided at runtime
+// by named sources, e.g. ConfigMaps. Sources can be updated or removed at any time.
+//
+// Base patches always take precedence, a source can not replace a built-in strategy. When multiple sources
+// define the same patch, the source with the lowest name wins.
+type ReloadableEngine struct {
+	mu      sync.RWMutex
+	base    Patches
+	sources map[string]Patches
+	engine  Engine
+	patches Patches
+}
+
+var _ Engine = &ReloadableEngine{}
+
+// NewReloadableEngine constructs a new ReloadableEngine based on the given patches.
+func NewReloadableEngine(base Patches) *ReloadableEngine {
+	e := &ReloadableEngine{
+		base:    base,
+		sources: map[string]Patches{},
+	}
+	e.rebuild()
+
+	return e
+}
+
+// Run performs the template transformation using the currently known patches.
+func (e *ReloadableEngine) Run(name string, resource []byte, newVersion string, variables map[string]string) ([]byte, error) {
+	e.mu.RLock()
+	engine := e.engine
+	e.mu.RUnlock()
+
+	return engine.Run(name, resource, newVersion, variables)
+}
+
+// Render returns the patches the named strategies produce using the currently known patches.
+func (e *ReloadableEngine) Render(name string, resource []byte, newVersion string, variables map[string]string) ([]RenderedPatch, error) {
+	e.mu.RLock()
+	engine := e.engine
+	e.mu.RUnlock()
+
+	return engine.Render(name, resource, newVersion, variables)
+}
+
+// Patches returns all currently known patches.
+func (e *ReloadableEngine) Patches() Patches {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return append(Patches{}, e.patches...)
+}
+
+// Update replaces all patches provided by the given source. The patches are validated against the currently
+// known patches first, an invalid set of patches is rejected and the previous state of the source is kept.
+func (e *ReloadableEngine) Update(source string, patches Patches) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, p := range patches {
+		if e.base.find(p.Name) != nil {
+			return errors.Errorf("patch %s from %s conflicts with built-in patch", p.Name, source)
+		}
+	}
+	if _, err := parseTemplate(append(append(Patches{}, e.base...), patches...)); err != nil {
+		return errors.WrapWithDetails(err, "invalid patches", "source", source)
+	}
+
+	e.sources[source] = patches
+	e.rebuild()
+
+	return nil
+}
+
+// Remove drops all patches provided by the given source.
+func (e *ReloadableEngine) Remove(source string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, exists := e.sources[source]; !exists {
+		return
+	}
+	delete(e.sources, source)
+	e.rebuild()
+}
+
+func (e *ReloadableEngine) rebuild() {
+	sources := make([]string, 0, len(e.sources))
+	for source := range e.sources {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	patches := append(Patches{}, e.base...)
+	for _, source := range sources {
+		for _, p := range e.sources[source] {
+			if patches.find(p.Name) == nil {
+				patches = append(patches, p)
+			}
+		}
+	}
+	e.patches = patches
+	e.engine = NewPatchEngine(patches)
+}
@@ -0,0 +1,71 @@
+package template_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/maistra/istio-workspace/pkg/template"
+)
+
+var _ = Describe("Reloadable template engine", func() {
+
+	const validTemplate = `[{{ template "_basic-version" . }}{{ template "_basic-remove" . }}]`
+
+	var engine *template.ReloadableEngine
+
+	customStrategy := func(tpl string) template.Patches {
+		return template.PatchesFromData(map[string]string{
+			"with-debug-env.tpl": tpl,
+			"with-debug-env.var": "level=debug",
+		})
+	}
+
+	BeforeEach(func() {
+		engine = template.NewReloadableEngine(template.LoadPatches("template/strategies"))
+	})
+
+	It("should construct patches from data", func() {
+		patches := customStrategy(validTemplate)
+		Expect(patches).To(HaveLen(1))
+		Expect(patches[0].Name).To(Equal("with-debug-env"))
+		Expect(patches[0].Variables).To(HaveKeyWithValue("level", "debug"))
+	})
+
+	It("should run strategies loaded at runtime", func() {
+		Expect(engine.Update("test/strategies", customStrategy(validTemplate))).To(Succeed())
+
+		modified, err := engine.Run("with-debug-env", []byte(testDeployment), "v5", nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		modifiedJSON, err := template.NewJSON(modified)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(modifiedJSON.Equal("/metadata/name", "productpage-v1-v5")).To(BeTrue())
+	})
+
+	It("should drop strategies of removed source", func() {
+		Expect(engine.Update("test/strategies", customStrategy(validTemplate))).To(Succeed())
+		engine.Remove("test/strategies")
+
+		_, err := engine.Run("with-debug-env", []byte(testDeployment), "v5", nil)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("unable to find patch"))
+	})
+
+	It("should reject invalid templates and keep previous version", func() {
+		Expect(engine.Update("test/strategies", customStrategy(validTemplate))).To(Succeed())
+
+		err := engine.Update("test/strategies", customStrategy(`[{{ template "_basic-version" . }]`))
+		Expect(err).To(HaveOccurred())
+
+		_, err = engine.Run("with-debug-env", []byte(testDeployment), "v5", nil)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should not allow to replace built-in strategies", func() {
+		err := engine.Update("test/strategies", template.PatchesFromData(map[string]string{
+			"telepresence.tpl": `[]`,
+		}))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("conflicts with built-in patch"))
+	})
+})
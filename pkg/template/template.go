@@ -4,52 +4,96 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"os"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
 
 	"emperror.dev/errors"
 	jsonpatch "github.com/evanphx/json-patch"
+	"sigs.k8s.io/yaml"
 
 	"github.com/maistra/istio-workspace/pkg/assets"
 )
 
-const TemplatePath = "TEMPLATE_PATH"
+const (
+	TemplatePath = "TEMPLATE_PATH"
+
+	// DefaultStrategiesPath is the location of the built-in strategies.
+	DefaultStrategiesPath = "template/strategies"
+
+	// StrategyLabel marks a ConfigMap as a source of custom strategies. Every NAME.tpl or NAME.smp.yaml key of the ConfigMap
+	// defines a strategy NAME with the default variables defined in the optional NAME.var key.
+	StrategyLabel = "maistra.io/istio-workspace-strategy"
+
+	// SubsetLabelVariable is the name of the template variable holding the pod label used to identify version subsets.
+	SubsetLabelVariable = "subsetLabel"
+
+	// ContainerVariable is the name of the template variable holding the name of the container the strategy should target.
+	ContainerVariable = "container"
+
+	// DefaultContainerAnnotation is the pod annotation used to mark the main container of a multi-container pod.
+	DefaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+	istioProxyContainer = "istio-proxy"
+
+	jsonPatchExtension           = ".tpl"
+	strategicMergePatchExtension = ".smp.yaml"
+
+	// basicPatch prepares the clone (name, version labels, removal of server side fields) when the strategy itself is
+	// not a JSON Patch taking care of it.
+	basicPatch = "_basic"
+
+	// strategySeparator separates names of the strategies composed together, e.g. prepared-image,extra-env.
+	strategySeparator = ","
+)
+
+// Format defines how the patch template is applied to the resource.
+type Format string
+
+const (
+	// JSONPatch is a RFC 6902 JSON Patch.
+	JSONPatch Format = "json-patch"
+	// StrategicMergePatch is a kubernetes strategic merge patch, falling back to RFC 7386 JSON Merge Patch for unknown kinds.
+	StrategicMergePatch Format = "strategic-merge-patch"
+)
 
 var (
 	errorInvalidPath = fmt.Errorf("given path is not valid")
+
+	jsonPointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	jsonPointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+	requiredVariablePattern = regexp.MustCompile(`failIfVariableDoesNotExist\s+\$?\.Vars\s+"([^"]+)"`)
+	includePattern          = regexp.MustCompile(`\{\{-?\s*template\s+"([^"]+)"`)
 )
 
-func loadPatches(tplFolder string) []Patch {
+// LoadPatches loads all patch templates and their default variables from the given folder.
+// Templates are either JSON Patches (NAME.tpl) or strategic merge patches (NAME.smp.yaml).
+func LoadPatches(tplFolder string) Patches {
 	tplDir, err := assets.ListDir(tplFolder)
 	if err != nil {
 		panic(err)
 	}
-	patches := []Patch{}
+	patches := Patches{}
 	for _, file := range tplDir {
-		if !strings.HasSuffix(file, ".tpl") {
+		tplName, format := patchName(file)
+		if tplName == "" {
 			continue
 		}
-		tplName := strings.Replace(file, ".tpl", "", -1)
 		tpl, err := assets.Load(tplFolder + "/" + file)
 		if err != nil {
 			panic(err)
 		}
 		tplVars := map[string]string{}
 		if tplVarRaw, err := assets.Load(tplFolder + "/" + tplName + ".var"); err == nil {
-			for _, line := range strings.Split(string(tplVarRaw), "\n") {
-				if line != "" {
-					vars := strings.Split(line, "=")
-					varName := strings.Trim(vars[0], " ")
-					tplVars[varName] = ""
-					if len(vars) == 2 {
-						tplVars[varName] = strings.Trim(vars[1], " ")
-					}
-				}
-			}
+			tplVars = parseVariables(tplVarRaw)
 		}
 		patches = append(patches, Patch{
 			Name:      tplName,
+			Format:    format,
 			Template:  tpl,
 			Variables: tplVars,
 		})
@@ -58,14 +102,76 @@ func loadPatches(tplFolder string) []Patch {
 	return patches
 }
 
+// PatchesFromData constructs patches from a flat file name to content mapping, e.g. the data of a ConfigMap.
+// Every NAME.tpl or NAME.smp.yaml entry becomes a patch named NAME with the default variables defined in the optional NAME.var entry.
+func PatchesFromData(data map[string]string) Patches {
+	files := make([]string, 0, len(data))
+	for file := range data {
+		if name, _ := patchName(file); name != "" {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+
+	patches := Patches{}
+	for _, file := range files {
+		name, format := patchName(file)
+		patches = append(patches, Patch{
+			Name:      name,
+			Format:    format,
+			Template:  []byte(data[file]),
+			Variables: parseVariables([]byte(data[name+".var"])),
+		})
+	}
+
+	return patches
+}
+
+// patchName returns the name and the format of the patch defined in the given file, or empty name if the file is not a patch template.
+func patchName(file string) (string, Format) {
+	switch {
+	case strings.HasSuffix(file, jsonPatchExtension):
+		return strings.TrimSuffix(file, jsonPatchExtension), JSONPatch
+	case strings.HasSuffix(file, strategicMergePatchExtension):
+		return strings.TrimSuffix(file, strategicMergePatchExtension), StrategicMergePatch
+	}
+
+	return "", ""
+}
+
+func parseVariables(raw []byte) map[string]string {
+	tplVars := map[string]string{}
+	for _, line := range strings.Split(string(raw), "\n") {
+		if line != "" {
+			vars := strings.Split(line, "=")
+			varName := strings.Trim(vars[0], " ")
+			tplVars[varName] = ""
+			if len(vars) == 2 {
+				tplVars[varName] = strings.Trim(vars[1], " ")
+			}
+		}
+	}
+
+	return tplVars
+}
+
+// StrategiesPath returns the location of the strategies, which is either TEMPLATE_PATH if set or the built-in ones.
+func StrategiesPath() string {
+	if path, exists := os.LookupEnv(TemplatePath); exists {
+		return path
+	}
+
+	return DefaultStrategiesPath
+}
+
 // NewDefaultEngine returns a new Engine with a predefined templates.
 func NewDefaultEngine() Engine {
-	return NewDefaultPatchEngine("template/strategies")
+	return NewDefaultPatchEngine(DefaultStrategiesPath)
 }
 
 // NewDefaultPatchEngine returns a new Engine with a predefined templates.
 func NewDefaultPatchEngine(path string) Engine {
-	return NewPatchEngine(loadPatches(path))
+	return NewPatchEngine(LoadPatches(path))
 }
 
 // NewPatchEngine constructs a new Engine with the given templates.
@@ -91,19 +197,65 @@ type Context struct {
 	Vars       map[string]string
 }
 
-// Patch is a named JSON Patch and it's defined default variables.
+// Patch is a named patch template, its format and it's defined default variables.
 type Patch struct {
 	Name      string
+	Format    Format
 	Template  []byte
 	Variables map[string]string
 }
 
+// RenderedPatch is a patch of a single strategy rendered for a given resource.
+type RenderedPatch struct {
+	Name   string
+	Format Format
+	Patch  []byte
+}
+
 // Patches holds all known patch templates for a Engine.
 type Patches []Patch
 
+// IsPartial returns true for patches which are not strategies on their own, but are meant to be included by other patches.
+func (p Patch) IsPartial() bool {
+	return strings.HasPrefix(p.Name, "_")
+}
+
+// RequiredVariables returns the sorted names of the variables which have to be set for the named patch to render,
+// including the ones required by all the patches it includes.
+func (p Patches) RequiredVariables(name string) []string {
+	required := map[string]bool{}
+	visited := map[string]bool{}
+	var visit func(name string)
+	visit = func(name string) {
+		patch := p.find(name)
+		if patch == nil || visited[name] {
+			return
+		}
+		visited[name] = true
+		for _, match := range requiredVariablePattern.FindAllSubmatch(patch.Template, -1) {
+			required[string(match[1])] = true
+		}
+		for _, match := range includePattern.FindAllSubmatch(patch.Template, -1) {
+			visit(string(match[1]))
+		}
+	}
+	visit(name)
+
+	names := make([]string, 0, len(required))
+	for variable := range required {
+		names = append(names, variable)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
 // Engine is a interface that describes a way to prepare the Deployment for cloning.
+//
+// The name can refer to multiple strategies separated by comma, e.g. prepared-image,extra-env, which are applied in the given order.
 type Engine interface {
 	Run(name string, resource []byte, newVersion string, variables map[string]string) ([]byte, error)
+	Render(name string, resource []byte, newVersion string, variables map[string]string) ([]RenderedPatch, error)
 }
 
 // PatchEngine is a reusable instance with a configured set of patch templates to manipulate the Deployment object via json patches.
@@ -112,6 +264,7 @@ type patchEngine struct {
 }
 
 // Value returns the object value behind a json path, e.g. /spec/metadata/name.
+// Path segments containing / or ~ are expected to be escaped as defined by RFC 6901, e.g. /metadata/labels/app.kubernetes.io~1version.
 func (t JSON) Value(path string) (interface{}, error) {
 	parts := strings.Split(path, "/")
 	if len(parts) < 2 {
@@ -120,6 +273,7 @@ func (t JSON) Value(path string) (interface{}, error) {
 	parts = parts[1:]
 	var level interface{} = t
 	for i, part := range parts {
+		part = jsonPointerUnescaper.Replace(part)
 		var l interface{}
 		switch v := level.(type) {
 		case map[string]interface{}:
@@ -169,18 +323,186 @@ func (t JSON) Equal(path string, compare interface{}) bool {
 	return fmt.Sprint(v) == fmt.Sprint(compare)
 }
 
+// ContainerIndex returns the index of the named container in the pod template, e.g. to be used in /spec/template/spec/containers/INDEX/image.
+//
+// If name is empty the default container is resolved in the following order:
+// the container named by the DefaultContainerAnnotation, the container named after the resource itself,
+// the first container which is not the istio-proxy sidecar.
+func (t JSON) ContainerIndex(name string) (int, error) {
+	rawContainers, err := t.Value("/spec/template/spec/containers")
+	if err != nil {
+		return 0, err
+	}
+	containers, ok := rawContainers.([]interface{})
+	if !ok || len(containers) == 0 {
+		return 0, errors.New("no containers defined in pod template")
+	}
+	containerName := func(i int) string {
+		if container, ok := containers[i].(map[string]interface{}); ok {
+			return fmt.Sprint(container["name"])
+		}
+
+		return ""
+	}
+	indexOf := func(name string) int {
+		for i := range containers {
+			if containerName(i) == name {
+				return i
+			}
+		}
+
+		return -1
+	}
+
+	if name != "" {
+		if i := indexOf(name); i >= 0 {
+			return i, nil
+		}
+
+		return 0, errors.Errorf("unable to find container %s", name)
+	}
+
+	if annotated, err := t.Value("/spec/template/metadata/annotations/" + EscapeJSONPointer(DefaultContainerAnnotation)); err == nil && annotated != nil {
+		if i := indexOf(fmt.Sprint(annotated)); i >= 0 {
+			return i, nil
+		}
+	}
+	if resourceName, err := t.Value("/metadata/name"); err == nil && resourceName != nil {
+		if i := indexOf(fmt.Sprint(resourceName)); i >= 0 {
+			return i, nil
+		}
+	}
+	for i := range containers {
+		if containerName(i) != istioProxyContainer {
+			return i, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// ParseImages parses the image variable which can either hold a single image used for the default container,
+// or a comma separated list of container=image pairs, e.g. app=quay.io/app:v2,proxy=quay.io/proxy:v2.
+// The default container is represented by an empty name.
+func ParseImages(images string) (map[string]string, error) {
+	if !strings.Contains(images, "=") {
+		return map[string]string{"": images}, nil
+	}
+	parsed := map[string]string{}
+	for _, pair := range strings.Split(images, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.Errorf("expected image in format container=image, got %s", pair)
+		}
+		parsed[parts[0]] = parts[1]
+	}
+
+	return parsed, nil
+}
+
 // Run performs the template transformation of a given json structure.
 func (e patchEngine) Run(name string, resource []byte, newVersion string, variables map[string]string) ([]byte, error) {
+	modified, _, err := e.apply(name, resource, newVersion, variables)
+
+	return modified, err
+}
+
+// Render returns the patches the named strategies produce for a given json structure, in the order they are applied.
+// Strategic merge patches are returned converted to JSON.
+func (e patchEngine) Render(name string, resource []byte, newVersion string, variables map[string]string) ([]RenderedPatch, error) {
+	_, rendered, err := e.apply(name, resource, newVersion, variables)
+
+	return rendered, err
+}
+
+func (e patchEngine) apply(name string, resource []byte, newVersion string, variables map[string]string) ([]byte, []RenderedPatch, error) {
 	t, err := parseTemplate(e.patches)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	chain := []*Patch{}
+	for _, strategy := range strings.Split(name, strategySeparator) {
+		patch := e.findPatch(strings.TrimSpace(strategy))
+		if patch == nil {
+			return nil, nil, errors.Errorf("unable to find patch %s", strategy)
+		}
+		if len(chain) > 0 && patch.Format != StrategicMergePatch {
+			return nil, nil, errors.Errorf("unable to compose %s, only the first strategy can be a JSON patch preparing the clone, "+
+				"the following ones have to be strategic merge patches", patch.Name)
+		}
+		chain = append(chain, patch)
+	}
+	if chain[0].Format == StrategicMergePatch {
+		basic := e.findPatch(basicPatch)
+		if basic == nil {
+			return nil, nil, errors.Errorf("unable to find patch %s required by %s", basicPatch, chain[0].Name)
+		}
+		chain = append([]*Patch{basic}, chain...)
+	}
+
+	variables, err = withDefaultContainer(resource, variables)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	patch := e.findPatch(name)
-	if patch == nil {
-		return nil, errors.Errorf("unable to find patch %s", name)
+	rendered := []RenderedPatch{}
+	modified := resource
+	for _, patch := range chain {
+		rawPatch, err := render(t, patch, modified, newVersion, variables)
+		if err != nil {
+			return nil, rendered, err
+		}
+
+		switch patch.Format {
+		case StrategicMergePatch:
+			if rawPatch, err = yaml.YAMLToJSON(rawPatch); err != nil {
+				return nil, rendered, errors.WrapWithDetails(err, "failed decoding YAML", "patch", patch.Name)
+			}
+			rendered = append(rendered, RenderedPatch{Name: patch.Name, Format: patch.Format, Patch: rawPatch})
+			if modified, err = strategicMerge(modified, rawPatch); err != nil {
+				return nil, rendered, errors.WrapWithDetails(err, "failed applying strategic merge patch", "patch", patch.Name)
+			}
+		default:
+			rendered = append(rendered, RenderedPatch{Name: patch.Name, Format: JSONPatch, Patch: rawPatch})
+			// Apply patch
+			jsonPatch, err := jsonpatch.DecodePatch(rawPatch)
+			if err != nil {
+				return nil, rendered, errors.Wrap(err, "failed decoding JSON")
+			}
+
+			if modified, err = jsonPatch.ApplyIndent(modified, "  "); err != nil {
+				return nil, rendered, errors.Wrap(err, "failed applying indent in JSON")
+			}
+		}
 	}
 
+	return modified, rendered, nil
+}
+
+// withDefaultContainer resolves the default container against the original resource, as the strategies applied
+// before might change what it is resolved to, e.g. by renaming the resource.
+func withDefaultContainer(resource []byte, variables map[string]string) (map[string]string, error) {
+	if variables[ContainerVariable] != "" {
+		return variables, nil
+	}
+	data, err := NewJSON(resource)
+	if err != nil {
+		return nil, err
+	}
+	withContainer := map[string]string{}
+	for k, v := range variables {
+		withContainer[k] = v
+	}
+	// resources without containers are left for the strategies to handle
+	if container, err := data.ContainerName(""); err == nil {
+		withContainer[ContainerVariable] = container
+	}
+
+	return withContainer, nil
+}
+
+func render(t *template.Template, patch *Patch, resource []byte, newVersion string, variables map[string]string) ([]byte, error) {
 	patchVariables := map[string]string{}
 	defaultVariables := patch.Variables
 
@@ -204,36 +526,31 @@ func (e patchEngine) Run(name string, resource []byte, newVersion string, variab
 
 	// Run Template
 	rawPatch := new(bytes.Buffer)
-	err = t.ExecuteTemplate(rawPatch, name, c)
+	err = t.ExecuteTemplate(rawPatch, patch.Name, c)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed parsing template")
 	}
 
-	// Apply patch
-	jsonPatch, err := jsonpatch.DecodePatch(rawPatch.Bytes())
-	if err != nil {
-		return nil, errors.Wrap(err, "failed decoding JSON")
-	}
-
-	modified, err := jsonPatch.ApplyIndent(resource, "  ")
-	if err != nil {
-		return nil, errors.Wrap(err, "failed applying indent in JSON")
-	}
+	return rawPatch.Bytes(), nil
+}
 
-	return modified, nil
+// EscapeJSONPointer escapes a single json path segment as defined by RFC 6901, e.g. app.kubernetes.io/version becomes app.kubernetes.io~1version.
+func EscapeJSONPointer(segment string) string {
+	return jsonPointerEscaper.Replace(segment)
 }
 
 func (e patchEngine) findPatch(name string) *Patch {
-	var patch *Patch
-	for i, p := range e.patches {
-		if p.Name == name {
-			patch = &e.patches[i]
+	return e.patches.find(name)
+}
 
-			break
+func (p Patches) find(name string) *Patch {
+	for i := range p {
+		if p[i].Name == name {
+			return &p[i]
 		}
 	}
 
-	return patch
+	return nil
 }
 
 func parseTemplate(patches Patches) (*template.Template, error) {
@@ -246,6 +563,17 @@ func parseTemplate(patches Patches) (*template.Template, error) {
 
 			return "", nil
 		},
+		"fail": func(message string) (string, error) {
+			return "", errors.New(message)
+		},
+		"escapeJSONPointer": EscapeJSONPointer,
+		"parseImages":       ParseImages,
+		"parseKeyValues":    ParseKeyValues,
+		"toJSON": func(value interface{}) (string, error) {
+			b, err := json.Marshal(value)
+
+			return string(b), errors.Wrap(err, "failed marshaling to json")
+		},
 	})
 	for _, p := range patches {
 		t, err = t.New(p.Name).Parse(string(p.Template))
@@ -95,6 +95,16 @@ var _ = Describe("Operations for template system", func() {
 				Expect(err).To(HaveOccurred())
 			})
 		})
+		Context("escaped path segments", func() {
+			It("should get value of key containing slash", func() {
+				v, err := tj.Value("/spec/template/metadata/annotations/kiali.io~1runtimes")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(v).To(BeEquivalentTo("go"))
+			})
+			It("should escape slash and tilde", func() {
+				Expect(template.EscapeJSONPointer("app.kubernetes.io/version~1")).To(Equal("app.kubernetes.io~1version~01"))
+			})
+		})
 	})
 
 	Context("engine", func() {
@@ -122,6 +132,158 @@ var _ = Describe("Operations for template system", func() {
 			})
 		})
 
+		Context("telepresence2", func() {
+			It("should keep the original container", func() {
+				e := template.NewDefaultEngine()
+
+				o, err := e.Run("telepresence2", []byte(testDeployment), "1000", map[string]string{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(o)).To(ContainSubstring("productpage-v1-1000"))
+				Expect(string(o)).To(ContainSubstring("telepresence.getambassador.io/inject-traffic-agent"))
+				Expect(string(o)).ToNot(ContainSubstring("datawire/telepresence-k8s"))
+				Expect(string(o)).To(ContainSubstring("COMMAND"))
+				Expect(string(o)).To(ContainSubstring("ARGS"))
+			})
+		})
+
+		Context("tunnel", func() {
+			It("should run the agent in place of the original container", func() {
+				e := template.NewDefaultEngine()
+
+				o, err := e.Run("tunnel", []byte(testDeployment), "1000", map[string]string{
+					"image":       "quay.io/maistra/istio-workspace:v0.4.0",
+					"controlPort": "10101",
+					"token":       "s3cr3t",
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(o)).To(ContainSubstring("quay.io/maistra/istio-workspace:v0.4.0"))
+				Expect(string(o)).To(ContainSubstring(`"agent"`))
+				Expect(string(o)).To(ContainSubstring(`"IKE_TUNNEL_TOKEN"`))
+				Expect(string(o)).To(ContainSubstring(`"s3cr3t"`))
+				Expect(string(o)).ToNot(ContainSubstring("ARGS"))
+			})
+
+			It("should fail when no image is provided", func() {
+				e := template.NewDefaultEngine()
+
+				_, err := e.Run("tunnel", []byte(testDeployment), "1000", map[string]string{})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("expected image variable to be set"))
+			})
+
+			It("should fail when no token is provided", func() {
+				e := template.NewDefaultEngine()
+
+				_, err := e.Run("tunnel", []byte(testDeployment), "1000", map[string]string{
+					"image": "quay.io/maistra/istio-workspace:v0.4.0",
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("expected token variable to be set"))
+			})
+		})
+
+		Context("sync", func() {
+			It("should keep the original container when no command is given", func() {
+				e := template.NewDefaultEngine()
+
+				o, err := e.Run("sync", []byte(testDeployment), "1000", map[string]string{
+					"pidFile": "/tmp/ike-sync.pid",
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(o)).To(ContainSubstring("kubectl.kubernetes.io/default-container"))
+				Expect(string(o)).To(ContainSubstring("COMMAND"))
+				Expect(string(o)).To(ContainSubstring("ARGS"))
+			})
+
+			It("should run the command in a restart loop", func() {
+				e := template.NewDefaultEngine()
+
+				o, err := e.Run("sync", []byte(testDeployment), "1000", map[string]string{
+					"pidFile": "/tmp/ike-sync.pid",
+					"command": `node "server.js"`,
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				clone, err := template.NewJSON(o)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(clone.Equal("/spec/template/spec/containers/0/command/2",
+					`trap 'exit 0' TERM INT; while true; do node "server.js" & echo $! > /tmp/ike-sync.pid; wait $!; sleep 1; done`)).To(BeTrue())
+				Expect(string(o)).ToNot(ContainSubstring("ARGS"))
+			})
+		})
+
+		Context("debug", func() {
+			It("should run the program under Delve", func() {
+				e := template.NewDefaultEngine()
+
+				o, err := e.Run("debug", []byte(testDeployment), "1000", map[string]string{
+					"language": "go",
+					"port":     "2345",
+					"command":  "/opt/productpage --port 9080",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				clone, err := template.NewJSON(o)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(clone.Equal("/spec/template/spec/containers/0/command/2", "set -- /opt/productpage --port 9080; program=$1; shift; "+
+					`exec dlv exec --headless --listen=127.0.0.1:2345 --api-version=2 --accept-multiclient --continue "$program" -- "$@"`)).To(BeTrue())
+				Expect(string(o)).ToNot(ContainSubstring("ARGS"))
+			})
+
+			It("should enable JDWP keeping the original command", func() {
+				e := template.NewDefaultEngine()
+
+				o, err := e.Run("debug", []byte(testDeployment), "1000", map[string]string{
+					"language": "java",
+					"port":     "5005",
+					"suspend":  "true",
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(o)).To(ContainSubstring("-agentlib:jdwp=transport=dt_socket,server=y,suspend=y,address=127.0.0.1:5005"))
+				Expect(string(o)).To(ContainSubstring("COMMAND"))
+			})
+
+			It("should wait for debugpy client when suspended", func() {
+				e := template.NewDefaultEngine()
+
+				o, err := e.Run("debug", []byte(testDeployment), "1000", map[string]string{
+					"language": "python",
+					"port":     "5678",
+					"command":  "python app.py",
+					"suspend":  "true",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				clone, err := template.NewJSON(o)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(clone.Equal("/spec/template/spec/containers/0/command/2", "set -- python app.py; interpreter=$1; shift; "+
+					`exec "$interpreter" -m debugpy --listen 127.0.0.1:5678 --wait-for-client "$@"`)).To(BeTrue())
+			})
+
+			It("should fail when no command is provided", func() {
+				e := template.NewDefaultEngine()
+
+				_, err := e.Run("debug", []byte(testDeployment), "1000", map[string]string{
+					"language": "go",
+					"port":     "2345",
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("expected command variable to be set"))
+			})
+
+			It("should fail for unsupported language", func() {
+				e := template.NewDefaultEngine()
+
+				_, err := e.Run("debug", []byte(testDeployment), "1000", map[string]string{
+					"language": "cobol",
+					"port":     "2345",
+					"command":  "run",
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("unsupported language cobol"))
+			})
+		})
+
 		Context("prepared-image", func() {
 			It("happy, happy, basic DefaultEngine", func() {
 				e := template.NewDefaultEngine()
@@ -137,6 +299,260 @@ var _ = Describe("Operations for template system", func() {
 			})
 		})
 
+		Context("subset label", func() {
+			It("should use version label by default", func() {
+				e := template.NewDefaultEngine()
+
+				o, err := e.Run("prepared-image", []byte(testDeployment), "1000", map[string]string{
+					"image": "maistra.org/test-image:test",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				clone, err := template.NewJSON(o)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(clone.Equal("/spec/template/metadata/labels/version", "1000")).To(BeTrue())
+				Expect(clone.Equal("/spec/template/metadata/labels/version-source", "v1")).To(BeTrue())
+			})
+
+			It("should use provided label containing slash", func() {
+				e := template.NewDefaultEngine()
+
+				o, err := e.Run("prepared-image", []byte(testDeployment), "1000", map[string]string{
+					"image":                      "maistra.org/test-image:test",
+					template.SubsetLabelVariable: "app.kubernetes.io/version",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				clone, err := template.NewJSON(o)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(clone.Equal("/spec/template/metadata/labels/app.kubernetes.io~1version", "1000")).To(BeTrue())
+				Expect(clone.Equal("/spec/template/metadata/labels/version", "v1")).To(BeTrue())
+				Expect(clone.Equal("/spec/selector/matchLabels/app.kubernetes.io~1version", "1000")).To(BeTrue())
+			})
+		})
+
+		Context("multiple containers", func() {
+
+			It("should resolve default container by name of the resource", func() {
+				data, err := template.NewJSON([]byte(multiContainerDeployment))
+				Expect(err).ToNot(HaveOccurred())
+
+				i, err := data.ContainerIndex("")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(i).To(Equal(1))
+			})
+
+			It("should resolve default container by annotation", func() {
+				data, err := template.NewJSON([]byte(multiContainerDeployment))
+				Expect(err).ToNot(HaveOccurred())
+				data["spec"].(map[string]interface{})["template"].(map[string]interface{})["metadata"] = map[string]interface{}{
+					"annotations": map[string]interface{}{template.DefaultContainerAnnotation: "log-shipper"},
+				}
+
+				i, err := data.ContainerIndex("")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(i).To(Equal(0))
+			})
+
+			It("should fail on unknown container", func() {
+				data, err := template.NewJSON([]byte(multiContainerDeployment))
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = data.ContainerIndex("unknown")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("unable to find container unknown"))
+			})
+
+			It("should replace image of default container", func() {
+				e := template.NewDefaultEngine()
+
+				o, err := e.Run("prepared-image", []byte(multiContainerDeployment), "1000", map[string]string{
+					"image": "maistra.org/test-image:test",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				clone, err := template.NewJSON(o)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(clone.Equal("/spec/template/spec/containers/0/image", "fluentd")).To(BeTrue())
+				Expect(clone.Equal("/spec/template/spec/containers/1/image", "maistra.org/test-image:test")).To(BeTrue())
+				Expect(clone.Has("/spec/template/spec/containers/1/livenessProbe")).To(BeFalse())
+				Expect(clone.Has("/spec/template/spec/containers/0/livenessProbe")).To(BeTrue())
+			})
+
+			It("should replace image of named container", func() {
+				e := template.NewDefaultEngine()
+
+				o, err := e.Run("prepared-image", []byte(multiContainerDeployment), "1000", map[string]string{
+					"image":     "maistra.org/test-image:test",
+					"container": "log-shipper",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				clone, err := template.NewJSON(o)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(clone.Equal("/spec/template/spec/containers/0/image", "maistra.org/test-image:test")).To(BeTrue())
+				Expect(clone.Equal("/spec/template/spec/containers/1/image", "docker.io/maistra/reviews:v1")).To(BeTrue())
+			})
+
+			It("should replace images of multiple containers", func() {
+				e := template.NewDefaultEngine()
+
+				o, err := e.Run("prepared-image", []byte(multiContainerDeployment), "1000", map[string]string{
+					"image": "log-shipper=maistra.org/fluentd:dev,reviews=maistra.org/reviews:dev",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				clone, err := template.NewJSON(o)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(clone.Equal("/spec/template/spec/containers/0/image", "maistra.org/fluentd:dev")).To(BeTrue())
+				Expect(clone.Equal("/spec/template/spec/containers/1/image", "maistra.org/reviews:dev")).To(BeTrue())
+			})
+
+			It("should target default container with telepresence", func() {
+				e := template.NewDefaultEngine()
+
+				o, err := e.Run("telepresence", []byte(multiContainerDeployment), "1000", map[string]string{
+					"version": "x-x-v",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				clone, err := template.NewJSON(o)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(clone.Equal("/spec/template/spec/containers/0/image", "fluentd")).To(BeTrue())
+				Expect(clone.Equal("/spec/template/spec/containers/1/image", "datawire/telepresence-k8s:x-x-v")).To(BeTrue())
+			})
+
+			It("should target named container with telepresence", func() {
+				e := template.NewDefaultEngine()
+
+				o, err := e.Run("telepresence", []byte(multiContainerDeployment), "1000", map[string]string{
+					"version":   "x-x-v",
+					"container": "log-shipper",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				clone, err := template.NewJSON(o)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(clone.Equal("/spec/template/spec/containers/0/image", "datawire/telepresence-k8s:x-x-v")).To(BeTrue())
+				Expect(clone.Equal("/spec/template/spec/containers/0/env/0/name", "TELEPRESENCE_CONTAINER_NAMESPACE")).To(BeTrue())
+				Expect(clone.Equal("/spec/template/spec/containers/1/image", "docker.io/maistra/reviews:v1")).To(BeTrue())
+				Expect(clone.Has("/spec/template/spec/containers/1/env")).To(BeFalse())
+			})
+		})
+
+		Context("strategic merge patches", func() {
+
+			It("should add env to the default container", func() {
+				e := template.NewDefaultEngine()
+
+				o, err := e.Run("extra-env", []byte(multiContainerDeployment), "1000", map[string]string{
+					"env": "DEBUG=true,LOG_LEVEL=trace",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				clone, err := template.NewJSON(o)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(clone.Equal("/spec/template/spec/containers/1/name", "reviews")).To(BeTrue())
+				Expect(clone.Equal("/spec/template/spec/containers/1/env/0/name", "DEBUG")).To(BeTrue())
+				Expect(clone.Equal("/spec/template/spec/containers/1/env/1/value", "trace")).To(BeTrue())
+				Expect(clone.Has("/spec/template/spec/containers/0/env")).To(BeFalse())
+			})
+
+			It("should prepare the clone when used on its own", func() {
+				e := template.NewDefaultEngine()
+
+				o, err := e.Run("extra-env", []byte(multiContainerDeployment), "1000", map[string]string{
+					"env": "DEBUG=true",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				clone, err := template.NewJSON(o)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(clone.Equal("/metadata/name", "reviews-1000")).To(BeTrue())
+				Expect(clone.Equal("/spec/template/metadata/labels/version", "1000")).To(BeTrue())
+			})
+
+			It("should compose multiple strategies", func() {
+				e := template.NewDefaultEngine()
+
+				o, err := e.Run("prepared-image,extra-env", []byte(multiContainerDeployment), "1000", map[string]string{
+					"image": "maistra.org/reviews:dev",
+					"env":   "DEBUG=true",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				clone, err := template.NewJSON(o)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(clone.Equal("/metadata/name", "reviews-1000")).To(BeTrue())
+				Expect(clone.Equal("/spec/template/spec/containers/1/image", "maistra.org/reviews:dev")).To(BeTrue())
+				Expect(clone.Equal("/spec/template/spec/containers/1/env/0/name", "DEBUG")).To(BeTrue())
+			})
+
+			It("should render each of the composed strategies", func() {
+				e := template.NewDefaultEngine()
+
+				rendered, err := e.Render("prepared-image,extra-env", []byte(multiContainerDeployment), "1000", map[string]string{
+					"image": "maistra.org/reviews:dev",
+					"env":   "DEBUG=true",
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rendered).To(HaveLen(2))
+				Expect(rendered[0].Format).To(Equal(template.JSONPatch))
+				Expect(rendered[1].Format).To(Equal(template.StrategicMergePatch))
+				Expect(string(rendered[1].Patch)).To(ContainSubstring(`"name":"DEBUG"`))
+			})
+
+			It("should prepare the clone once when composing multiple strategic merge patches", func() {
+				e := template.NewPatchEngine(append(template.LoadPatches(template.DefaultStrategiesPath), template.Patch{
+					Name:     "with-debug",
+					Format:   template.StrategicMergePatch,
+					Template: []byte("metadata:\n  labels:\n    debug: \"true\""),
+				}))
+
+				o, err := e.Run("extra-env,with-debug", []byte(multiContainerDeployment), "1000", map[string]string{
+					"env": "DEBUG=true",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				clone, err := template.NewJSON(o)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(clone.Equal("/metadata/name", "reviews-1000")).To(BeTrue())
+				Expect(clone.Equal("/metadata/labels/debug", "true")).To(BeTrue())
+			})
+
+			It("should fail on JSON patch following other strategy in composition", func() {
+				e := template.NewDefaultEngine()
+
+				_, err := e.Run("extra-env,prepared-image", []byte(multiContainerDeployment), "1000", map[string]string{
+					"image": "maistra.org/reviews:dev",
+					"env":   "DEBUG=true",
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("unable to compose prepared-image"))
+			})
+
+			It("should fail on unknown strategy in composition", func() {
+				e := template.NewDefaultEngine()
+
+				_, err := e.Run("prepared-image,unknown", []byte(multiContainerDeployment), "1000", map[string]string{
+					"image": "maistra.org/reviews:dev",
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("unable to find patch unknown"))
+			})
+
+			It("should load strategic merge patches from data", func() {
+				patches := template.PatchesFromData(map[string]string{
+					"with-debug-env.smp.yaml": "metadata: {}",
+					"with-debug-env.var":      "level=debug",
+				})
+				Expect(patches).To(HaveLen(1))
+				Expect(patches[0].Name).To(Equal("with-debug-env"))
+				Expect(patches[0].Format).To(Equal(template.StrategicMergePatch))
+				Expect(patches[0].Variables).To(HaveKeyWithValue("level", "debug"))
+			})
+		})
+
 		Context("object validation", func() {
 			It("should fail on wrong Patch format", func() {
 				e := template.NewPatchEngine(template.Patches{template.Patch{
@@ -214,6 +630,39 @@ var _ = Describe("Operations for template system", func() {
 				Expect(string(o)).To(ContainSubstring("PROVIDED_VERSION"))
 			})
 		})
+
+		Context("rendering", func() {
+
+			It("should return patch without applying it", func() {
+				e := template.NewPatchEngine(template.Patches{template.Patch{
+					Name:     "test",
+					Template: []byte(`[ {"op": "replace", "path": "/version", "value": "{{.NewVersion}}"} ]`),
+				}})
+				p, err := e.Render("test", []byte(`{"version": "100"}`), "x", map[string]string{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(p).To(HaveLen(1))
+				Expect(string(p[0].Patch)).To(Equal(`[ {"op": "replace", "path": "/version", "value": "x"} ]`))
+			})
+		})
+
+		Context("required variables", func() {
+
+			It("should collect required variables of included patches", func() {
+				patches := template.Patches{
+					template.Patch{
+						Name:     "test",
+						Template: []byte(`{{ failIfVariableDoesNotExist .Vars "image" }}[{{ template "_include" . }}]`),
+					},
+					template.Patch{
+						Name:     "_include",
+						Template: []byte(`{{ failIfVariableDoesNotExist .Vars "version" -}}`),
+					},
+				}
+
+				Expect(patches.RequiredVariables("test")).To(Equal([]string{"image", "version"}))
+				Expect(patches.RequiredVariables("_include")).To(Equal([]string{"version"}))
+			})
+		})
 	})
 })
 
@@ -336,3 +785,58 @@ const testDeployment = `
     }
 }
 `
+
+const multiContainerDeployment = `
+{
+    "apiVersion": "apps/v1",
+    "kind": "Deployment",
+    "metadata": {
+        "creationTimestamp": "2019-07-13T08:46:46Z",
+        "labels": {
+            "app": "reviews",
+            "version": "v1"
+        },
+        "name": "reviews",
+        "namespace": "bookinfo"
+    },
+    "spec": {
+        "replicas": 1,
+        "selector": {
+            "matchLabels": {
+                "app": "reviews",
+                "version": "v1"
+            }
+        },
+        "template": {
+            "metadata": {
+                "labels": {
+                    "app": "reviews",
+                    "version": "v1"
+                }
+            },
+            "spec": {
+                "containers": [
+                    {
+                        "name": "log-shipper",
+                        "image": "fluentd",
+                        "livenessProbe": {
+                            "exec": {"command": ["true"]}
+                        }
+                    },
+                    {
+                        "name": "reviews",
+                        "image": "docker.io/maistra/reviews:v1",
+                        "livenessProbe": {
+                            "httpGet": {"path": "/healthz", "port": 9080}
+                        }
+                    },
+                    {
+                        "name": "istio-proxy",
+                        "image": "docker.io/istio/proxyv2"
+                    }
+                ]
+            }
+        }
+    }
+}
+`
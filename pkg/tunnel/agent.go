@@ -0,0 +1,237 @@
+package tunnel
+
+import (
+	"context"
+	"crypto/subtle"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+const (
+	idleConnections  = 64
+	handshakeTimeout = 10 * time.Second
+	handoverTimeout  = 30 * time.Second
+	dialTimeout      = 10 * time.Second
+	maxHandshake     = 300
+)
+
+// Agent runs in the cloned pod and forwards its inbound traffic to the idle control connections.
+type Agent struct {
+	host      string
+	token     string
+	mu        sync.Mutex
+	idle      map[int]chan net.Conn
+	listeners []net.Listener
+}
+
+// NewAgent creates an Agent accepting inbound traffic on the given host, empty for all interfaces.
+// Only control connections presenting the given token are served.
+func NewAgent(host, token string) *Agent {
+	return &Agent{
+		host:  host,
+		token: token,
+		idle:  map[int]chan net.Conn{},
+	}
+}
+
+// Serve accepts control connections on the given listener until the context is done.
+func (a *Agent) Serve(ctx context.Context, control net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		_ = control.Close()
+		a.close()
+	}()
+
+	for {
+		conn, err := control.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return errors.Wrap(err, "failed accepting control connection")
+		}
+		go a.register(ctx, conn)
+	}
+}
+
+// register reads the handshake of the control connection. Connection serving a remote port is kept
+// until paired with an inbound one, dial request is connected to the requested address right away.
+func (a *Agent) register(ctx context.Context, conn net.Conn) {
+	token, err := readHandshake(conn)
+	if err != nil {
+		logger().Error(err, "invalid control connection", "remote", conn.RemoteAddr().String())
+		_ = conn.Close()
+
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) != 1 {
+		logger().Info("rejecting control connection with invalid token", "remote", conn.RemoteAddr().String())
+		_ = conn.Close()
+
+		return
+	}
+
+	handshake, err := readHandshake(conn)
+	if err != nil {
+		logger().Error(err, "invalid control connection", "remote", conn.RemoteAddr().String())
+		_ = conn.Close()
+
+		return
+	}
+
+	if strings.HasPrefix(handshake, dialPrefix) {
+		dial(ctx, conn, strings.TrimPrefix(handshake, dialPrefix))
+
+		return
+	}
+
+	port, err := strconv.Atoi(handshake)
+	if err != nil {
+		logger().Error(err, "invalid port in handshake", "remote", conn.RemoteAddr().String())
+		_ = conn.Close()
+
+		return
+	}
+
+	idle, err := a.idleConnections(ctx, port)
+	if err != nil {
+		logger().Error(err, "failed listening for inbound traffic", "port", port)
+		_ = conn.Close()
+
+		return
+	}
+
+	select {
+	case idle <- conn:
+	default:
+		logger().Info("too many idle control connections, closing", "port", port)
+		_ = conn.Close()
+	}
+}
+
+// idleConnections returns the queue of idle control connections for the port, listening on it the first time.
+func (a *Agent) idleConnections(ctx context.Context, port int) (chan net.Conn, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if idle, exists := a.idle[port]; exists {
+		return idle, nil
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(a.host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, errors.WrapWithDetails(err, "failed listening", "port", port)
+	}
+	idle := make(chan net.Conn, idleConnections)
+	a.idle[port] = idle
+	a.listeners = append(a.listeners, listener)
+	logger().Info("forwarding inbound traffic", "port", port)
+
+	go func() {
+		for {
+			inbound, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go handover(ctx, inbound, idle)
+		}
+	}()
+
+	return idle, nil
+}
+
+// handover pairs the inbound connection with the first idle control connection still alive.
+func handover(ctx context.Context, inbound net.Conn, idle chan net.Conn) {
+	timeout := time.NewTimer(handoverTimeout)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case control := <-idle:
+			if _, err := control.Write([]byte{accepted}); err != nil {
+				_ = control.Close()
+
+				continue
+			}
+			pipe(inbound, control)
+
+			return
+		case <-timeout.C:
+			logger().Info("no control connection available, dropping inbound connection", "remote", inbound.RemoteAddr().String())
+			_ = inbound.Close()
+
+			return
+		case <-ctx.Done():
+			_ = inbound.Close()
+
+			return
+		}
+	}
+}
+
+// dial connects to the address on behalf of the client.
+func dial(ctx context.Context, conn net.Conn, address string) {
+	dialer := net.Dialer{Timeout: dialTimeout}
+	outbound, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		logger().Info("failed connecting", "address", address, "error", err.Error())
+		_, _ = conn.Write([]byte{rejected})
+		_ = conn.Close()
+
+		return
+	}
+	if _, err := conn.Write([]byte{accepted}); err != nil {
+		_ = outbound.Close()
+		_ = conn.Close()
+
+		return
+	}
+	pipe(conn, outbound)
+}
+
+func (a *Agent) close() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, listener := range a.listeners {
+		_ = listener.Close()
+	}
+	for _, idle := range a.idle {
+	Drain:
+		for {
+			select {
+			case conn := <-idle:
+				_ = conn.Close()
+			default:
+				break Drain
+			}
+		}
+	}
+}
+
+// readHandshake reads the handshake byte by byte, so no data following it is consumed.
+func readHandshake(conn net.Conn) (string, error) {
+	if err := conn.SetReadDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+		return "", errors.Wrap(err, "failed setting deadline")
+	}
+
+	var line strings.Builder
+	b := make([]byte, 1)
+	for line.Len() < maxHandshake {
+		if _, err := conn.Read(b); err != nil {
+			return "", errors.Wrap(err, "failed reading handshake")
+		}
+		if b[0] == '\n' {
+			return line.String(), errors.Wrap(conn.SetReadDeadline(time.Time{}), "failed resetting deadline")
+		}
+		line.WriteByte(b[0])
+	}
+
+	return "", errors.New("handshake too long")
+}
@@ -0,0 +1,188 @@
+package tunnel
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+const (
+	// DefaultPoolSize is the number of idle control connections kept open for each port.
+	DefaultPoolSize = 4
+
+	pollInterval = time.Second
+	retryBackoff = time.Second
+)
+
+// Client keeps idle control connections to the Agent and forwards the connections handed over by it
+// to the local process.
+type Client struct {
+	controlAddr string
+	token       string
+	mappings    []Mapping
+	poolSize    int
+	idle        int32
+}
+
+// NewClient creates a Client connecting to the agent on the given address, e.g. the local end of a port-forward,
+// authenticated by the token of the session.
+func NewClient(controlAddr, token string, mappings []Mapping) *Client {
+	return &Client{
+		controlAddr: controlAddr,
+		token:       token,
+		mappings:    mappings,
+		poolSize:    DefaultPoolSize,
+	}
+}
+
+// Run maintains the pool of idle control connections for every mapping until the context is done.
+func (c *Client) Run(ctx context.Context) error {
+	if len(c.mappings) == 0 {
+		return errors.New("no ports to forward")
+	}
+
+	var wg sync.WaitGroup
+	for _, m := range c.mappings {
+		for i := 0; i < c.poolSize; i++ {
+			wg.Add(1)
+			go func(m Mapping) {
+				defer wg.Done()
+				c.serve(ctx, m)
+			}(m)
+		}
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// Healthy returns an error if there is no idle control connection, so no traffic could be forwarded.
+func (c *Client) Healthy() error {
+	if atomic.LoadInt32(&c.idle) == 0 {
+		return errors.Errorf("no control connection to the agent at %s", c.controlAddr)
+	}
+
+	return nil
+}
+
+func (c *Client) serve(ctx context.Context, m Mapping) {
+	for ctx.Err() == nil {
+		conn, err := c.waitForInbound(ctx, m)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger().V(1).Info("control connection failed, retrying", "error", err.Error())
+				sleep(ctx, retryBackoff)
+			}
+
+			continue
+		}
+
+		go func() {
+			local, err := net.Dial("tcp", net.JoinHostPort("localhost", strconv.Itoa(m.Local)))
+			if err != nil {
+				logger().Error(err, "failed connecting to local process", "port", m.Local)
+				_ = conn.Close()
+
+				return
+			}
+			pipe(conn, local)
+		}()
+	}
+}
+
+// waitForInbound opens the control connection and blocks until the agent hands an inbound connection over.
+func (c *Client) waitForInbound(ctx context.Context, m Mapping) (net.Conn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.controlAddr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed connecting to agent")
+	}
+	if _, err = fmt.Fprintf(conn, "%s\n%d\n", c.token, m.Remote); err != nil {
+		_ = conn.Close()
+
+		return nil, errors.Wrap(err, "failed sending handshake")
+	}
+
+	atomic.AddInt32(&c.idle, 1)
+	defer atomic.AddInt32(&c.idle, -1)
+
+	b := make([]byte, 1)
+	for {
+		if ctx.Err() != nil {
+			_ = conn.Close()
+
+			return nil, errors.Wrap(ctx.Err(), "stopped")
+		}
+		if err := conn.SetReadDeadline(time.Now().Add(pollInterval)); err != nil {
+			_ = conn.Close()
+
+			return nil, errors.Wrap(err, "failed setting deadline")
+		}
+		_, err := conn.Read(b)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			_ = conn.Close()
+
+			return nil, errors.Wrap(err, "control connection closed")
+		}
+		if b[0] != accepted {
+			_ = conn.Close()
+
+			return nil, errors.Errorf("unexpected control byte %d", b[0])
+		}
+
+		if err := conn.SetReadDeadline(time.Time{}); err != nil {
+			_ = conn.Close()
+
+			return nil, errors.Wrap(err, "failed resetting deadline")
+		}
+
+		return conn, nil
+	}
+}
+
+// Dial connects to the address in the cluster through the agent.
+func (c *Client) Dial(ctx context.Context, address string) (net.Conn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.controlAddr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed connecting to agent")
+	}
+	if _, err = fmt.Fprintf(conn, "%s\n%s%s\n", c.token, dialPrefix, address); err != nil {
+		_ = conn.Close()
+
+		return nil, errors.Wrap(err, "failed sending dial request")
+	}
+
+	b := make([]byte, 1)
+	if _, err := conn.Read(b); err != nil {
+		_ = conn.Close()
+
+		return nil, errors.WrapWithDetails(err, "failed reading dial response", "address", address)
+	}
+	if b[0] != accepted {
+		_ = conn.Close()
+
+		return nil, errors.Errorf("agent failed connecting to %s", address)
+	}
+
+	return conn, nil
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,153 @@
+package tunnel
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+
+	"emperror.dev/errors"
+)
+
+// Subset of SOCKS5 protocol (RFC 1928) needed to CONNECT without authentication.
+const (
+	socksVersion       byte = 5
+	socksNoAuth        byte = 0
+	socksNoAcceptable  byte = 0xff
+	socksConnect       byte = 1
+	socksAddrIPv4      byte = 1
+	socksAddrDomain    byte = 3
+	socksAddrIPv6      byte = 4
+	socksSucceeded     byte = 0
+	socksHostUnreach   byte = 4
+	socksCmdNotSupport byte = 7
+)
+
+// ServeSOCKS serves SOCKS5 proxy on the given listener until the context is done. All the connections
+// are opened by the agent, so the names are resolved and the addresses are reached from within the cluster.
+func (c *Client) ServeSOCKS(ctx context.Context, listener net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return errors.Wrap(err, "failed accepting SOCKS connection")
+		}
+		go func() {
+			if err := c.serveSOCKS(ctx, conn); err != nil {
+				logger().V(1).Info("SOCKS connection failed", "error", err.Error())
+				_ = conn.Close()
+			}
+		}()
+	}
+}
+
+func (c *Client) serveSOCKS(ctx context.Context, conn net.Conn) error {
+	if err := socksNegotiate(conn); err != nil {
+		return err
+	}
+
+	address, err := socksReadRequest(conn)
+	if err != nil {
+		return err
+	}
+
+	outbound, err := c.Dial(ctx, address)
+	if err != nil {
+		_ = socksReply(conn, socksHostUnreach)
+
+		return err
+	}
+	if err := socksReply(conn, socksSucceeded); err != nil {
+		_ = outbound.Close()
+
+		return err
+	}
+	pipe(conn, outbound)
+
+	return nil
+}
+
+func socksNegotiate(conn net.Conn) error {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return errors.Wrap(err, "failed reading SOCKS greeting")
+	}
+	if header[0] != socksVersion {
+		return errors.Errorf("unsupported SOCKS version %d", header[0])
+	}
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return errors.Wrap(err, "failed reading SOCKS methods")
+	}
+	for _, method := range methods {
+		if method == socksNoAuth {
+			_, err := conn.Write([]byte{socksVersion, socksNoAuth})
+
+			return errors.Wrap(err, "failed writing SOCKS method")
+		}
+	}
+	_, _ = conn.Write([]byte{socksVersion, socksNoAcceptable})
+
+	return errors.New("SOCKS client requires authentication")
+}
+
+func socksReadRequest(conn net.Conn) (string, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", errors.Wrap(err, "failed reading SOCKS request")
+	}
+	if header[1] != socksConnect {
+		_ = socksReply(conn, socksCmdNotSupport)
+
+		return "", errors.Errorf("unsupported SOCKS command %d", header[1])
+	}
+
+	var host string
+	switch header[3] {
+	case socksAddrIPv4, socksAddrIPv6:
+		size := net.IPv4len
+		if header[3] == socksAddrIPv6 {
+			size = net.IPv6len
+		}
+		ip := make(net.IP, size)
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return "", errors.Wrap(err, "failed reading SOCKS address")
+		}
+		host = ip.String()
+	case socksAddrDomain:
+		size := make([]byte, 1)
+		if _, err := io.ReadFull(conn, size); err != nil {
+			return "", errors.Wrap(err, "failed reading SOCKS address")
+		}
+		domain := make([]byte, size[0])
+		if _, err := io.ReadFull(conn, domain); err != nil {
+			return "", errors.Wrap(err, "failed reading SOCKS address")
+		}
+		host = string(domain)
+	default:
+		return "", errors.Errorf("unsupported SOCKS address type %d", header[3])
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return "", errors.Wrap(err, "failed reading SOCKS port")
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), nil
+}
+
+func socksReply(conn net.Conn, status byte) error {
+	// bound address is not relevant for CONNECT, so 0.0.0.0:0 is always used
+	_, err := conn.Write([]byte{socksVersion, status, 0, socksAddrIPv4, 0, 0, 0, 0, 0, 0})
+
+	return errors.Wrap(err, "failed writing SOCKS reply")
+}
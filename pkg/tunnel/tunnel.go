@@ -0,0 +1,109 @@
+// Package tunnel implements a minimal reverse tunnel between an agent running in the cloned pod and
+// the developer machine.
+//
+// The agent accepts the inbound traffic of the clone and hands every connection over to one of the idle control
+// connections opened by the client beforehand, e.g. through a port-forward. The client then dials the local
+// process and pipes the data in both directions.
+//
+// Control connections are also used to reach the cluster from the developer machine, e.g. through the SOCKS proxy
+// served by the client.
+//
+// Every control connection starts with the session token followed by a new line. The agent closes connections
+// presenting a different token, so only the client holding the token of the session can take the traffic over.
+//
+// Protocol of a single control connection:
+//  * client sends the remote port it serves followed by a new line, e.g. "9080\n"
+//  * agent sends a single accepted byte once an inbound connection on that port is paired with it
+//  * from then on the connection carries the raw data of the inbound one
+//
+// Protocol of an outbound connection:
+//  * client sends dial request with the address in the cluster followed by a new line, e.g. "dial ratings:9080\n"
+//  * agent sends accepted byte once connected, rejected byte otherwise
+//  * from then on the connection carries the raw data of the outbound one
+package tunnel
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+
+	"github.com/maistra/istio-workspace/pkg/log"
+)
+
+var logger = func() logr.Logger {
+	return log.Log.WithValues("type", "tunnel")
+}
+
+const (
+	// DefaultControlPort is the port agent listens on for control connections.
+	DefaultControlPort = 10101
+	// TokenEnvVar is the environment variable holding the session token the agent expects from the client.
+	TokenEnvVar = "IKE_TUNNEL_TOKEN"
+
+	accepted byte = 1
+	rejected byte = 0
+
+	dialPrefix = "dial "
+)
+
+// Mapping defines which port of the local process serves the traffic of the remote port of the clone.
+type Mapping struct {
+	Local  int
+	Remote int
+}
+
+// ParseMapping parses port definition in the format local[:remote]. Remote port defaults to the local one.
+func ParseMapping(port string) (Mapping, error) {
+	parts := strings.SplitN(port, ":", 2)
+	local, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return Mapping{}, errors.Errorf("expected port in format local[:remote], got %s", port)
+	}
+	remote := local
+	if len(parts) == 2 {
+		if remote, err = strconv.Atoi(parts[1]); err != nil {
+			return Mapping{}, errors.Errorf("expected port in format local[:remote], got %s", port)
+		}
+	}
+
+	return Mapping{Local: local, Remote: remote}, nil
+}
+
+// NewToken generates random token authenticating the control connections of the session.
+func NewToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed generating token")
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// pipe copies the data in both directions until one of the sides is done and closes both connections.
+func pipe(a, b net.Conn) {
+	var once sync.Once
+	closeBoth := func() {
+		_ = a.Close()
+		_ = b.Close()
+	}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(a, b)
+		once.Do(closeBoth)
+	}()
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(b, a)
+		once.Do(closeBoth)
+	}()
+	wg.Wait()
+}
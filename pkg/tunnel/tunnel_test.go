@@ -0,0 +1,182 @@
+package tunnel_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/maistra/istio-workspace/pkg/tunnel"
+)
+
+var _ = Describe("Reverse tunnel", func() {
+
+	Context("port mapping", func() {
+
+		It("should use local port as remote one if not specified", func() {
+			Expect(tunnel.ParseMapping("9080")).To(Equal(tunnel.Mapping{Local: 9080, Remote: 9080}))
+		})
+
+		It("should parse local and remote port", func() {
+			Expect(tunnel.ParseMapping("4321:9080")).To(Equal(tunnel.Mapping{Local: 4321, Remote: 9080}))
+		})
+
+		It("should fail on invalid port", func() {
+			_, err := tunnel.ParseMapping("http:9080")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("expected port in format local[:remote]"))
+		})
+	})
+
+	Context("forwarding", func() {
+
+		var (
+			ctx        context.Context
+			cancel     context.CancelFunc
+			local      net.Listener
+			remotePort int
+			client     *tunnel.Client
+			control    net.Listener
+		)
+
+		BeforeEach(func() {
+			ctx, cancel = context.WithCancel(context.Background())
+
+			// local process greeting and echoing back upper cased lines
+			var err error
+			local, err = net.Listen("tcp", "localhost:0")
+			Expect(err).ToNot(HaveOccurred())
+			go serveEcho(local)
+
+			control, err = net.Listen("tcp", "localhost:0")
+			Expect(err).ToNot(HaveOccurred())
+			go func() {
+				defer GinkgoRecover()
+				Expect(tunnel.NewAgent("localhost", "s3cr3t").Serve(ctx, control)).To(Succeed())
+			}()
+
+			remotePort = freePort()
+			client = tunnel.NewClient(control.Addr().String(), "s3cr3t", []tunnel.Mapping{{Local: port(local), Remote: remotePort}})
+			go func() {
+				defer GinkgoRecover()
+				Expect(client.Run(ctx)).To(Succeed())
+			}()
+		})
+
+		AfterEach(func() {
+			cancel()
+			_ = local.Close()
+		})
+
+		It("should forward inbound connection to the local process", func() {
+			Eventually(client.Healthy, 5*time.Second).Should(Succeed())
+			Eventually(func() error {
+				conn, err := net.Dial("tcp", net.JoinHostPort("localhost", strconv.Itoa(remotePort)))
+				if err != nil {
+					return err
+				}
+				_ = conn.Close()
+
+				return nil
+			}, 5*time.Second).Should(Succeed())
+
+			conn, err := net.Dial("tcp", net.JoinHostPort("localhost", strconv.Itoa(remotePort)))
+			Expect(err).ToNot(HaveOccurred())
+			defer conn.Close()
+
+			reader := bufio.NewReader(conn)
+			greeting, err := reader.ReadString('\n')
+			Expect(err).ToNot(HaveOccurred())
+			Expect(greeting).To(Equal("hello\n"))
+
+			_, err = fmt.Fprintln(conn, "ping")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reader.ReadString('\n')).To(Equal("ping\n"))
+		})
+
+		It("should not hand inbound connection over to the client with invalid token", func() {
+			interceptedPort := freePort()
+			intruder := tunnel.NewClient(control.Addr().String(), "guess", []tunnel.Mapping{{Local: port(local), Remote: interceptedPort}})
+			go func() {
+				defer GinkgoRecover()
+				Expect(intruder.Run(ctx)).To(Succeed())
+			}()
+
+			// agent does not even start listening on the port requested by the intruder
+			Consistently(func() error {
+				conn, err := net.Dial("tcp", net.JoinHostPort("localhost", strconv.Itoa(interceptedPort)))
+				if err == nil {
+					_ = conn.Close()
+				}
+
+				return err
+			}, time.Second, 100*time.Millisecond).Should(HaveOccurred())
+		})
+
+		It("should not dial the cluster for the client with invalid token", func() {
+			intruder := tunnel.NewClient(control.Addr().String(), "guess", nil)
+
+			_, err := intruder.Dial(ctx, local.Addr().String())
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should forward multiple connections at once", func() {
+			Eventually(client.Healthy, 5*time.Second).Should(Succeed())
+
+			conns := []net.Conn{}
+			for i := 0; i < 3; i++ {
+				var conn net.Conn
+				Eventually(func() (err error) {
+					conn, err = net.Dial("tcp", net.JoinHostPort("localhost", strconv.Itoa(remotePort)))
+
+					return err
+				}, 5*time.Second).Should(Succeed())
+				conns = append(conns, conn)
+			}
+
+			for i, conn := range conns {
+				reader := bufio.NewReader(conn)
+				Expect(reader.ReadString('\n')).To(Equal("hello\n"))
+				_, err := fmt.Fprintf(conn, "conn-%d\n", i)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(reader.ReadString('\n')).To(Equal(fmt.Sprintf("conn-%d\n", i)))
+				_ = conn.Close()
+			}
+		})
+	})
+})
+
+func serveEcho(l net.Listener) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go func(conn net.Conn) {
+			defer conn.Close()
+			_, _ = fmt.Fprintln(conn, "hello")
+			scanner := bufio.NewScanner(conn)
+			for scanner.Scan() {
+				_, _ = fmt.Fprintln(conn, scanner.Text())
+			}
+		}(conn)
+	}
+}
+
+func port(l net.Listener) int {
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func freePort() int {
+	l, err := net.Listen("tcp", "localhost:0")
+	Expect(err).ToNot(HaveOccurred())
+	defer l.Close()
+
+	return port(l)
+}
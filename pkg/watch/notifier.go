@@ -0,0 +1,191 @@
+package watch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultPollInterval defines how often watched directories are scanned for changes when polling.
+const DefaultPollInterval = time.Second
+
+// notifier delivers change events of the files in the watched paths (non-recursively).
+type notifier interface {
+	Add(path string) error
+	Events() <-chan fsnotify.Event
+	Errors() <-chan error
+	Close() error
+}
+
+// fsNotifier relies on file system notifications, such as inotify.
+type fsNotifier struct {
+	watcher *fsnotify.Watcher
+}
+
+func newFsNotifier() (notifier, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating fs watch")
+	}
+
+	return &fsNotifier{watcher: watcher}, nil
+}
+
+func (f *fsNotifier) Add(path string) error {
+	return errors.WithStack(f.watcher.Add(path))
+}
+
+func (f *fsNotifier) Events() <-chan fsnotify.Event {
+	return f.watcher.Events
+}
+
+func (f *fsNotifier) Errors() <-chan error {
+	return f.watcher.Errors
+}
+
+func (f *fsNotifier) Close() error {
+	return errors.WithStack(f.watcher.Close())
+}
+
+// notificationsExhausted tells if the error is caused by reaching limits of inotify instances or watches
+// (fs.inotify.max_user_instances and fs.inotify.max_user_watches respectively).
+func notificationsExhausted(err error) bool {
+	return errors.Is(err, syscall.ENOSPC) || errors.Is(err, syscall.EMFILE)
+}
+
+// poller detects changes by periodically comparing modification times and sizes of the files in the watched paths.
+// Unlike file system notifications it works on network file systems or bind mounts of Docker Desktop and WSL,
+// and is not constrained by inotify limits.
+type poller struct {
+	mu        sync.Mutex
+	interval  time.Duration
+	snapshots map[string]map[string]os.FileInfo // watched path -> files it contains
+	events    chan fsnotify.Event
+	errors    chan error
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newPoller(interval time.Duration) notifier {
+	p := &poller{
+		interval:  interval,
+		snapshots: map[string]map[string]os.FileInfo{},
+		events:    make(chan fsnotify.Event),
+		errors:    make(chan error),
+		done:      make(chan struct{}),
+	}
+	go p.run()
+
+	return p
+}
+
+func (p *poller) Add(path string) error {
+	snapshot, err := scan(path)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed scanning path", "path", path)
+	}
+	p.mu.Lock()
+	p.snapshots[path] = snapshot
+	p.mu.Unlock()
+
+	return nil
+}
+
+func (p *poller) Events() <-chan fsnotify.Event {
+	return p.events
+}
+
+func (p *poller) Errors() <-chan error {
+	return p.errors
+}
+
+func (p *poller) Close() error {
+	p.closeOnce.Do(func() {
+		close(p.done)
+	})
+
+	return nil
+}
+
+func (p *poller) run() {
+	tick := time.NewTicker(p.interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			p.poll()
+		case <-p.done:
+			return
+		}
+	}
+}
+
+func (p *poller) poll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for path, previous := range p.snapshots {
+		current, err := scan(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			p.sendError(errors.WrapIfWithDetails(err, "failed scanning path", "path", path))
+
+			continue
+		}
+		for name, info := range current {
+			old, found := previous[name]
+			switch {
+			case !found:
+				p.sendEvent(fsnotify.Event{Name: name, Op: fsnotify.Create})
+			case !info.IsDir() && (!info.ModTime().Equal(old.ModTime()) || info.Size() != old.Size()):
+				p.sendEvent(fsnotify.Event{Name: name, Op: fsnotify.Write})
+			}
+		}
+		for name := range previous {
+			if _, found := current[name]; !found {
+				p.sendEvent(fsnotify.Event{Name: name, Op: fsnotify.Remove})
+			}
+		}
+		p.snapshots[path] = current
+	}
+}
+
+// sendEvent delivers the event unless the poller is closed in the meantime.
+func (p *poller) sendEvent(event fsnotify.Event) {
+	select {
+	case p.events <- event:
+	case <-p.done:
+	}
+}
+
+func (p *poller) sendError(err error) {
+	select {
+	case p.errors <- err:
+	case <-p.done:
+	}
+}
+
+// scan lists files of the directory, or the file itself if path is not a directory.
+func scan(path string) (map[string]os.FileInfo, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if !info.IsDir() {
+		return map[string]os.FileInfo{path: info}, nil
+	}
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	snapshot := make(map[string]os.FileInfo, len(entries))
+	for _, entry := range entries {
+		snapshot[filepath.Join(path, entry.Name())] = entry
+	}
+
+	return snapshot, nil
+}
@@ -2,8 +2,10 @@ package watch
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -22,27 +24,55 @@ var logger = func() logr.Logger {
 // Handler allows to define how to react on file changes event.
 type Handler func(events []fsnotify.Event) error
 
+// ignoreFiles lists files defining patterns of the paths which should not be watched, in .gitignore format.
+// They are respected in all the watched directories, not only in the top-level ones.
+var ignoreFiles = []string{".gitignore", ".ikeignore"}
+
+// maxUserWatchesFile holds the limit of inotify watches per user.
+const maxUserWatchesFile = "/proc/sys/fs/inotify/max_user_watches"
+
 // Watch represents single file system watch and delegates change events to defined handler.
 type Watch struct {
-	watcher    *fsnotify.Watcher
-	handlers   []Handler
-	basePaths  []string
-	gitignores []ignore.GitIgnore
-	interval   time.Duration
-	done       chan struct{}
+	notifier  notifier
+	polling   bool
+	handlers  []Handler
+	basePaths []string
+	ignores   []ignores
+	interval  time.Duration
+	quiet     time.Duration
+	done      chan struct{}
+}
+
+// ignores holds patterns matched against paths relative to the directory they are defined for.
+type ignores struct {
+	dir     string
+	matcher *ignore.GitIgnore
+}
+
+func (i ignores) matches(path string, dir bool) bool {
+	relative, err := filepath.Rel(i.dir, path)
+	if err != nil || relative == "." || strings.HasPrefix(relative, "..") {
+		return false
+	}
+	if dir {
+		relative += "/"
+	}
+
+	return i.matcher.MatchesPath(relative)
 }
 
 // Start observes on file change events and dispatches them to defined handler in batches every
-// given interval.
+// given interval, once no more changes occurred within the quiet period.
 func (w *Watch) Start() {
 	// Dispatch fsnotify events
 	go func() {
 		tick := time.NewTicker(w.interval)
 		events := make(map[string]fsnotify.Event)
+		var lastChange time.Time
 	OutOfFor:
 		for {
 			select {
-			case event, ok := <-w.watcher.Events:
+			case event, ok := <-w.notifier.Events():
 				if !ok {
 					return
 				}
@@ -54,13 +84,14 @@ func (w *Watch) Start() {
 				logger().V(1).Info("file changed", "file", event.Name, "op", event.Op.String())
 				fmt.Printf("file changed %s %s %s %s\n", "file", event.Name, "op", event.Op.String())
 				events[event.Name] = event
-			case err, ok := <-w.watcher.Errors:
+				lastChange = time.Now()
+			case err, ok := <-w.notifier.Errors():
 				if !ok {
 					return
 				}
 				logger().Error(err, "failed while watching")
 			case <-tick.C:
-				if len(events) == 0 {
+				if len(events) == 0 || time.Since(lastChange) < w.quiet {
 					continue
 				}
 				logger().V(1).Info("firing change event")
@@ -79,19 +110,15 @@ func (w *Watch) Start() {
 	}()
 }
 
-// Excluded checks whether a path is excluded from watch by first inspecting .gitignores
-// and user-defined exclusions.
+// Excluded checks whether a path is excluded from watch by inspecting .gitignore and .ikeignore files
+// of the watched directories and user-defined exclusions.
 func (w *Watch) Excluded(path string) bool {
-	reducedPath := path
-	for _, basePath := range w.basePaths {
-		if strings.HasPrefix(path, basePath) {
-			reducedPath = strings.TrimPrefix(path, basePath)
+	return w.excluded(path, false)
+}
 
-			break
-		}
-	}
-	for _, gitIgnore := range w.gitignores {
-		if gitIgnore.MatchesPath(reducedPath) {
+func (w *Watch) excluded(path string, dir bool) bool {
+	for _, rules := range w.ignores {
+		if rules.matches(path, dir) {
 			return true
 		}
 	}
@@ -99,11 +126,11 @@ func (w *Watch) Excluded(path string) bool {
 	return false
 }
 
-// Close attempts to close underlying fsnotify.Watcher.
+// Close attempts to close underlying notifier.
 // In case of failure it logs the error.
 func (w *Watch) Close() {
 	w.done <- struct{}{}
-	if e := w.watcher.Close(); e != nil {
+	if e := w.notifier.Close(); e != nil {
 		logger().Error(e, "failed closing watch")
 	}
 }
@@ -112,11 +139,11 @@ func (w *Watch) Close() {
 func (w *Watch) addPath(filePath string) error {
 	w.basePaths = append(w.basePaths, filePath)
 
-	return errors.WrapIfWithDetails(w.watcher.Add(filePath), "failed adding path", "path", filePath)
+	return errors.WrapIfWithDetails(w.notifier.Add(filePath), "failed adding path", "path", filePath)
 }
 
 // addRecursiveWatch handles adding watches recursively for the path provided
-// and its subdirectories. If a non-directory is specified, this call is a no-op.
+// and its subdirectories, skipping the excluded ones. If a non-directory is specified, this call is a no-op.
 //
 // Based on https://github.com/openshift/origin/blob/85eb37b3/pkg/util/fsnotification/fsnotification.go.
 func (w *Watch) addRecursiveWatch(filePath string) error {
@@ -133,25 +160,32 @@ func (w *Watch) addRecursiveWatch(filePath string) error {
 		return nil
 	}
 
-	folders, err := allSubFoldersOf(filePath)
-	if err != nil {
-		return err
-	}
-
-	for _, v := range folders {
-		logger().V(1).Info(fmt.Sprintf("adding watch on filePath %s", v))
-		err = w.addPath(v)
+	return errors.WithStack(filepath.Walk(filePath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			// "no space left on device" issues are usually resolved via
-			// $ sudo sysctl fs.inotify.max_user_watches=65536
-			return errors.WithDetails(err, "error adding watcher for filePath", "path", v)
+			return err
 		}
-	}
+		if !info.IsDir() {
+			return nil
+		}
+		if w.excluded(path, true) {
+			logger().V(1).Info(fmt.Sprintf("skipping excluded directory %s", path))
 
-	return nil
+			return filepath.SkipDir
+		}
+		if e := w.addIgnoreFiles(path); e != nil {
+			return e
+		}
+
+		logger().V(1).Info(fmt.Sprintf("adding watch on filePath %s", path))
+		if e := w.addPath(path); e != nil {
+			return errors.WithDetails(e, "error adding watcher for filePath", "path", path)
+		}
+
+		return nil
+	}))
 }
 
-func (w *Watch) addExclusions(exclusions []string) error {
+func (w *Watch) addExclusions(dir string, exclusions []string) error {
 	if len(exclusions) == 0 {
 		return nil
 	}
@@ -159,45 +193,47 @@ func (w *Watch) addExclusions(exclusions []string) error {
 	if e != nil {
 		return errors.Wrapf(e, "failed adding exclusion list %v", exclusions)
 	}
-	w.gitignores = append(w.gitignores, *gitIgnore)
+	w.ignores = append(w.ignores, ignores{dir: dir, matcher: gitIgnore})
 
 	return nil
 }
 
-// addGitIgnore adds .gitignore rules to the watcher if the file exists in the given path.
-func (w *Watch) addGitIgnore(path string) error {
-	gitIgnorePath := path + string(os.PathSeparator) + ".gitignore"
-	file, err := os.Open(gitIgnorePath)
-	if err == nil {
-		err := file.Close()
-		if err != nil {
-			return errors.WrapWithDetails(err, "failed closing file", "path", gitIgnorePath)
+// addIgnoreFiles adds rules of .gitignore and .ikeignore to the watcher if the files exist in the given directory.
+func (w *Watch) addIgnoreFiles(dir string) error {
+	for _, name := range ignoreFiles {
+		ignorePath := filepath.Join(dir, name)
+		if _, err := os.Stat(ignorePath); err != nil {
+			continue
 		}
-		gitIgnore, err := ignore.CompileIgnoreFile(gitIgnorePath)
+		matcher, err := ignore.CompileIgnoreFile(ignorePath)
 		if err != nil {
-			return errors.WrapWithDetails(err, "failed compiling ignore list from .gitignore", "path", gitIgnorePath)
+			return errors.WrapWithDetails(err, "failed compiling ignore list", "path", ignorePath)
 		}
-		w.gitignores = append(w.gitignores, *gitIgnore)
+		w.ignores = append(w.ignores, ignores{dir: dir, matcher: matcher})
 	}
 
 	return nil
 }
 
-// allSubFoldersOf recursively retrieves all subfolders of the specified path.
-func allSubFoldersOf(filePath string) (paths []string, err error) {
-	err = filepath.Walk(filePath, func(newPath string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if info.IsDir() {
-			paths = append(paths, newPath)
-		}
-
-		return nil
-	})
-
-	return
+// reportWatches logs the number of watched paths and warns when it gets close to the limit of inotify watches.
+func (w *Watch) reportWatches() {
+	logger().Info(fmt.Sprintf("watching %d directories", len(w.basePaths)), "polling", w.polling)
+	if w.polling {
+		return
+	}
+	content, err := ioutil.ReadFile(maxUserWatchesFile)
+	if err != nil {
+		return
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		return
+	}
+	const threshold = 0.8
+	if float64(len(w.basePaths)) >= threshold*float64(limit) {
+		logger().Info(fmt.Sprintf("WARN: watching %d directories is close to the limit of %d inotify watches. "+
+			"Exclude some of them in .ikeignore, increase fs.inotify.max_user_watches or use polling instead", len(w.basePaths), limit))
+	}
 }
 
 // extractEvents takes a map and returns slice of all values.
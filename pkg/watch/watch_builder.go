@@ -1,17 +1,18 @@
 package watch
 
 import (
+	"fmt"
 	"os"
 	"time"
 
 	"emperror.dev/errors"
-	"github.com/fsnotify/fsnotify"
 )
 
 // Builder is a struct which allows to use fluent API to create underlying instance of Watch.
 type Builder struct {
-	w          *Watch
-	exclusions []string
+	w            *Watch
+	exclusions   []string
+	pollInterval time.Duration
 }
 
 // CreateWatch creates instance of Builder providing fluent functions to customize watch
@@ -30,6 +31,24 @@ func (wb *Builder) WithHandlers(handlers ...Handler) *Builder {
 	return wb
 }
 
+// Debouncing delays handling of the changes until no more of them occur within the quiet period,
+// so that e.g. saving several files at once or running a code generator results in a single batch of events.
+func (wb *Builder) Debouncing(quietPeriod time.Duration) *Builder {
+	wb.w.quiet = quietPeriod
+
+	return wb
+}
+
+// Polling makes the watch scan watched directories every interval instead of relying on file system notifications,
+// which are not delivered e.g. on network file systems or bind mounts of Docker Desktop and WSL.
+// Polling (every DefaultPollInterval) is also used when inotify limits are exhausted.
+func (wb *Builder) Polling(interval time.Duration) *Builder {
+	wb.w.polling = true
+	wb.pollInterval = interval
+
+	return wb
+}
+
 // Excluding allows to define exclusion patterns (as glob expressions).
 func (wb *Builder) Excluding(exclusions ...string) *Builder {
 	wb.exclusions = exclusions
@@ -38,38 +57,78 @@ func (wb *Builder) Excluding(exclusions ...string) *Builder {
 }
 
 // OnPaths defines paths to be watched.
-// If path is a directory it will recursively watch all files and subdirectories.
+// If path is a directory it will recursively watch all files and subdirectories which are not excluded.
 // If path is a file only this path is watched.
+// When inotify limits are exhausted it falls back to polling.
 func (wb *Builder) OnPaths(paths ...string) (watch *Watch, err error) {
-	fsWatcher, err := fsnotify.NewWatcher()
+	if wb.pollInterval == 0 {
+		wb.pollInterval = DefaultPollInterval
+	}
+	if !wb.w.polling {
+		err := wb.addNotifiedPaths(paths)
+		if !notificationsExhausted(err) {
+			return wb.watch(err)
+		}
+		logger().Info(fmt.Sprintf("WARN: limits of inotify are exhausted (%s), falling back to polling", err.Error()))
+		wb.closeNotifier()
+		wb.w.basePaths, wb.w.ignores = nil, nil
+		wb.w.polling = true
+	}
+	wb.w.notifier = newPoller(wb.pollInterval)
+
+	return wb.watch(wb.addPaths(paths))
+}
+
+func (wb *Builder) addNotifiedPaths(paths []string) error {
+	fsNotifier, err := newFsNotifier()
 	if err != nil {
-		return nil, errors.Wrap(err, "failed creating fs watch")
+		return err
 	}
+	wb.w.notifier = fsNotifier
 
-	wb.w.watcher = fsWatcher
+	return wb.addPaths(paths)
+}
+
+func (wb *Builder) watch(err error) (*Watch, error) {
+	if err != nil {
+		wb.closeNotifier()
+
+		return nil, err
+	}
+	wb.w.reportWatches()
+
+	return wb.w, nil
+}
 
+func (wb *Builder) closeNotifier() {
+	if wb.w.notifier == nil {
+		return
+	}
+	if e := wb.w.notifier.Close(); e != nil {
+		logger().Error(e, "failed closing watch")
+	}
+}
+
+func (wb *Builder) addPaths(paths []string) error {
 	for _, path := range paths {
 		dir, err := os.Stat(path)
 		if err != nil {
-			return nil, errors.WrapWithDetails(err, "failed checking path", "path", path)
+			return errors.WrapWithDetails(err, "failed checking path", "path", path)
 		}
 
 		if !dir.IsDir() {
 			if e := wb.w.addPath(path); e != nil {
-				return nil, e
+				return e
 			}
 		} else {
-			if e := wb.w.addExclusions(wb.exclusions); e != nil {
-				return nil, e
-			}
-			if e := wb.w.addGitIgnore(path); e != nil {
-				return nil, e
+			if e := wb.w.addExclusions(path, wb.exclusions); e != nil {
+				return e
 			}
 			if e := wb.w.addRecursiveWatch(path); e != nil {
-				return nil, e
+				return e
 			}
 		}
 	}
 
-	return wb.w, nil
+	return nil
 }
@@ -3,6 +3,8 @@ package watch_test
 import (
 	"fmt"
 	"path/filepath"
+	"sync/atomic"
+	"time"
 
 	"emperror.dev/errors"
 	"github.com/fsnotify/fsnotify"
@@ -177,6 +179,89 @@ src/main/**/*.java
 		// then
 		Eventually(done).Should(BeClosed())
 	})
+
+	It("should respect nested .gitignore and .ikeignore files", func() {
+		// given
+		done := make(chan struct{})
+
+		watchTmpDir := TmpDir(GinkgoT(), "watch")
+		bundle := TmpFile(GinkgoT(), watchTmpDir+"/frontend/dist/bundle.js", "content")
+		scratch := TmpFile(GinkgoT(), watchTmpDir+"/backend/notes.tmp", "content")
+		_ = TmpFile(GinkgoT(), watchTmpDir+"/frontend/.gitignore", "dist/")
+		_ = TmpFile(GinkgoT(), watchTmpDir+"/.ikeignore", "*.tmp")
+		code := TmpFile(GinkgoT(), watchTmpDir+"/dist/main.go", "package main")
+
+		watcher, e := watch.CreateWatch(1).
+			WithHandlers(notExpectFileChange(bundle.Name(), scratch.Name()), expectFileChange(code.Name(), done)).
+			OnPaths(watchTmpDir)
+		Expect(e).ToNot(HaveOccurred())
+
+		defer watcher.Close()
+
+		// when
+		watcher.Start()
+		_, _ = bundle.WriteString(" should not be watched")
+		_, _ = scratch.WriteString(" should not be watched")
+		_, _ = code.WriteString("\n // Bla! Should trigger watch reaction, as dist/ is ignored only in frontend")
+
+		// then
+		Eventually(done).Should(BeClosed())
+	})
+
+	It("should recognize file change when polling", func() {
+		// given
+		done := make(chan struct{})
+		tmpDir := TmpDir(GinkgoT(), "watch_polling")
+		text := TmpFile(GinkgoT(), tmpDir+"/nested/text.txt", "text text text")
+
+		watcher, e := watch.CreateWatch(1).
+			WithHandlers(expectFileChange(text.Name(), done)).
+			Polling(10 * time.Millisecond).
+			OnPaths(tmpDir)
+		Expect(e).ToNot(HaveOccurred())
+
+		defer watcher.Close()
+
+		// when
+		watcher.Start()
+		_, _ = text.WriteString(" modified!")
+
+		// then
+		Eventually(done).Should(BeClosed())
+	})
+
+	It("should handle changes in a single batch once they stop within the quiet period", func() {
+		// given
+		var batches int32
+		tmpDir := TmpDir(GinkgoT(), "watch_debounce")
+		code := TmpFile(GinkgoT(), tmpDir+"/main.go", "package main")
+		text := TmpFile(GinkgoT(), tmpDir+"/text.txt", "text text text")
+
+		watcher, e := watch.CreateWatch(1).
+			WithHandlers(func(events []fsnotify.Event) error {
+				atomic.AddInt32(&batches, 1)
+
+				return nil
+			}).
+			Debouncing(100 * time.Millisecond).
+			OnPaths(tmpDir)
+		Expect(e).ToNot(HaveOccurred())
+
+		defer watcher.Close()
+
+		// when
+		watcher.Start()
+		for i := 0; i < 5; i++ {
+			_, _ = code.WriteString("\n // Bla!")
+			_, _ = text.WriteString(" modified!")
+			time.Sleep(20 * time.Millisecond)
+		}
+
+		// then
+		Consistently(func() int32 { return atomic.LoadInt32(&batches) }, 50*time.Millisecond).Should(BeZero())
+		Eventually(func() int32 { return atomic.LoadInt32(&batches) }).Should(Equal(int32(1)))
+		Consistently(func() int32 { return atomic.LoadInt32(&batches) }, 200*time.Millisecond).Should(Equal(int32(1)))
+	})
 })
 
 func expectFileChange(fileName string, done chan<- struct{}) watch.Handler {
@@ -1,8 +1,9 @@
-{{ if .Data.Has "/spec/template/spec/containers/0/livenessProbe" }}
-{"op": "remove", "path": "/spec/template/spec/containers/0/livenessProbe"},
+{{ $c := .Data.ContainerIndex (index .Vars "container") }}
+{{ if .Data.Has (print "/spec/template/spec/containers/" $c "/livenessProbe") }}
+{"op": "remove", "path": "/spec/template/spec/containers/{{$c}}/livenessProbe"},
 {{ end }}
-{{ if .Data.Has "/spec/template/spec/containers/0/readinessProbe" }}
-{"op": "remove", "path": "/spec/template/spec/containers/0/readinessProbe"},
+{{ if .Data.Has (print "/spec/template/spec/containers/" $c "/readinessProbe") }}
+{"op": "remove", "path": "/spec/template/spec/containers/{{$c}}/readinessProbe"},
 {{ end }}
 {{ if .Data.Has "/metadata/resourceVersion" }}
 {"op": "remove", "path": "/metadata/resourceVersion"},
@@ -1,15 +1,18 @@
+{{ $subsetLabel := or (index .Vars "subsetLabel") "version" }}
+{{ $label := escapeJSONPointer $subsetLabel }}
+{{ $labelSource := escapeJSONPointer (print $subsetLabel "-source") }}
 {{ if not (.Data.Has "/spec/template/metadata") }}
 {"op": "add", "path": "/spec/template/metadata", "value": {}},
 {{ end }}
 {{ if not (.Data.Has "/spec/template/metadata/labels") }}
 {"op": "add", "path": "/spec/template/metadata/labels", "value": {}},
 {{ end }}
-{{ if .Data.Has "/spec/template/metadata/labels/version" }}
-{"op": "copy", "from": "/spec/template/metadata/labels/version", "path": "/spec/template/metadata/labels/version-source"},
-{"op": "replace", "path": "/spec/template/metadata/labels/version", "value": "{{.NewVersion}}"},
+{{ if .Data.Has (print "/spec/template/metadata/labels/" $label) }}
+{"op": "copy", "from": "/spec/template/metadata/labels/{{$label}}", "path": "/spec/template/metadata/labels/{{$labelSource}}"},
+{"op": "replace", "path": "/spec/template/metadata/labels/{{$label}}", "value": "{{.NewVersion}}"},
 {{ end }}
-{{ if not (.Data.Has "/spec/template/metadata/labels/version") }}
-{"op": "add", "path": "/spec/template/metadata/labels/version", "value": "{{.NewVersion}}"},
+{{ if not (.Data.Has (print "/spec/template/metadata/labels/" $label)) }}
+{"op": "add", "path": "/spec/template/metadata/labels/{{$label}}", "value": "{{.NewVersion}}"},
 {{ end }}
 {{ if not (.Data.Has "/spec/selector") }}
 {"op": "add", "path": "/spec/selector", "value": {}},
@@ -18,22 +21,22 @@
   {{ if not (.Data.Has "/spec/selector/matchLabels") }}
   {"op": "add", "path": "/spec/selector/matchLabels", "value": {}},
   {{ end }}
-  {{ if .Data.Has "/spec/selector/matchLabels/version" }}
-  {"op": "replace", "path": "/spec/selector/matchLabels/version", "value": "{{.NewVersion}}"},
+  {{ if .Data.Has (print "/spec/selector/matchLabels/" $label) }}
+  {"op": "replace", "path": "/spec/selector/matchLabels/{{$label}}", "value": "{{.NewVersion}}"},
   {{ end }}
-  {{ if not (.Data.Has "/spec/selector/matchLabels/version") }}
-  {"op": "add", "path": "/spec/selector/matchLabels/version", "value": "{{.NewVersion}}"},
+  {{ if not (.Data.Has (print "/spec/selector/matchLabels/" $label)) }}
+  {"op": "add", "path": "/spec/selector/matchLabels/{{$label}}", "value": "{{.NewVersion}}"},
   {{ end }}
 {{ end }}
 {{ if .Data.Equal "/kind" "DeploymentConfig" }}
-  {{ if .Data.Has "/spec/selector/version" }}
-  {"op": "replace", "path": "/spec/selector/version", "value": "{{.NewVersion}}"},
+  {{ if .Data.Has (print "/spec/selector/" $label) }}
+  {"op": "replace", "path": "/spec/selector/{{$label}}", "value": "{{.NewVersion}}"},
   {{ end }}
-  {{ if not (.Data.Has "/spec/selector/version") }}
-  {"op": "add", "path": "/spec/selector/version", "value": "{{.NewVersion}}"},
+  {{ if not (.Data.Has (print "/spec/selector/" $label)) }}
+  {"op": "add", "path": "/spec/selector/{{$label}}", "value": "{{.NewVersion}}"},
   {{ end }}
 {{ end }}
-{{ if .Data.Has "/metadata/labels/version" }}
-{"op": "replace", "path": "/metadata/labels/version", "value": "{{.NewVersion}}"},
+{{ if .Data.Has (print "/metadata/labels/" $label) }}
+{"op": "replace", "path": "/metadata/labels/{{$label}}", "value": "{{.NewVersion}}"},
 {{ end }}
 {"op": "replace", "path": "/metadata/name", "value": "{{.Data.Value "/metadata/name"}}-{{.NewVersion}}"},
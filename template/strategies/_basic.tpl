@@ -0,0 +1,4 @@
+[
+  {{ template "_basic-version" . }}
+  {{ template "_basic-remove" . }}
+]
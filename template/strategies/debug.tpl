@@ -0,0 +1,41 @@
+{{ failIfVariableDoesNotExist .Vars "language" -}}
+{{ failIfVariableDoesNotExist .Vars "port" -}}
+{{ if ne .Vars.language "java" }}{{ failIfVariableDoesNotExist .Vars "command" }}{{ end -}}
+{{ $c := .Data.ContainerIndex .Vars.container }}
+{{ $suspend := eq .Vars.suspend "true" }}
+{{ $continue := " --continue" }}{{ $waitForClient := "" }}
+{{ if $suspend }}{{ $continue = "" }}{{ $waitForClient = " --wait-for-client" }}{{ end }}
+[
+  {{ template "_basic-version" . }}
+
+  {{ if not (.Data.Has "/spec/template/spec/replicas") }}
+  {"op": "add", "path": "/spec/template/spec/replicas", "value": {}},
+  {{ end }}
+  {"op": "replace", "path": "/spec/template/spec/replicas", "value": "1"},
+  {{ if .Vars.image }}
+  {"op": "replace", "path": "/spec/template/spec/containers/{{$c}}/image", "value": "{{.Vars.image}}"},
+  {{ end }}
+  {{ if eq .Vars.language "java" }}
+  {{ if not (.Data.Has (print "/spec/template/spec/containers/" $c "/env")) }}
+  {"op": "add", "path": "/spec/template/spec/containers/{{$c}}/env", "value": []},
+  {{ end }}
+  {"op": "add", "path": "/spec/template/spec/containers/{{$c}}/env/-", "value": {
+    "name": "JAVA_TOOL_OPTIONS",
+    "value": "-agentlib:jdwp=transport=dt_socket,server=y,suspend={{ if $suspend }}y{{ else }}n{{ end }},address=127.0.0.1:{{.Vars.port}}"
+  }
+  },
+  {{ else }}
+  {{ if .Data.Has (print "/spec/template/spec/containers/" $c "/args") }}
+  {"op": "remove", "path": "/spec/template/spec/containers/{{$c}}/args"},
+  {{ end }}
+  {{ if eq .Vars.language "go" }}
+  {"op": "add", "path": "/spec/template/spec/containers/{{$c}}/command", "value": ["sh", "-c", {{ toJSON (print "set -- " .Vars.command "; program=$1; shift; exec dlv exec --headless --listen=127.0.0.1:" .Vars.port " --api-version=2 --accept-multiclient" $continue " \"$program\" -- \"$@\"") }}]},
+  {{ else if eq .Vars.language "python" }}
+  {"op": "add", "path": "/spec/template/spec/containers/{{$c}}/command", "value": ["sh", "-c", {{ toJSON (print "set -- " .Vars.command "; interpreter=$1; shift; exec \"$interpreter\" -m debugpy --listen 127.0.0.1:" .Vars.port $waitForClient " \"$@\"") }}]},
+  {{ else }}
+  {{ fail (print "unsupported language " .Vars.language ", expected one of go, java, python") }}
+  {{ end }}
+  {{ end }}
+
+  {{ template "_basic-remove" . }}
+]
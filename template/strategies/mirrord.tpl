@@ -0,0 +1,10 @@
+[
+  {{ template "_basic-version" . }}
+
+  {{ if not (.Data.Has "/spec/template/spec/replicas") }}
+  {"op": "add", "path": "/spec/template/spec/replicas", "value": {}},
+  {{ end }}
+  {"op": "replace", "path": "/spec/template/spec/replicas", "value": "1"},
+
+  {{ template "_basic-remove" . }}
+]
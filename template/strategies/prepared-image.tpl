@@ -6,7 +6,10 @@
   {"op": "add", "path": "/spec/template/spec/replicas", "value": {}},
   {{ end }}
   {"op": "replace", "path": "/spec/template/spec/replicas", "value": "1"},
-  {"op": "replace", "path": "/spec/template/spec/containers/0/image", "value": "{{.Vars.image}}"},
+  {{ range $name, $image := parseImages .Vars.image }}
+  {{ $c := $.Data.ContainerIndex (or $name $.Vars.container) }}
+  {"op": "replace", "path": "/spec/template/spec/containers/{{$c}}/image", "value": "{{$image}}"},
+  {{ end }}
 
   {{ template "_basic-remove" . }}
 ]
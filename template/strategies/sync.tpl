@@ -0,0 +1,23 @@
+{{ failIfVariableDoesNotExist .Vars "pidFile" -}}
+{{ $c := .Data.ContainerIndex .Vars.container }}
+{{ $name := .Data.Value (print "/spec/template/spec/containers/" $c "/name") }}
+[
+  {{ template "_basic-version" . }}
+
+  {{ if not (.Data.Has "/spec/template/spec/replicas") }}
+  {"op": "add", "path": "/spec/template/spec/replicas", "value": {}},
+  {{ end }}
+  {"op": "replace", "path": "/spec/template/spec/replicas", "value": "1"},
+  {{ if not (.Data.Has "/spec/template/metadata/annotations") }}
+  {"op": "add", "path": "/spec/template/metadata/annotations", "value": {}},
+  {{ end }}
+  {"op": "add", "path": "/spec/template/metadata/annotations/{{ escapeJSONPointer "kubectl.kubernetes.io/default-container" }}", "value": "{{ $name }}"},
+  {{ if .Vars.command }}
+  {{ if .Data.Has (print "/spec/template/spec/containers/" $c "/args") }}
+  {"op": "remove", "path": "/spec/template/spec/containers/{{$c}}/args"},
+  {{ end }}
+  {"op": "add", "path": "/spec/template/spec/containers/{{$c}}/command", "value": ["sh", "-c", {{ toJSON (print "trap 'exit 0' TERM INT; while true; do " .Vars.command " & echo $! > " .Vars.pidFile "; wait $!; sleep 1; done") }}]},
+  {{ end }}
+
+  {{ template "_basic-remove" . }}
+]
@@ -1,5 +1,5 @@
 {{ failIfVariableDoesNotExist .Vars "version" -}}
-
+{{ $c := .Data.ContainerIndex .Vars.container }}
 [
   {{ template "_basic-version" . }}
 
@@ -8,11 +8,11 @@
   {{ end }}
   {"op": "replace", "path": "/spec/template/spec/replicas", "value": "1"},
   {"op": "add", "path": "/spec/template/metadata/labels/telepresence", "value": "test"},
-  {"op": "replace", "path": "/spec/template/spec/containers/0/image", "value": "datawire/telepresence-k8s:{{.Vars.version}}"},
-  {{ if not (.Data.Has "/spec/template/spec/containers/0/env") }}
-  {"op": "add", "path": "/spec/template/spec/containers/0/env", "value": []},
+  {"op": "replace", "path": "/spec/template/spec/containers/{{$c}}/image", "value": "datawire/telepresence-k8s:{{.Vars.version}}"},
+  {{ if not (.Data.Has (print "/spec/template/spec/containers/" $c "/env")) }}
+  {"op": "add", "path": "/spec/template/spec/containers/{{$c}}/env", "value": []},
   {{ end }}
-  {"op": "add", "path": "/spec/template/spec/containers/0/env/-", "value": {
+  {"op": "add", "path": "/spec/template/spec/containers/{{$c}}/env/-", "value": {
     "name": "TELEPRESENCE_CONTAINER_NAMESPACE",
     "valueFrom": {
       "fieldRef": {
@@ -22,11 +22,11 @@
     }
   }
   },
-  {{ if .Data.Has "/spec/template/spec/containers/0/args" }}
-  {"op": "remove", "path": "/spec/template/spec/containers/0/args"},
+  {{ if .Data.Has (print "/spec/template/spec/containers/" $c "/args") }}
+  {"op": "remove", "path": "/spec/template/spec/containers/{{$c}}/args"},
   {{ end }}
-  {{ if .Data.Has "/spec/template/spec/containers/0/command" }}
-  {"op": "remove", "path": "/spec/template/spec/containers/0/command"},
+  {{ if .Data.Has (print "/spec/template/spec/containers/" $c "/command") }}
+  {"op": "remove", "path": "/spec/template/spec/containers/{{$c}}/command"},
   {{ end }}
 
   {{ template "_basic-remove" . }}
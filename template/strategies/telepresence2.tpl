@@ -0,0 +1,15 @@
+[
+  {{ template "_basic-version" . }}
+
+  {{ if not (.Data.Has "/spec/template/spec/replicas") }}
+  {"op": "add", "path": "/spec/template/spec/replicas", "value": {}},
+  {{ end }}
+  {"op": "replace", "path": "/spec/template/spec/replicas", "value": "1"},
+  {"op": "add", "path": "/spec/template/metadata/labels/telepresence", "value": "intercept"},
+  {{ if not (.Data.Has "/spec/template/metadata/annotations") }}
+  {"op": "add", "path": "/spec/template/metadata/annotations", "value": {}},
+  {{ end }}
+  {"op": "add", "path": "/spec/template/metadata/annotations/telepresence.getambassador.io~1inject-traffic-agent", "value": "enabled"},
+
+  {{ template "_basic-remove" . }}
+]
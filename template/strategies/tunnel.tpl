@@ -0,0 +1,22 @@
+{{ failIfVariableDoesNotExist .Vars "image" -}}
+{{ failIfVariableDoesNotExist .Vars "token" -}}
+{{ $c := .Data.ContainerIndex (index .Vars "container") }}
+[
+  {{ template "_basic-version" . }}
+
+  {{ if not (.Data.Has "/spec/template/spec/replicas") }}
+  {"op": "add", "path": "/spec/template/spec/replicas", "value": {}},
+  {{ end }}
+  {"op": "replace", "path": "/spec/template/spec/replicas", "value": "1"},
+  {"op": "replace", "path": "/spec/template/spec/containers/{{$c}}/image", "value": "{{.Vars.image}}"},
+  {{ if .Data.Has (print "/spec/template/spec/containers/" $c "/args") }}
+  {"op": "remove", "path": "/spec/template/spec/containers/{{$c}}/args"},
+  {{ end }}
+  {{ if not (.Data.Has (print "/spec/template/spec/containers/" $c "/env")) }}
+  {"op": "add", "path": "/spec/template/spec/containers/{{$c}}/env", "value": []},
+  {{ end }}
+  {"op": "add", "path": "/spec/template/spec/containers/{{$c}}/env/-", "value": {"name": "IKE_TUNNEL_TOKEN", "value": "{{.Vars.token}}"}},
+  {"op": "add", "path": "/spec/template/spec/containers/{{$c}}/command", "value": ["ike", "agent", "--control-port", "{{.Vars.controlPort}}"]},
+
+  {{ template "_basic-remove" . }}
+]
@@ -1,12 +1,7 @@
 package main
 
-var propagationHeaders = []string{
-	"x-request-id",
-	"x-b3-traceid",
-	"x-b3-spanid",
-	"x-b3-parentspanid",
-	"x-b3-sampled",
-	"x-b3-flags",
-	"x-ot-span-context",
-	"x-test-suite",
-	"x-workspace-route"}
+import (
+	"github.com/maistra/istio-workspace/pkg/routing"
+)
+
+var propagationHeaders = append(append([]string{}, routing.PropagatedHeaders...), "x-test-suite")
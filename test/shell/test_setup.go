@@ -14,6 +14,7 @@ var (
 	MvnBin       string
 	TpSleepBin   string
 	TpVersionBin string
+	MirrordBin   string
 	JavaBin      string
 )
 
@@ -26,6 +27,8 @@ func StubShellCommands() {
 	TpVersionBin = buildBinary("github.com/maistra/istio-workspace/test/echo", "telepresence", "-ldflags", "-w -X main.Echo=0.234")
 	TpSleepBin = buildBinary("github.com/maistra/istio-workspace/test/echo",
 		"telepresence", "-ldflags", "-w -X main.SleepMs=256")
+	MirrordBin = buildBinary("github.com/maistra/istio-workspace/test/echo",
+		"mirrord", "-ldflags", "-w -X main.SleepMs=256")
 }
 
 func ExecuteCommand(outputChan chan string, execute func() (string, error)) func() {